@@ -70,6 +70,11 @@ type Agent interface {
 	// or the call times out).
 	Submit(Call) error
 
+	// CancelCall cancels a call that is being submitted to the Agent, which
+	// then ends with models.ErrCallCancelled. models.ErrCallNotFound is
+	// returned if the Agent is not running the call.
+	CancelCall(ctx context.Context, callID string) error
+
 	// Close will wait for any outstanding calls to complete and then exit.
 	// Closing the agent will invoke Close on the underlying DataAccess.
 	// Close is not safe to be called from multiple threads.
@@ -93,12 +98,22 @@ type agent struct {
 	shutWg   *common.WaitGroup
 	shutonce sync.Once
 
+	// calls being run, by id
+	running runningCalls
+
 	// TODO(reed): shoot this fucking thing
 	callOverrider CallOverrider
 
 	// additional options to configure each call
 	callOpts []CallOpt
 
+	// records the outcome and stderr of each call, if set
+	callStore models.CallStore
+	logStore  models.LogStore
+
+	// decrypts the secret config of calls, if set
+	secrets models.SecretStore
+
 	// deferred actions to call at end of initialisation
 	onStartup []func()
 }
@@ -197,6 +212,30 @@ func WithCallOptions(opts ...CallOpt) Option {
 	}
 }
 
+// WithCallStore records the outcome of every call run by the agent in cs
+func WithCallStore(cs models.CallStore) Option {
+	return func(a *agent) error {
+		a.callStore = cs
+		return nil
+	}
+}
+
+// WithLogStore stores the (size limited) stderr of every call run by the agent in ls
+func WithLogStore(ls models.LogStore) Option {
+	return func(a *agent) error {
+		a.logStore = ls
+		return nil
+	}
+}
+
+// WithSecretStore decrypts the secret config of apps and fns with ss as calls are made to them
+func WithSecretStore(ss models.SecretStore) Option {
+	return func(a *agent) error {
+		a.secrets = ss
+		return nil
+	}
+}
+
 // NewDockerDriver creates a default docker driver from agent config
 func NewDockerDriver(cfg *Config) (drivers.Driver, error) {
 	return drivers.New("docker", drivers.Config{
@@ -263,9 +302,20 @@ func (a *agent) Submit(callI Call) error {
 		)
 	}
 
+	ctx, done := a.running.track(ctx, call)
+	defer done()
+
 	return a.submit(ctx, call)
 }
 
+// implements Agent
+func (a *agent) CancelCall(ctx context.Context, callID string) error {
+	if !a.running.cancel(callID) {
+		return models.ErrCallNotFound
+	}
+	return nil
+}
+
 func (a *agent) startStateTrackers(ctx context.Context, call *call) {
 	call.requestState = NewRequestState()
 }
@@ -316,6 +366,11 @@ func (a *agent) handleCallEnd(ctx context.Context, call *call, slot Slot, err er
 		slot.Close()
 	}
 
+	// whatever error cancelling the call caused, report it as cancelled
+	if err != nil && call.isCancelled() {
+		err = models.ErrCallCancelled
+	}
+
 	// This means call was routed (executed)
 	if isStarted {
 		call.End(ctx, err)
@@ -332,9 +387,12 @@ func (a *agent) handleCallEnd(ctx context.Context, call *call, slot Slot, err er
 			statsTooBusy(ctx)
 			return models.ErrCallTimeoutServerBusy
 		}
+		if err == models.ErrCallCancelled {
+			call.recordCancelled(ctx)
+		}
 	}
 
-	if err == context.Canceled {
+	if err == context.Canceled || err == models.ErrCallCancelled {
 		statsCanceled(ctx)
 	} else if err != nil {
 		statsErrors(ctx)
@@ -1166,6 +1224,7 @@ type container struct {
 	tmpFsSize      uint64
 	disableNet     bool
 	iofs           iofs
+	secretFiles    iofs
 	logCfg         drivers.LoggerConfig
 	close          func()
 	beforeCall     drivers.BeforeCall
@@ -1190,17 +1249,29 @@ var _ drivers.ContainerTask = &container{}
 // newHotContainer creates a container that can be used for multiple sequential events
 func newHotContainer(ctx context.Context, evictor Evictor, caller *slotCaller, call *call, cfg *Config, id, authToken string, udsWait chan error) *container {
 
+	var secretFiles iofs = &noopIOFS{}
 	var iofs iofs
 	var err error
 
 	logger := common.Logger(ctx)
 
+	if len(call.SecretFiles) != 0 {
+		secretFiles, err = newSecretFiles(ctx, cfg, call.SecretFiles)
+		if err != nil {
+			udsWait <- err
+			return nil
+		}
+	}
+
 	if cfg.IOFSEnableTmpfs {
 		iofs, err = newTmpfsIOFS(ctx, cfg)
 	} else {
 		iofs, err = newDirectoryIOFS(ctx, cfg)
 	}
 	if err != nil {
+		if err := secretFiles.Close(); err != nil {
+			logger.WithError(err).Error("Error closing secret files")
+		}
 		udsWait <- err
 		return nil
 	}
@@ -1270,6 +1341,7 @@ func newHotContainer(ctx context.Context, evictor Evictor, caller *slotCaller, c
 		tmpFsSize:      uint64(call.TmpFsSize),
 		disableNet:     call.disableNet,
 		iofs:           iofs,
+		secretFiles:    secretFiles,
 		dockerAuth:     call.dockerAuth,
 		authToken:      authToken,
 		logCfg: drivers.LoggerConfig{
@@ -1300,6 +1372,9 @@ func newHotContainer(ctx context.Context, evictor Evictor, caller *slotCaller, c
 			if err := iofs.Close(); err != nil {
 				logger.WithError(err).Error("Error closing IOFS")
 			}
+			if err := secretFiles.Close(); err != nil {
+				logger.WithError(err).Error("Error closing secret files")
+			}
 			baseTransport.CloseIdleConnections()
 		},
 	}
@@ -1361,6 +1436,8 @@ func (c *container) LoggerConfig() drivers.LoggerConfig { return c.logCfg }
 func (c *container) UDSAgentPath() string               { return c.iofs.AgentPath() }
 func (c *container) UDSDockerPath() string              { return c.iofs.DockerPath() }
 func (c *container) UDSDockerDest() string              { return iofsDockerMountDest }
+func (c *container) SecretFilesDockerPath() string      { return c.secretFiles.DockerPath() }
+func (c *container) SecretFilesDockerDest() string      { return secretFilesDockerMountDest }
 func (c *container) DisableNet() bool                   { return c.disableNet }
 
 // WriteStat publishes each metric in the specified Stats structure as a histogram metric
@@ -698,6 +698,32 @@ func TestGetCallReturnsResourceImpossibility(t *testing.T) {
 	}
 }
 
+func TestGetCallReturnsTmpFsSizeTooBig(t *testing.T) {
+	call := &models.Call{
+		AppID:       id.New().String(),
+		FnID:        id.New().String(),
+		Image:       "fnproject/fn-test-utils",
+		Type:        "sync",
+		Timeout:     1,
+		IdleTimeout: 2,
+		Memory:      64,
+		TmpFsSize:   16,
+	}
+
+	cfg, err := NewConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.MaxFsSize = 8
+	a := New(WithConfig(cfg))
+	defer checkClose(t, a)
+
+	_, err = a.GetCall(FromModel(call))
+	if models.GetAPIErrorCode(err) != http.StatusBadRequest || !strings.Contains(err.Error(), "maximum file system size of 8 MB") {
+		t.Fatal("did not get expected err, got: ", err)
+	}
+}
+
 //
 // Tmp directory should be RW by default.
 //
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/fnproject/fn/api/agent/drivers/docker"
@@ -51,6 +52,15 @@ func FromHTTPFnRequest(app *models.App, fn *models.Fn, req *http.Request) CallOp
 	return func(c *call) error {
 		id := id.New().String()
 
+		// TODO - this wasn't really the intention here (that annotations would naturally cascade
+		// but seems to be necessary for some runner behaviour
+		annotations := app.Annotations.MergeChange(fn.Annotations)
+
+		conf, files, err := buildConfig(req.Context(), c.secrets, app, fn, annotations)
+		if err != nil {
+			return err
+		}
+
 		var syslogURL string
 		if app.SyslogURL != nil {
 			syslogURL = *app.SyslogURL
@@ -63,13 +73,12 @@ func FromHTTPFnRequest(app *models.App, fn *models.Fn, req *http.Request) CallOp
 			Type:        models.TypeSync,
 			Timeout:     fn.Timeout,
 			IdleTimeout: fn.IdleTimeout,
-			TmpFsSize:   0, // TODO clean up this
+			TmpFsSize:   fn.TmpFsSize,
 			Memory:      fn.Memory,
-			CPUs:        0, // TODO clean up this
-			Config:      buildConfig(app, fn),
-			// TODO - this wasn't really the intention here (that annotations would naturally cascade
-			// but seems to be necessary for some runner behaviour
-			Annotations: app.Annotations.MergeChange(fn.Annotations),
+			CPUs:        fn.CPUs,
+			Config:      conf,
+			SecretFiles: files,
+			Annotations: annotations,
 			Headers:     req.Header,
 			CreatedAt:   common.DateTime(time.Now()),
 			URL:         reqURL(req),
@@ -85,23 +94,71 @@ func FromHTTPFnRequest(app *models.App, fn *models.Fn, req *http.Request) CallOp
 	}
 }
 
-func buildConfig(app *models.App, fn *models.Fn) models.Config {
-	conf := make(models.Config, 8+len(app.Config)+len(fn.Config))
+// buildConfig merges the config of fn over that of app, decrypting the values
+// of their secret config with secrets. Secret entries take precedence over
+// plain ones of the same level. The secret entries that annotations mount as
+// files are returned apart from the rest of the config, as those files.
+func buildConfig(ctx context.Context, secrets models.SecretStore, app *models.App, fn *models.Fn, annotations models.Annotations) (conf, files models.Config, err error) {
+	mounted, err := models.SecretFiles(annotations)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conf = make(models.Config, 8+len(app.Config)+len(app.SecretConfig)+len(fn.Config)+len(fn.SecretConfig))
+	files = make(models.Config, len(mounted))
 	for k, v := range app.Config {
 		conf[k] = v
 	}
+	if err := openSecretConfig(ctx, secrets, conf, files, mounted, app.SecretConfig); err != nil {
+		return nil, nil, err
+	}
 	for k, v := range fn.Config {
 		conf[k] = v
+		delete(files, k)
+	}
+	if err := openSecretConfig(ctx, secrets, conf, files, mounted, fn.SecretConfig); err != nil {
+		return nil, nil, err
 	}
 
 	// XXX(reed): add trigger id to request headers on call?
 
 	conf["FN_MEMORY"] = fmt.Sprintf("%d", fn.Memory)
+	if fn.CPUs != 0 {
+		conf["FN_CPUS"] = fn.CPUs.String()
+	}
 	conf["FN_TYPE"] = "sync"
 	conf["FN_FN_ID"] = fn.ID
 	conf["FN_APP_ID"] = app.ID
 
-	return conf
+	if len(files) == 0 {
+		files = nil
+	}
+	return conf, files, nil
+}
+
+// openSecretConfig decrypts the values of sealed into files if they are
+// mounted, into conf otherwise, so that an entry is in one of them only
+func openSecretConfig(ctx context.Context, secrets models.SecretStore, conf, files models.Config, mounted map[string]bool, sealed models.Config) error {
+	if len(sealed) == 0 {
+		return nil
+	}
+	if secrets == nil {
+		return models.ErrSecretsNotSupported
+	}
+	for k, v := range sealed {
+		plaintext, err := secrets.Decrypt(ctx, v)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt secret config %s: %v", k, err)
+		}
+		if mounted[k] {
+			files[k] = plaintext
+			delete(conf, k)
+		} else {
+			conf[k] = plaintext
+			delete(files, k)
+		}
+	}
+	return nil
 }
 
 func reqURL(req *http.Request) string {
@@ -162,6 +219,18 @@ func WithTrigger(t *models.Trigger) CallOpt {
 	}
 }
 
+// WithQueuedCall keeps the id, creation time and trigger of a call that was
+// queued before it was run, so it can be found by the id handed out when it
+// was queued. It must come after the option that sets up the call.
+func WithQueuedCall(queued *models.Call) CallOpt {
+	return func(c *call) error {
+		c.ID = queued.ID
+		c.CreatedAt = queued.CreatedAt
+		c.TriggerID = queued.TriggerID
+		return nil
+	}
+}
+
 // WithWriter sets the writer that the call uses to send its output message to
 // TODO this should be required
 func WithWriter(w io.Writer) CallOpt {
@@ -215,6 +284,7 @@ func WithDockerAuth(auth docker.Auther) CallOpt {
 // GetCall builds a Call that can be used to submit jobs to the agent.
 func (a *agent) GetCall(opts ...CallOpt) (Call, error) {
 	var c call
+	c.secrets = a.secrets
 
 	// add additional agent options after any call specific options
 	// NOTE(reed): this policy is open to being the opposite way around, have at it,
@@ -243,6 +313,14 @@ func (a *agent) GetCall(opts ...CallOpt) (Call, error) {
 		c.extensions = ext
 	}
 
+	// tmpfs is backed by memory so it is reserved along with it below. it may
+	// not exceed the fs size limit of this agent, which fns can't know of, so
+	// the limit is in the error. its inodes are not up to the fn, the driver
+	// caps them for every container if MaxTmpFsInodes is set.
+	if a.cfg.MaxFsSize != 0 && uint64(c.TmpFsSize) > a.cfg.MaxFsSize {
+		return nil, models.NewAPIError(http.StatusBadRequest,
+			fmt.Errorf("Requested tmpfs_size of %d MB exceeds the maximum file system size of %d MB", c.TmpFsSize, a.cfg.MaxFsSize))
+	}
 	mem := c.Memory + uint64(c.TmpFsSize)
 	if !a.resources.IsResourcePossible(mem, c.CPUs) {
 		return nil, models.ErrCallResourceTooBig
@@ -258,6 +336,8 @@ func (a *agent) GetCall(opts ...CallOpt) (Call, error) {
 	setupCtx(&c)
 
 	c.ct = a
+	c.callStore = a.callStore
+	c.logStore = a.logStore
 	if c.stderr == nil {
 		// TODO(reed): is line writer is vulnerable to attack?
 		// XXX(reed): forcing this as default is not great / configuring it isn't great either. reconsider.
@@ -285,11 +365,14 @@ type call struct {
 	req          *http.Request
 	stderr       io.ReadWriteCloser
 	ct           callTrigger
+	callStore    models.CallStore
+	logStore     models.LogStore
 	slots        *slotQueue
 	requestState RequestState
 	slotHashId   string
 	disableNet   bool
 	dockerAuth   docker.Auther // pull config function
+	secrets      models.SecretStore
 
 	// amount of time attributed to user-code execution
 	userExecTime *time.Duration
@@ -308,6 +391,9 @@ type call struct {
 
 	// LB & Pure Runner Extra Config
 	extensions map[string]string
+
+	// set once the call is cancelled, accessed atomically
+	cancelled uint32
 }
 
 // SlotHashId returns a string identity for this call that can be used to uniquely place the call in a given container
@@ -381,6 +467,9 @@ func (c *call) End(ctx context.Context, errIn error) error {
 		c.Status = "success"
 	case context.DeadlineExceeded:
 		c.Status = "timeout"
+	case models.ErrCallCancelled:
+		c.Status = "cancelled"
+		c.Error = errIn.Error()
 	default:
 		c.Status = "error"
 		c.Error = errIn.Error()
@@ -389,6 +478,19 @@ func (c *call) End(ctx context.Context, errIn error) error {
 	// ensure stats histogram is reasonably bounded
 	c.Call.Stats = stats.Decimate(240, c.Call.Stats)
 
+	// don't let a cancelled request lose the record of its call, and don't
+	// fail the call because of the history stores either
+	if c.callStore != nil {
+		if err := c.callStore.InsertCall(common.BackgroundContext(ctx), c.Model()); err != nil {
+			common.Logger(ctx).WithError(err).Error("error inserting call into call store")
+		}
+	}
+	if c.logStore != nil {
+		if err := c.logStore.InsertLog(common.BackgroundContext(ctx), c.Model(), c.stderr); err != nil {
+			common.Logger(ctx).WithError(err).Error("error inserting log into log store")
+		}
+	}
+
 	// NOTE call this after InsertLog or the buffer will get reset
 	c.stderr.Close()
 
@@ -398,6 +500,30 @@ func (c *call) End(ctx context.Context, errIn error) error {
 	return errIn // original error, important for use in sync call returns
 }
 
+// recordCancelled records a call that was cancelled before it started, End
+// records the calls that did start
+func (c *call) recordCancelled(ctx context.Context) {
+	c.CompletedAt = common.DateTime(time.Now())
+	c.Status = "cancelled"
+	c.Error = models.ErrCallCancelled.Error()
+
+	if c.callStore != nil {
+		if err := c.callStore.InsertCall(common.BackgroundContext(ctx), c.Model()); err != nil {
+			common.Logger(ctx).WithError(err).Error("error inserting call into call store")
+		}
+	}
+}
+
+// markCancelled notes that the call is being cancelled, so that it ends with
+// models.ErrCallCancelled whatever error cancelling it causes
+func (c *call) markCancelled() {
+	atomic.StoreUint32(&c.cancelled, 1)
+}
+
+func (c *call) isCancelled() bool {
+	return atomic.LoadUint32(&c.cancelled) == 1
+}
+
 func GetCallLatencies(c *call) (time.Duration, time.Duration) {
 	var schedDuration time.Duration
 	var execDuration time.Duration
@@ -0,0 +1,252 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fnproject/fn/api/callstore"
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/id"
+	"github.com/fnproject/fn/api/logs"
+	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/api/secrets"
+)
+
+func TestCallEndRecordsCallAndLog(t *testing.T) {
+	ctx := context.Background()
+	cs := callstore.NewMock()
+	ls := logs.NewMock()
+
+	model := &models.Call{ID: id.New().String(), FnID: id.New().String(), AppID: id.New().String()}
+	c := &call{
+		Call:      model,
+		ct:        &lbAgent{},
+		callStore: cs,
+		logStore:  ls,
+		stderr:    setupLogger(ctx, 1024, false, model),
+	}
+	c.stderr.Write([]byte("something went wrong\n"))
+
+	expectedErr := errors.New("boom")
+	if err := c.End(ctx, expectedErr); err != expectedErr {
+		t.Fatalf("expected End to return the call's error, got %v", err)
+	}
+
+	stored, err := cs.GetCall(ctx, model.FnID, model.ID)
+	if err != nil {
+		t.Fatalf("call was not recorded: %v", err)
+	}
+	if stored.Status != "error" || stored.Error != expectedErr.Error() {
+		t.Fatalf("expected errored call to be recorded, got %+v", stored)
+	}
+
+	r, err := ls.GetLog(ctx, model.FnID, model.ID)
+	if err != nil {
+		t.Fatalf("log was not recorded: %v", err)
+	}
+	b, _ := ioutil.ReadAll(r)
+	if string(b) != "something went wrong\n" {
+		t.Fatalf("expected stderr to be recorded, got %q", b)
+	}
+}
+
+func TestLBCallEndRecordsCall(t *testing.T) {
+	cs := callstore.NewMock()
+	a, err := NewLBAgent(nil, nil, WithLBCallStore(cs))
+	if err != nil {
+		t.Fatalf("Unexpected error in creating LB Agent, %s", err.Error())
+	}
+
+	c, err := a.GetCall(SetCallType(models.TypeSync), func(c *call) error {
+		c.ID = id.New().String()
+		c.FnID = id.New().String()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error calling GetCall, %s", err.Error())
+	}
+
+	ctx := context.Background()
+	c.(*call).End(ctx, nil)
+
+	stored, err := cs.GetCall(ctx, c.Model().FnID, c.Model().ID)
+	if err != nil {
+		t.Fatalf("call was not recorded: %v", err)
+	}
+	if stored.Status != "success" {
+		t.Fatalf("expected successful call to be recorded, got %s", stored.Status)
+	}
+}
+
+func TestFromHTTPFnRequestCPUs(t *testing.T) {
+	app := &models.App{ID: id.New().String(), Name: "app"}
+	fn := &models.Fn{ID: id.New().String(), AppID: app.ID, ResourceConfig: models.ResourceConfig{Memory: 128, CPUs: 250}}
+	req, _ := http.NewRequest("POST", "http://www.example.com/invoke/"+fn.ID, nil)
+
+	var c call
+	if err := FromHTTPFnRequest(app, fn, req)(&c); err != nil {
+		t.Fatalf("Unexpected error building call, %s", err.Error())
+	}
+	if c.CPUs != 250 {
+		t.Fatalf("expected call cpus to be 250m, got %v", c.CPUs)
+	}
+	if c.Config["FN_CPUS"] != "250m" {
+		t.Fatalf("expected FN_CPUS to be 250m, got %q", c.Config["FN_CPUS"])
+	}
+}
+
+func TestFromHTTPFnRequestTmpFsSize(t *testing.T) {
+	app := &models.App{ID: id.New().String(), Name: "app"}
+	fn := &models.Fn{ID: id.New().String(), AppID: app.ID, ResourceConfig: models.ResourceConfig{Memory: 128, TmpFsSize: 32}}
+	req, _ := http.NewRequest("POST", "http://www.example.com/invoke/"+fn.ID, nil)
+
+	var c call
+	if err := FromHTTPFnRequest(app, fn, req)(&c); err != nil {
+		t.Fatalf("Unexpected error building call, %s", err.Error())
+	}
+	if c.TmpFsSize != 32 {
+		t.Fatalf("expected call tmpfs_size to be 32, got %v", c.TmpFsSize)
+	}
+}
+
+func TestWithQueuedCall(t *testing.T) {
+	app := &models.App{ID: id.New().String(), Name: "app"}
+	fn := &models.Fn{ID: id.New().String(), AppID: app.ID, ResourceConfig: models.ResourceConfig{Memory: 128}}
+	req, _ := http.NewRequest("POST", "http://www.example.com/invoke/"+fn.ID, nil)
+	queued := &models.Call{
+		ID:        id.New().String(),
+		TriggerID: id.New().String(),
+		CreatedAt: common.DateTime(time.Now().Add(-time.Minute)),
+	}
+
+	var c call
+	for _, opt := range []CallOpt{FromHTTPFnRequest(app, fn, req), WithQueuedCall(queued)} {
+		if err := opt(&c); err != nil {
+			t.Fatalf("Unexpected error building call, %s", err.Error())
+		}
+	}
+	if c.ID != queued.ID || c.TriggerID != queued.TriggerID {
+		t.Fatalf("expected call to keep the id and trigger of the queued call, got %s %s", c.ID, c.TriggerID)
+	}
+	if !time.Time(c.CreatedAt).Equal(time.Time(queued.CreatedAt)) {
+		t.Fatalf("expected call to keep the creation time of the queued call, got %v", c.CreatedAt)
+	}
+}
+
+func TestFromHTTPFnRequestSecretConfig(t *testing.T) {
+	ctx := context.Background()
+	ss, err := secrets.NewLocal(bytes.Repeat([]byte{1}, secrets.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	seal := func(v string) string {
+		sealed, err := ss.Encrypt(ctx, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sealed
+	}
+
+	app := &models.App{ID: id.New().String(), Name: "app",
+		Config:       models.Config{"DB_USER": "app", "DB_HOST": "db"},
+		SecretConfig: models.Config{"DB_PASSWORD": seal("app-password"), "DB_USER": seal("secret-app")},
+	}
+	fn := &models.Fn{ID: id.New().String(), AppID: app.ID, ResourceConfig: models.ResourceConfig{Memory: 128},
+		Config:       models.Config{"DB_PASSWORD": "fn-plain"},
+		SecretConfig: models.Config{"API_KEY": seal("fn-key")},
+	}
+	req, _ := http.NewRequest("POST", "http://www.example.com/invoke/"+fn.ID, nil)
+
+	a, err := NewLBAgent(nil, nil, WithLBSecretStore(ss))
+	if err != nil {
+		t.Fatalf("Unexpected error in creating LB Agent, %s", err.Error())
+	}
+	c, err := a.GetCall(FromHTTPFnRequest(app, fn, req))
+	if err != nil {
+		t.Fatalf("Unexpected error building call, %s", err.Error())
+	}
+	for k, expected := range map[string]string{
+		"DB_HOST":     "db",
+		"DB_USER":     "secret-app",
+		"DB_PASSWORD": "fn-plain",
+		"API_KEY":     "fn-key",
+	} {
+		if got := c.Model().Config[k]; got != expected {
+			t.Errorf("expected config %s to be %q, got %q", k, expected, got)
+		}
+	}
+
+	// without the store, or with another key, calls with secrets can't be made
+	a, err = NewLBAgent(nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error in creating LB Agent, %s", err.Error())
+	}
+	if _, err := a.GetCall(FromHTTPFnRequest(app, fn, req)); err != models.ErrSecretsNotSupported {
+		t.Errorf("expected %v without a secret store, got %v", models.ErrSecretsNotSupported, err)
+	}
+	other, err := secrets.NewLocal(bytes.Repeat([]byte{2}, secrets.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err = NewLBAgent(nil, nil, WithLBSecretStore(other))
+	if err != nil {
+		t.Fatalf("Unexpected error in creating LB Agent, %s", err.Error())
+	}
+	if _, err := a.GetCall(FromHTTPFnRequest(app, fn, req)); err == nil {
+		t.Error("expected secrets sealed with another key not to be opened")
+	}
+}
+
+func TestFromHTTPFnRequestSecretFiles(t *testing.T) {
+	ctx := context.Background()
+	ss, err := secrets.NewLocal(bytes.Repeat([]byte{1}, secrets.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	seal := func(v string) string {
+		sealed, err := ss.Encrypt(ctx, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sealed
+	}
+
+	app := &models.App{ID: id.New().String(), Name: "app",
+		SecretConfig: models.Config{"DB_PASSWORD": seal("app-password"), "tls.key": seal("app-key")},
+	}
+	fn := &models.Fn{ID: id.New().String(), AppID: app.ID, ResourceConfig: models.ResourceConfig{Memory: 128},
+		SecretConfig: models.Config{"API_KEY": seal("fn-key")},
+	}
+	app.Annotations, _ = models.Annotations{}.With(models.SecretFilesAnnotation, []string{"DB_PASSWORD"})
+	fn.Annotations, _ = models.Annotations{}.With(models.SecretFilesAnnotation, []string{"tls.key", "API_KEY", "MISSING"})
+	req, _ := http.NewRequest("POST", "http://www.example.com/invoke/"+fn.ID, nil)
+
+	a, err := NewLBAgent(nil, nil, WithLBSecretStore(ss))
+	if err != nil {
+		t.Fatalf("Unexpected error in creating LB Agent, %s", err.Error())
+	}
+	c, err := a.GetCall(FromHTTPFnRequest(app, fn, req))
+	if err != nil {
+		t.Fatalf("Unexpected error building call, %s", err.Error())
+	}
+
+	// the annotation of the fn replaces that of the app
+	files := c.Model().SecretFiles
+	if len(files) != 2 || files["tls.key"] != "app-key" || files["API_KEY"] != "fn-key" {
+		t.Errorf("expected the secrets the fn designates to be files, got %v", files)
+	}
+	conf := c.Model().Config
+	if conf["DB_PASSWORD"] != "app-password" {
+		t.Errorf("expected the secrets the fn doesn't designate to be config, got %v", conf)
+	}
+	for name := range files {
+		if _, ok := conf[name]; ok {
+			t.Errorf("expected secret file %s not to be in the config too", name)
+		}
+	}
+}
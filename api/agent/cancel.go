@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"context"
+	"sync"
+)
+
+// runningCalls tracks the calls an agent is running by id, so that they can
+// be cancelled.
+type runningCalls struct {
+	lock  sync.Mutex
+	calls map[string]runningCall
+}
+
+type runningCall struct {
+	call   *call
+	cancel context.CancelFunc
+}
+
+// track returns a context for running c that is cancelled if c is, and a
+// func to call once c has finished running
+func (rc *runningCalls) track(ctx context.Context, c *call) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	rc.lock.Lock()
+	if rc.calls == nil {
+		rc.calls = make(map[string]runningCall)
+	}
+	rc.calls[c.ID] = runningCall{call: c, cancel: cancel}
+	rc.lock.Unlock()
+
+	return ctx, func() {
+		rc.lock.Lock()
+		delete(rc.calls, c.ID)
+		rc.lock.Unlock()
+		cancel()
+	}
+}
+
+// cancel cancels the call with the given id, returning false if it isn't running
+func (rc *runningCalls) cancel(callID string) bool {
+	rc.lock.Lock()
+	running, ok := rc.calls[callID]
+	rc.lock.Unlock()
+	if !ok {
+		return false
+	}
+
+	running.call.markCancelled()
+	running.cancel()
+	return true
+}
@@ -0,0 +1,106 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/fnproject/fn/api/agent/grpc"
+	"github.com/fnproject/fn/api/models"
+	pool "github.com/fnproject/fn/api/runnerpool"
+)
+
+func TestRunningCallsCancel(t *testing.T) {
+	var running runningCalls
+	c := &call{Call: &models.Call{ID: "call-id"}}
+
+	ctx, done := running.track(context.Background(), c)
+	if running.cancel("other-id") {
+		t.Fatal("expected a call that isn't running not to be cancelled")
+	}
+	if !running.cancel(c.ID) {
+		t.Fatal("expected running call to be cancelled")
+	}
+	if ctx.Err() != context.Canceled || !c.isCancelled() {
+		t.Fatal("expected the context of the cancelled call to be done and the call marked cancelled")
+	}
+
+	done()
+	if running.cancel(c.ID) {
+		t.Fatal("expected a call that finished running not to be cancelled")
+	}
+}
+
+func TestPureRunnerCancel(t *testing.T) {
+	pr := &pureRunner{callHandleMap: make(map[string]*callHandle)}
+
+	ch := &callHandle{c: &call{Call: &models.Call{ID: "call-id"}}}
+	ch.sctx, ch.scancel = context.WithCancel(context.Background())
+	pr.saveCallHandle(ch)
+
+	status, err := pr.Cancel(context.Background(), &pb.CancelMsg{CallId: "other-id"})
+	if err != nil || status.Cancelled {
+		t.Fatalf("expected a call that isn't running not to be cancelled, got %v %v", status, err)
+	}
+
+	status, err = pr.Cancel(context.Background(), &pb.CancelMsg{CallId: "call-id"})
+	if err != nil || !status.Cancelled {
+		t.Fatalf("expected running call to be cancelled, got %v %v", status, err)
+	}
+	if ch.sctx.Err() != context.Canceled || !ch.c.isCancelled() {
+		t.Fatal("expected the submit of the cancelled call to be cancelled")
+	}
+}
+
+// cancelRunner is a runner that is running a single call
+type cancelRunner struct {
+	*mockRunner
+	callID    string
+	cancelled bool
+}
+
+func (r *cancelRunner) Cancel(ctx context.Context, callID string) (bool, error) {
+	r.cancelled = r.cancelled || callID == r.callID
+	return callID == r.callID, nil
+}
+
+func TestLBAgentCancelCall(t *testing.T) {
+	runner := &cancelRunner{mockRunner: &mockRunner{addr: "192.0.2.1"}, callID: "remote-id"}
+	rp := &mockRunnerPool{runners: []pool.Runner{&mockRunner{addr: "192.0.2.0"}, runner}}
+	cfg := pool.NewPlacerConfig()
+	a, err := NewLBAgent(rp, pool.NewNaivePlacer(&cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err := a.CancelCall(ctx, "remote-id"); err != nil {
+		t.Fatalf("expected call on a runner to be cancelled, got %v", err)
+	}
+	if !runner.cancelled {
+		t.Fatal("expected the cancel to be sent to the runner running the call")
+	}
+
+	if err := a.CancelCall(ctx, "unknown-id"); err != models.ErrCallNotFound {
+		t.Fatalf("expected a call no runner has not to be found, got %v", err)
+	}
+
+	// calls still being placed are cancelled by the lb
+	c := &call{Call: &models.Call{ID: "placing-id"}}
+	placeCtx, done := a.(*lbAgent).running.track(ctx, c)
+	defer done()
+	if err := a.CancelCall(ctx, c.ID); err != nil {
+		t.Fatalf("expected call being placed to be cancelled, got %v", err)
+	}
+	if placeCtx.Err() != context.Canceled {
+		t.Fatal("expected the placement of the cancelled call to be cancelled")
+	}
+
+	// the error of a call cancelled on a runner is recognised as such
+	finished := &pb.CallFinished{
+		ErrorCode: int32(models.GetAPIErrorCode(models.ErrCallCancelled)),
+		ErrorStr:  models.ErrCallCancelled.Error(),
+	}
+	if err := parseError(finished); err != models.ErrCallCancelled {
+		t.Fatalf("expected the runner's error to be the cancellation, got %v", err)
+	}
+}
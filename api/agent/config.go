@@ -23,6 +23,8 @@ type Config struct {
 	HotStartTimeout               time.Duration `json:"hot_start_timeout_msecs"`
 	DetachedHeadRoom              time.Duration `json:"detached_head_room_msecs"`
 	MaxResponseSize               uint64        `json:"max_response_size_bytes"`
+	RequestSpoolThreshold         uint64        `json:"request_spool_threshold_bytes"`
+	RequestSpoolDir               string        `json:"request_spool_dir"`
 	MaxHdrResponseSize            uint64        `json:"max_hdr_response_size_bytes"`
 	MaxLogSize                    uint64        `json:"max_log_size_bytes"`
 	MaxTotalCPU                   uint64        `json:"max_total_cpu_mcpus"`
@@ -80,6 +82,10 @@ const (
 	EnvHotStartTimeout = "FN_HOT_START_TIMEOUT_MSECS"
 	// EnvMaxResponseSize is the maximum number of bytes that a function may return from an invocation
 	EnvMaxResponseSize = "FN_MAX_RESPONSE_SIZE"
+	// EnvRequestSpoolThreshold is the number of bytes of a request body the lb keeps in memory, beyond which it spools the body to a temp file
+	EnvRequestSpoolThreshold = "FN_REQUEST_SPOOL_THRESHOLD"
+	// EnvRequestSpoolDir is the directory request bodies are spooled to, the system temp dir by default
+	EnvRequestSpoolDir = "FN_REQUEST_SPOOL_DIR"
 	// EnvHdrMaxResponseSize is the maximum number of bytes that a function may return in an invocation header
 	EnvMaxHdrResponseSize = "FN_MAX_HDR_RESPONSE_SIZE"
 	// EnvMaxLogSize is the maximum size that a function's log may reach
@@ -175,6 +181,8 @@ func NewConfig() (*Config, error) {
 	err = setEnvMsecs(err, EnvHotStartTimeout, &cfg.HotStartTimeout, time.Duration(5)*time.Second)
 	err = setEnvMsecs(err, EnvDetachedHeadroom, &cfg.DetachedHeadRoom, time.Duration(360)*time.Second)
 	err = setEnvUint(err, EnvMaxResponseSize, &cfg.MaxResponseSize, nil)
+	err = setEnvUint(err, EnvRequestSpoolThreshold, &cfg.RequestSpoolThreshold, nil)
+	err = setEnvStr(err, EnvRequestSpoolDir, &cfg.RequestSpoolDir)
 	err = setEnvUint(err, EnvMaxHdrResponseSize, &cfg.MaxHdrResponseSize, nil)
 	err = setEnvUint(err, EnvMaxLogSize, &cfg.MaxLogSize, nil)
 	err = setEnvUint(err, EnvMaxTotalCPU, &cfg.MaxTotalCPU, nil)
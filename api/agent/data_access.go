@@ -19,6 +19,12 @@ type ReadDataAccess interface {
 	GetAppByID(ctx context.Context, appID string) (*models.App, error)
 	GetTriggerBySource(ctx context.Context, appID string, triggerType, source string) (*models.Trigger, error)
 	GetFnByID(ctx context.Context, fnID string) (*models.Fn, error)
+	// GetDomainByHostname gets the custom domain of an app requests to hostname are routed to,
+	// returning models.ErrDomainNotFound if hostname isn't the domain of any app.
+	GetDomainByHostname(ctx context.Context, hostname string) (*models.Domain, error)
+	// GetAliasByName gets the alias of an app with a name, to call the fn it points to,
+	// returning models.ErrAliasNotFound if the app has no such alias.
+	GetAliasByName(ctx context.Context, appID, name string) (*models.Alias, error)
 }
 
 // XXX(reed): replace all uses of ReadDataAccess with DataAccess or vice versa, whatever is easier
@@ -59,6 +65,18 @@ func (m *metricda) GetFnByID(ctx context.Context, fnID string) (*models.Fn, erro
 	return m.rda.GetFnByID(ctx, fnID)
 }
 
+func (m *metricda) GetDomainByHostname(ctx context.Context, hostname string) (*models.Domain, error) {
+	ctx, span := trace.StartSpan(ctx, "rda_get_domain_by_hostname")
+	defer span.End()
+	return m.rda.GetDomainByHostname(ctx, hostname)
+}
+
+func (m *metricda) GetAliasByName(ctx context.Context, appID, name string) (*models.Alias, error) {
+	ctx, span := trace.StartSpan(ctx, "rda_get_alias_by_name")
+	defer span.End()
+	return m.rda.GetAliasByName(ctx, appID, name)
+}
+
 // CachedDataAccess wraps a DataAccess and caches the results of GetApp.
 type cachedDataAccess struct {
 	ReadDataAccess
@@ -79,6 +97,10 @@ func NewCachedDataAccess(da ReadDataAccess) ReadDataAccess {
 func appIDCacheKey(appID string) string     { return "a:" + appID }
 func appNameCacheKey(appName string) string { return "n:" + appName }
 func fnCacheKey(fnID string) string         { return "f:" + fnID }
+func domainCacheKey(host string) string     { return "d:" + host }
+func aliasCacheKey(app, name string) string {
+	return "l:" + app + string('\x00') + name
+}
 func trigSourceCacheKey(app, typ, source string) string {
 	return "t:" + app + string('\x00') + typ + string('\x00') + source
 }
@@ -162,3 +184,52 @@ func (da *cachedDataAccess) GetFnByID(ctx context.Context, fnID string) (*models
 	da.cache.Set(key, fn, cache.DefaultExpiration)
 	return fn.(*models.Fn), nil
 }
+
+// GetDomainByHostname caches hostnames that aren't domains too, as every
+// request to the front end asks for the domain of its host
+func (da *cachedDataAccess) GetDomainByHostname(ctx context.Context, hostname string) (*models.Domain, error) {
+	key := domainCacheKey(hostname)
+	domain, ok := da.cache.Get(key)
+	if ok {
+		if domain.(*models.Domain) == nil {
+			return nil, models.ErrDomainNotFound
+		}
+		return domain.(*models.Domain), nil
+	}
+
+	resp, err := da.singleflight.Do(key,
+		func() (interface{}, error) {
+			return da.ReadDataAccess.GetDomainByHostname(ctx, hostname)
+		})
+
+	if err == models.ErrDomainNotFound {
+		da.cache.Set(key, (*models.Domain)(nil), cache.DefaultExpiration)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	domain = resp.(*models.Domain)
+	da.cache.Set(key, domain, cache.DefaultExpiration)
+	return domain.(*models.Domain), nil
+}
+
+func (da *cachedDataAccess) GetAliasByName(ctx context.Context, appID, name string) (*models.Alias, error) {
+	key := aliasCacheKey(appID, name)
+	alias, ok := da.cache.Get(key)
+	if ok {
+		return alias.(*models.Alias), nil
+	}
+
+	resp, err := da.singleflight.Do(key,
+		func() (interface{}, error) {
+			return da.ReadDataAccess.GetAliasByName(ctx, appID, name)
+		})
+
+	if err != nil {
+		return nil, err
+	}
+	alias = resp.(*models.Alias)
+	da.cache.Set(key, alias, cache.DefaultExpiration)
+	return alias.(*models.Alias), nil
+}
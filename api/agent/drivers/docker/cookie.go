@@ -201,6 +201,17 @@ func (c *cookie) configureIOFS(log logrus.FieldLogger) {
 	log.WithFields(logrus.Fields{"bind": bind, "call_id": c.task.Id()}).Debug("setting bind")
 }
 
+func (c *cookie) configureSecretFiles(log logrus.FieldLogger) {
+	path := c.task.SecretFilesDockerPath()
+	if path == "" {
+		return
+	}
+
+	bind := fmt.Sprintf("%s:%s:ro", path, c.task.SecretFilesDockerDest())
+	c.opts.HostConfig.Binds = append(c.opts.HostConfig.Binds, bind)
+	log.WithFields(logrus.Fields{"bind": bind, "call_id": c.task.Id()}).Debug("setting secret files bind")
+}
+
 func (c *cookie) configureVolumes(log logrus.FieldLogger) {
 	if len(c.task.Volumes()) == 0 {
 		return
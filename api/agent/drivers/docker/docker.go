@@ -411,6 +411,7 @@ func (drv *DockerDriver) CreateCookie(ctx context.Context, task drivers.Containe
 	cookie.configureVolumes(log)
 	cookie.configureWorkDir(log)
 	cookie.configureIOFS(log)
+	cookie.configureSecretFiles(log)
 	cookie.configureNetwork(log)
 	cookie.configureHostname(log)
 	cookie.configureImage(log)
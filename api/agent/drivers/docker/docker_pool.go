@@ -75,6 +75,8 @@ func (c *poolTask) WriteStat(ctx context.Context, stat stats.Stat) {}
 func (c *poolTask) UDSAgentPath() string                           { return "" }
 func (c *poolTask) UDSDockerPath() string                          { return "" }
 func (c *poolTask) UDSDockerDest() string                          { return "" }
+func (c *poolTask) SecretFilesDockerPath() string                  { return "" }
+func (c *poolTask) SecretFilesDockerDest() string                  { return "" }
 
 type dockerPoolItem struct {
 	id     string
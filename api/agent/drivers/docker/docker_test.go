@@ -55,10 +55,12 @@ func (f *taskDockerTest) Extensions() map[string]string
 func (f *taskDockerTest) LoggerConfig() drivers.LoggerConfig {
 	return drivers.LoggerConfig{URL: f.logURL}
 }
-func (f *taskDockerTest) UDSAgentPath() string  { return "" }
-func (f *taskDockerTest) UDSDockerPath() string { return "" }
-func (f *taskDockerTest) UDSDockerDest() string { return "" }
-func (f *taskDockerTest) DisableNet() bool      { return f.disableNet }
+func (f *taskDockerTest) UDSAgentPath() string          { return "" }
+func (f *taskDockerTest) UDSDockerPath() string         { return "" }
+func (f *taskDockerTest) UDSDockerDest() string         { return "" }
+func (f *taskDockerTest) SecretFilesDockerPath() string { return "" }
+func (f *taskDockerTest) SecretFilesDockerDest() string { return "" }
+func (f *taskDockerTest) DisableNet() bool              { return f.disableNet }
 
 func (f *taskDockerTest) BeforeCall(context.Context, *models.Call, drivers.CallExtensions) error {
 	return nil
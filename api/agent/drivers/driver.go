@@ -206,6 +206,15 @@ type ContainerTask interface {
 	// of the directory where the sock file resides inside of the container.
 	UDSDockerDest() string
 
+	// SecretFilesDockerPath is the directory holding the secret config entries
+	// of the container as files, relative to the docker host, or empty if
+	// the container has none.
+	SecretFilesDockerPath() string
+
+	// SecretFilesDockerDest is the path of the directory of secret files inside
+	// of the container, where it is mounted read only.
+	SecretFilesDockerDest() string
+
 	// Returns true if network is disabled.
 	DisableNet() bool
 
@@ -1323,6 +1323,85 @@ func (m *LogResponseMsg_Container_Request_Line) GetData() []byte {
 	return nil
 }
 
+// Request to cancel a call
+type CancelMsg struct {
+	CallId               string   `protobuf:"bytes,1,opt,name=call_id,json=callId,proto3" json:"call_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelMsg) Reset()         { *m = CancelMsg{} }
+func (m *CancelMsg) String() string { return proto.CompactTextString(m) }
+func (*CancelMsg) ProtoMessage()    {}
+func (*CancelMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_48eceea7e2abc593, []int{13}
+}
+
+func (m *CancelMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelMsg.Unmarshal(m, b)
+}
+func (m *CancelMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelMsg.Marshal(b, m, deterministic)
+}
+func (m *CancelMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelMsg.Merge(m, src)
+}
+func (m *CancelMsg) XXX_Size() int {
+	return xxx_messageInfo_CancelMsg.Size(m)
+}
+func (m *CancelMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelMsg proto.InternalMessageInfo
+
+func (m *CancelMsg) GetCallId() string {
+	if m != nil {
+		return m.CallId
+	}
+	return ""
+}
+
+type CancelStatus struct {
+	Cancelled            bool     `protobuf:"varint,1,opt,name=cancelled,proto3" json:"cancelled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelStatus) Reset()         { *m = CancelStatus{} }
+func (m *CancelStatus) String() string { return proto.CompactTextString(m) }
+func (*CancelStatus) ProtoMessage()    {}
+func (*CancelStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_48eceea7e2abc593, []int{14}
+}
+
+func (m *CancelStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelStatus.Unmarshal(m, b)
+}
+func (m *CancelStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelStatus.Marshal(b, m, deterministic)
+}
+func (m *CancelStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelStatus.Merge(m, src)
+}
+func (m *CancelStatus) XXX_Size() int {
+	return xxx_messageInfo_CancelStatus.Size(m)
+}
+func (m *CancelStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelStatus proto.InternalMessageInfo
+
+func (m *CancelStatus) GetCancelled() bool {
+	if m != nil {
+		return m.Cancelled
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterEnum("LogResponseMsg_Container_Request_Line_Source", LogResponseMsg_Container_Request_Line_Source_name, LogResponseMsg_Container_Request_Line_Source_value)
 	proto.RegisterType((*TryCall)(nil), "TryCall")
@@ -1348,95 +1427,100 @@ func init() {
 	proto.RegisterType((*LogResponseMsg_Container)(nil), "LogResponseMsg.Container")
 	proto.RegisterType((*LogResponseMsg_Container_Request)(nil), "LogResponseMsg.Container.Request")
 	proto.RegisterType((*LogResponseMsg_Container_Request_Line)(nil), "LogResponseMsg.Container.Request.Line")
+	proto.RegisterType((*CancelMsg)(nil), "CancelMsg")
+	proto.RegisterType((*CancelStatus)(nil), "CancelStatus")
 }
 
 func init() { proto.RegisterFile("runner.proto", fileDescriptor_48eceea7e2abc593) }
 
 var fileDescriptor_48eceea7e2abc593 = []byte{
-	// 1326 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0x4f, 0x8f, 0xdb, 0x44,
-	0x14, 0x5f, 0xc7, 0x89, 0x93, 0xbc, 0xfc, 0xdd, 0xa1, 0x5d, 0x8c, 0xa9, 0x68, 0x08, 0xa5, 0x8a,
-	0x60, 0xeb, 0xd2, 0xa5, 0x95, 0x4a, 0x25, 0x40, 0x25, 0xbb, 0x55, 0x8a, 0x5a, 0x5a, 0x4d, 0xb6,
-	0x70, 0x5c, 0xcd, 0xda, 0xb3, 0x89, 0x89, 0x63, 0x87, 0x99, 0xf1, 0xd2, 0x95, 0x38, 0x70, 0x83,
-	0xaf, 0xc0, 0x05, 0x89, 0x23, 0x77, 0x3e, 0x07, 0x47, 0x3e, 0x0a, 0x67, 0x34, 0x7f, 0xe2, 0xfc,
-	0xdb, 0x6e, 0xbb, 0x12, 0x37, 0xbf, 0xdf, 0xef, 0xcd, 0xbc, 0x37, 0xcf, 0xbf, 0xf7, 0x3c, 0x86,
-	0x3a, 0xcb, 0x92, 0x84, 0x32, 0x7f, 0xc6, 0x52, 0x91, 0x7a, 0xef, 0x8e, 0xd2, 0x74, 0x14, 0xd3,
-	0xdb, 0xca, 0x3a, 0xce, 0x4e, 0x6e, 0xd3, 0xe9, 0x4c, 0x9c, 0x19, 0xf2, 0xda, 0x3a, 0xc9, 0x05,
-	0xcb, 0x02, 0xa1, 0xd9, 0xee, 0xdf, 0x16, 0x94, 0x0f, 0xd9, 0x59, 0x9f, 0xc4, 0x31, 0xea, 0x41,
-	0x7b, 0x9a, 0x86, 0x34, 0xe6, 0x47, 0x01, 0x89, 0xe3, 0xa3, 0xef, 0x79, 0x9a, 0xb8, 0x56, 0xc7,
-	0xea, 0x55, 0x71, 0x53, 0xe3, 0xd2, 0xeb, 0x6b, 0x9e, 0x26, 0xa8, 0x03, 0x75, 0x1e, 0xa7, 0xe2,
-	0x68, 0x4c, 0xf8, 0xf8, 0x28, 0x0a, 0xdd, 0x82, 0xf2, 0x02, 0x89, 0x0d, 0x08, 0x1f, 0x3f, 0x0e,
-	0xd1, 0x7d, 0x00, 0xfa, 0x52, 0xd0, 0x84, 0x47, 0x69, 0xc2, 0x5d, 0xbb, 0x63, 0xf7, 0x6a, 0x7b,
-	0xae, 0x6f, 0x22, 0xf9, 0x07, 0x39, 0x75, 0x90, 0x08, 0x76, 0x86, 0x97, 0x7c, 0xbd, 0xcf, 0xa1,
-	0xb5, 0x46, 0xa3, 0x36, 0xd8, 0x13, 0x7a, 0x66, 0x72, 0x91, 0x8f, 0xe8, 0x0a, 0x94, 0x4e, 0x49,
-	0x9c, 0x51, 0x13, 0x59, 0x1b, 0x0f, 0x0a, 0xf7, 0xad, 0xee, 0x1d, 0xa8, 0xee, 0x13, 0x41, 0x1e,
-	0x31, 0x32, 0xa5, 0x08, 0x41, 0x31, 0x24, 0x82, 0xa8, 0x95, 0x75, 0xac, 0x9e, 0xe5, 0x66, 0x34,
-	0x3d, 0x51, 0x0b, 0x2b, 0x58, 0x3e, 0x76, 0xef, 0x02, 0x0c, 0x84, 0x98, 0x0d, 0x28, 0x09, 0x29,
-	0x7b, 0xd3, 0x60, 0xdd, 0x6f, 0xa1, 0x2e, 0x57, 0x61, 0xca, 0x67, 0x4f, 0xa9, 0x20, 0xe8, 0x3a,
-	0xd4, 0xb8, 0x20, 0x22, 0xe3, 0x47, 0x41, 0x1a, 0x52, 0xb5, 0xbe, 0x84, 0x41, 0x43, 0xfd, 0x34,
-	0xa4, 0xe8, 0x43, 0x28, 0x8f, 0x55, 0x08, 0xee, 0x16, 0x54, 0x3d, 0x6a, 0xfe, 0x22, 0x2c, 0x9e,
-	0x73, 0xdd, 0x2f, 0xa0, 0x25, 0x6b, 0x84, 0x29, 0xcf, 0x62, 0x31, 0x14, 0x84, 0x09, 0xf4, 0x01,
-	0x14, 0xc7, 0x42, 0xcc, 0xdc, 0xb0, 0x63, 0xf5, 0x6a, 0x7b, 0x0d, 0x7f, 0x39, 0xee, 0x60, 0x0b,
-	0x2b, 0xf2, 0x2b, 0x07, 0x8a, 0x53, 0x2a, 0x48, 0xf7, 0xf7, 0x22, 0xd4, 0xe5, 0x06, 0x8f, 0xa2,
-	0x24, 0xe2, 0x63, 0x1a, 0x22, 0x17, 0xca, 0x3c, 0x0b, 0x02, 0xca, 0xb9, 0x4a, 0xaa, 0x82, 0xe7,
-	0xa6, 0x64, 0x42, 0x2a, 0x48, 0x14, 0x73, 0x73, 0xb4, 0xb9, 0x89, 0xae, 0x41, 0x95, 0x32, 0x96,
-	0x32, 0x99, 0xb8, 0x6b, 0xab, 0xa3, 0x2c, 0x00, 0xe4, 0x41, 0x45, 0x19, 0x43, 0xc1, 0xdc, 0xa2,
-	0x5a, 0x98, 0xdb, 0x72, 0x65, 0xc0, 0x28, 0x11, 0x34, 0x7c, 0x28, 0xdc, 0x92, 0x22, 0x17, 0x80,
-	0x64, 0xb9, 0x3c, 0x92, 0x62, 0x1d, 0xcd, 0xe6, 0x00, 0xea, 0x40, 0x2d, 0x48, 0xa7, 0xb3, 0x98,
-	0x6a, 0xbe, 0xac, 0xf8, 0x65, 0x08, 0xed, 0xc2, 0x36, 0x0f, 0xc6, 0x34, 0xcc, 0x62, 0xca, 0xf6,
-	0x33, 0x46, 0x44, 0x94, 0x26, 0x6e, 0xa5, 0x63, 0xf5, 0x6c, 0xbc, 0x49, 0x48, 0x6f, 0xfa, 0x92,
-	0x06, 0x99, 0x34, 0x72, 0xef, 0xaa, 0xf6, 0xde, 0x20, 0xf2, 0x33, 0xbf, 0xe0, 0x94, 0xb9, 0xa0,
-	0x2a, 0xb5, 0x00, 0xa4, 0x08, 0xa2, 0x29, 0x19, 0x51, 0xb7, 0xa6, 0x45, 0xa0, 0x0c, 0x74, 0x17,
-	0xae, 0xaa, 0x87, 0xe7, 0x59, 0x1c, 0x7f, 0x47, 0x22, 0x91, 0x47, 0xa9, 0xab, 0x28, 0xe7, 0x93,
-	0xa8, 0x07, 0xad, 0x40, 0xb0, 0xe7, 0x8c, 0xce, 0x72, 0xff, 0x86, 0xf2, 0x5f, 0x87, 0xe5, 0x09,
-	0x02, 0xc1, 0xfa, 0xaa, 0x7e, 0xb9, 0x6f, 0x53, 0x9f, 0x60, 0x83, 0x40, 0x37, 0xa0, 0x11, 0x25,
-	0x91, 0x16, 0xcd, 0x61, 0x34, 0xa5, 0x6e, 0x4b, 0x79, 0xae, 0x82, 0xdd, 0x21, 0x54, 0xfb, 0x71,
-	0x44, 0x13, 0xf1, 0x94, 0x8f, 0xd0, 0x35, 0xb0, 0x05, 0xd3, 0x6a, 0xaf, 0xed, 0x55, 0xe6, 0x0d,
-	0x3a, 0xd8, 0xc2, 0x12, 0x46, 0x1d, 0xd3, 0x3f, 0x05, 0x45, 0x83, 0x9f, 0x77, 0x96, 0x54, 0x9d,
-	0x64, 0xa4, 0xea, 0x8e, 0xd3, 0xf0, 0xac, 0xfb, 0x9b, 0x05, 0x55, 0xac, 0x66, 0x92, 0xdc, 0xf5,
-	0x1e, 0xd4, 0x99, 0xd2, 0xef, 0x91, 0x7a, 0xb9, 0x66, 0xfb, 0xb6, 0xbf, 0x26, 0xec, 0xc1, 0x16,
-	0xae, 0xb1, 0x25, 0x9d, 0xbf, 0x36, 0x1c, 0xfa, 0x18, 0x2a, 0x27, 0x46, 0xd7, 0x4a, 0x96, 0xb2,
-	0x1b, 0x96, 0xc5, 0x3e, 0xd8, 0xc2, 0xb9, 0x43, 0x9e, 0xdb, 0x3f, 0x0e, 0xd4, 0x75, 0x6e, 0x43,
-	0xd5, 0x8d, 0x68, 0x07, 0x1c, 0x12, 0x88, 0xe8, 0x54, 0x77, 0x74, 0x09, 0x1b, 0x4b, 0xe2, 0x27,
-	0x24, 0x8a, 0xcd, 0xde, 0x15, 0x6c, 0x2c, 0xd4, 0x84, 0x42, 0x14, 0x1a, 0xa5, 0x17, 0xa2, 0x70,
-	0xb9, 0x6f, 0x4a, 0x17, 0xf4, 0x8d, 0x73, 0x51, 0xdf, 0x94, 0x2f, 0xea, 0x9b, 0xca, 0x85, 0x7d,
-	0x53, 0x7d, 0x4d, 0xdf, 0xc0, 0x66, 0xdf, 0xec, 0x80, 0x13, 0x10, 0xd9, 0x1f, 0x4a, 0xbe, 0x15,
-	0x6c, 0x2c, 0xf4, 0x11, 0xb4, 0x19, 0xfd, 0x21, 0xa3, 0x5c, 0x70, 0x4c, 0x03, 0x1a, 0x9d, 0xd2,
-	0x50, 0x49, 0xb7, 0x88, 0x37, 0x70, 0xa9, 0xda, 0x39, 0x36, 0x20, 0x49, 0x28, 0xcb, 0xd4, 0x50,
-	0xae, 0xeb, 0x30, 0xea, 0x42, 0x7d, 0x12, 0x66, 0xd3, 0x19, 0x7f, 0x96, 0xec, 0x47, 0x7c, 0xa2,
-	0x04, 0x5b, 0xc4, 0x2b, 0xd8, 0xf9, 0x9d, 0xdc, 0xba, 0x54, 0x27, 0xb7, 0x5f, 0xd5, 0xc9, 0xbb,
-	0xb0, 0x1d, 0xf1, 0x6f, 0xa8, 0xf8, 0x31, 0x65, 0x93, 0xfd, 0x88, 0x93, 0x63, 0x99, 0xeb, 0xb6,
-	0x3a, 0xf8, 0x26, 0x81, 0xfa, 0x50, 0x0f, 0x32, 0x2e, 0xd2, 0xa9, 0x56, 0x87, 0x8b, 0xd4, 0x70,
-	0xbe, 0xee, 0x2f, 0x4b, 0xc6, 0xef, 0x2f, 0x79, 0xe8, 0x6f, 0xd6, 0xca, 0xa2, 0x57, 0x0f, 0x82,
-	0xb7, 0x2e, 0x39, 0x08, 0xae, 0x5c, 0x62, 0x10, 0x5c, 0x7d, 0xe3, 0x41, 0xb0, 0x73, 0xce, 0x20,
-	0xf0, 0xbe, 0x84, 0xed, 0x8d, 0x63, 0x5d, 0xea, 0x5b, 0x7b, 0x0a, 0xd5, 0x7e, 0x9a, 0x9c, 0x44,
-	0x23, 0xd9, 0xf3, 0x3e, 0x38, 0x81, 0x32, 0x5c, 0x4b, 0x15, 0x70, 0xc7, 0xcf, 0x39, 0xf3, 0xa4,
-	0xeb, 0x66, 0xbc, 0xbc, 0xcf, 0xa0, 0xb6, 0x04, 0x5f, 0x2a, 0x6e, 0x13, 0xea, 0x7a, 0xa9, 0x4e,
-	0xbc, 0xfb, 0x67, 0x01, 0x1a, 0x4f, 0xd2, 0x11, 0xd6, 0x32, 0x94, 0xc9, 0xec, 0x42, 0x69, 0x79,
-	0xf2, 0x5c, 0xf1, 0x57, 0x68, 0x7f, 0x3e, 0x7d, 0xb4, 0x13, 0xba, 0x09, 0x36, 0x09, 0x26, 0x66,
-	0xec, 0xa0, 0x35, 0xdf, 0x87, 0xc1, 0x44, 0x8e, 0x43, 0x12, 0x48, 0xcd, 0x96, 0x18, 0x25, 0xe1,
-	0x99, 0x19, 0x3d, 0xeb, 0xbb, 0x62, 0xc9, 0xc9, 0x5d, 0x95, 0x93, 0xf7, 0x13, 0x94, 0xf4, 0x58,
-	0xbb, 0xbf, 0x56, 0x99, 0xce, 0x79, 0xd9, 0xfc, 0xcf, 0x35, 0xf2, 0x4a, 0x60, 0x3f, 0x0c, 0x26,
-	0x5e, 0x19, 0x4a, 0x2a, 0xad, 0x7c, 0x18, 0xfe, 0x6b, 0x43, 0x53, 0x85, 0xe7, 0xb3, 0x34, 0xe1,
-	0x54, 0x16, 0xeb, 0x56, 0x7e, 0x4b, 0x92, 0xd9, 0xbd, 0xe3, 0xaf, 0xd2, 0x32, 0x31, 0x41, 0xa2,
-	0x84, 0x32, 0x3d, 0x83, 0xbd, 0xbf, 0x6c, 0xf5, 0xda, 0x35, 0x26, 0xa5, 0x46, 0x66, 0xb3, 0x38,
-	0x0a, 0x94, 0xf2, 0x1e, 0x87, 0x26, 0xbb, 0x55, 0x10, 0xbd, 0x07, 0x70, 0x92, 0x25, 0x81, 0x71,
-	0x31, 0xd7, 0xc5, 0x05, 0xa2, 0x27, 0x98, 0xd9, 0xf2, 0xb1, 0x1e, 0xbf, 0x6a, 0x82, 0xe5, 0x10,
-	0xba, 0x67, 0x92, 0x2c, 0xaa, 0x24, 0xdf, 0x7f, 0x65, 0x92, 0xbe, 0x29, 0xac, 0x49, 0xf6, 0x97,
-	0x02, 0x94, 0x0d, 0x22, 0x87, 0xa8, 0x99, 0x54, 0x79, 0x9a, 0x0b, 0x00, 0x3d, 0xc8, 0x3f, 0x3e,
-	0x32, 0xc0, 0xcd, 0xd7, 0x06, 0xf0, 0x9f, 0x44, 0x09, 0x35, 0x51, 0xfe, 0xb0, 0xa0, 0x28, 0x4d,
-	0x19, 0x42, 0x44, 0x53, 0xca, 0x05, 0x99, 0xce, 0x54, 0x08, 0x1b, 0x2f, 0x00, 0x74, 0x00, 0x0e,
-	0x4f, 0x33, 0x16, 0xe8, 0xd7, 0xd5, 0xdc, 0xbb, 0xf5, 0x66, 0x41, 0xfc, 0xa1, 0x5a, 0x84, 0xcd,
-	0xe2, 0xfc, 0x56, 0x6b, 0x2f, 0x6e, 0xb5, 0xdd, 0x0e, 0x38, 0xda, 0x0b, 0x01, 0x38, 0xc3, 0xc3,
-	0xfd, 0x67, 0x2f, 0x0e, 0xdb, 0x5b, 0xe6, 0xf9, 0x00, 0xe3, 0xb6, 0xb5, 0xf7, 0x73, 0x01, 0x9a,
-	0x7a, 0xa4, 0x3d, 0x97, 0x37, 0xff, 0x20, 0x8d, 0xd1, 0x0d, 0x70, 0x0e, 0x92, 0x91, 0xbc, 0xc7,
-	0x80, 0x9f, 0x5f, 0x09, 0x3c, 0xf0, 0xf3, 0x0f, 0x79, 0xcf, 0xfa, 0xc4, 0x42, 0x77, 0xc1, 0x99,
-	0x7f, 0x37, 0x7d, 0xfd, 0x2f, 0xe1, 0xcf, 0xff, 0x25, 0xfc, 0x03, 0xf9, 0xa3, 0xe1, 0x35, 0x56,
-	0x66, 0x65, 0xd7, 0xfe, 0xb5, 0x60, 0xa1, 0x5d, 0x68, 0x69, 0xe9, 0x66, 0x8c, 0x6a, 0x56, 0x06,
-	0x99, 0x4f, 0x04, 0xaf, 0xe1, 0x2f, 0x77, 0x30, 0xba, 0x03, 0x30, 0x14, 0x8c, 0x92, 0xe9, 0x93,
-	0x74, 0xc4, 0x51, 0x73, 0xb5, 0x41, 0xbc, 0xd6, 0x5a, 0x9d, 0x54, 0x5a, 0x77, 0xa0, 0xac, 0x17,
-	0xef, 0xa1, 0xb7, 0x37, 0xf2, 0x1a, 0xaa, 0x7f, 0x9c, 0xb5, 0xc4, 0x8e, 0x1d, 0xc5, 0x7f, 0xfa,
-	0x5f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x64, 0x4e, 0xf9, 0x4b, 0x3e, 0x0d, 0x00, 0x00,
+	// 1374 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0x4f, 0x6f, 0x1b, 0x45,
+	0x14, 0x8f, 0xbd, 0xf6, 0xda, 0x7e, 0xfe, 0x13, 0x67, 0x68, 0xd3, 0x65, 0xa9, 0xa8, 0xd9, 0x96,
+	0xca, 0x82, 0x74, 0x4b, 0x43, 0x2b, 0x95, 0x4a, 0x80, 0x8a, 0x93, 0xca, 0x41, 0x2d, 0xad, 0xc6,
+	0x29, 0x1c, 0xa3, 0xc9, 0xee, 0xc4, 0x5e, 0xbc, 0xde, 0x35, 0x33, 0xb3, 0xa1, 0x91, 0xb8, 0xc3,
+	0x57, 0xe0, 0x82, 0xc4, 0x81, 0x03, 0x77, 0x3e, 0x07, 0x47, 0x3e, 0x0a, 0x67, 0x34, 0x7f, 0xbc,
+	0xfe, 0x97, 0xa6, 0x8d, 0xc4, 0x6d, 0xdf, 0xef, 0xf7, 0x66, 0xde, 0x9b, 0xb7, 0xef, 0xfd, 0x76,
+	0x16, 0x1a, 0x2c, 0x4b, 0x12, 0xca, 0xfc, 0x29, 0x4b, 0x45, 0xea, 0xbe, 0x37, 0x4c, 0xd3, 0x61,
+	0x4c, 0xef, 0x2a, 0xeb, 0x38, 0x3b, 0xb9, 0x4b, 0x27, 0x53, 0x71, 0x66, 0xc8, 0xeb, 0xab, 0x24,
+	0x17, 0x2c, 0x0b, 0x84, 0x66, 0xbd, 0xbf, 0x0b, 0x50, 0x39, 0x64, 0x67, 0x3d, 0x12, 0xc7, 0xa8,
+	0x0b, 0xed, 0x49, 0x1a, 0xd2, 0x98, 0x1f, 0x05, 0x24, 0x8e, 0x8f, 0xbe, 0xe7, 0x69, 0xe2, 0x14,
+	0x3a, 0x85, 0x6e, 0x0d, 0xb7, 0x34, 0x2e, 0xbd, 0xbe, 0xe6, 0x69, 0x82, 0x3a, 0xd0, 0xe0, 0x71,
+	0x2a, 0x8e, 0x46, 0x84, 0x8f, 0x8e, 0xa2, 0xd0, 0x29, 0x2a, 0x2f, 0x90, 0x58, 0x9f, 0xf0, 0xd1,
+	0x41, 0x88, 0x1e, 0x02, 0xd0, 0x57, 0x82, 0x26, 0x3c, 0x4a, 0x13, 0xee, 0x58, 0x1d, 0xab, 0x5b,
+	0xdf, 0x75, 0x7c, 0x13, 0xc9, 0xdf, 0xcf, 0xa9, 0xfd, 0x44, 0xb0, 0x33, 0xbc, 0xe0, 0xeb, 0x7e,
+	0x0e, 0x9b, 0x2b, 0x34, 0x6a, 0x83, 0x35, 0xa6, 0x67, 0x26, 0x17, 0xf9, 0x88, 0xae, 0x40, 0xf9,
+	0x94, 0xc4, 0x19, 0x35, 0x91, 0xb5, 0xf1, 0xa8, 0xf8, 0xb0, 0xe0, 0xdd, 0x83, 0xda, 0x1e, 0x11,
+	0xe4, 0x09, 0x23, 0x13, 0x8a, 0x10, 0x94, 0x42, 0x22, 0x88, 0x5a, 0xd9, 0xc0, 0xea, 0x59, 0x6e,
+	0x46, 0xd3, 0x13, 0xb5, 0xb0, 0x8a, 0xe5, 0xa3, 0x77, 0x1f, 0xa0, 0x2f, 0xc4, 0xb4, 0x4f, 0x49,
+	0x48, 0xd9, 0xdb, 0x06, 0xf3, 0xbe, 0x85, 0x86, 0x5c, 0x85, 0x29, 0x9f, 0x3e, 0xa3, 0x82, 0xa0,
+	0x1b, 0x50, 0xe7, 0x82, 0x88, 0x8c, 0x1f, 0x05, 0x69, 0x48, 0xd5, 0xfa, 0x32, 0x06, 0x0d, 0xf5,
+	0xd2, 0x90, 0xa2, 0x0f, 0xa1, 0x32, 0x52, 0x21, 0xb8, 0x53, 0x54, 0xf5, 0xa8, 0xfb, 0xf3, 0xb0,
+	0x78, 0xc6, 0x79, 0x5f, 0xc0, 0xa6, 0xac, 0x11, 0xa6, 0x3c, 0x8b, 0xc5, 0x40, 0x10, 0x26, 0xd0,
+	0x4d, 0x28, 0x8d, 0x84, 0x98, 0x3a, 0x61, 0xa7, 0xd0, 0xad, 0xef, 0x36, 0xfd, 0xc5, 0xb8, 0xfd,
+	0x0d, 0xac, 0xc8, 0xaf, 0x6c, 0x28, 0x4d, 0xa8, 0x20, 0xde, 0x6f, 0x25, 0x68, 0xc8, 0x0d, 0x9e,
+	0x44, 0x49, 0xc4, 0x47, 0x34, 0x44, 0x0e, 0x54, 0x78, 0x16, 0x04, 0x94, 0x73, 0x95, 0x54, 0x15,
+	0xcf, 0x4c, 0xc9, 0x84, 0x54, 0x90, 0x28, 0xe6, 0xe6, 0x68, 0x33, 0x13, 0x5d, 0x87, 0x1a, 0x65,
+	0x2c, 0x65, 0x32, 0x71, 0xc7, 0x52, 0x47, 0x99, 0x03, 0xc8, 0x85, 0xaa, 0x32, 0x06, 0x82, 0x39,
+	0x25, 0xb5, 0x30, 0xb7, 0xe5, 0xca, 0x80, 0x51, 0x22, 0x68, 0xf8, 0x58, 0x38, 0x65, 0x45, 0xce,
+	0x01, 0xc9, 0x72, 0x79, 0x24, 0xc5, 0xda, 0x9a, 0xcd, 0x01, 0xd4, 0x81, 0x7a, 0x90, 0x4e, 0xa6,
+	0x31, 0xd5, 0x7c, 0x45, 0xf1, 0x8b, 0x10, 0xda, 0x81, 0x2d, 0x1e, 0x8c, 0x68, 0x98, 0xc5, 0x94,
+	0xed, 0x65, 0x8c, 0x88, 0x28, 0x4d, 0x9c, 0x6a, 0xa7, 0xd0, 0xb5, 0xf0, 0x3a, 0x21, 0xbd, 0xe9,
+	0x2b, 0x1a, 0x64, 0xd2, 0xc8, 0xbd, 0x6b, 0xda, 0x7b, 0x8d, 0xc8, 0xcf, 0xfc, 0x92, 0x53, 0xe6,
+	0x80, 0xaa, 0xd4, 0x1c, 0x90, 0x4d, 0x10, 0x4d, 0xc8, 0x90, 0x3a, 0x75, 0xdd, 0x04, 0xca, 0x40,
+	0xf7, 0xe1, 0xaa, 0x7a, 0x78, 0x91, 0xc5, 0xf1, 0x77, 0x24, 0x12, 0x79, 0x94, 0x86, 0x8a, 0x72,
+	0x3e, 0x89, 0xba, 0xb0, 0x19, 0x08, 0xf6, 0x82, 0xd1, 0x69, 0xee, 0xdf, 0x54, 0xfe, 0xab, 0xb0,
+	0x3c, 0x41, 0x20, 0x58, 0x4f, 0xd5, 0x2f, 0xf7, 0x6d, 0xe9, 0x13, 0xac, 0x11, 0xe8, 0x16, 0x34,
+	0xa3, 0x24, 0xd2, 0x4d, 0x73, 0x18, 0x4d, 0xa8, 0xb3, 0xa9, 0x3c, 0x97, 0x41, 0x6f, 0x00, 0xb5,
+	0x5e, 0x1c, 0xd1, 0x44, 0x3c, 0xe3, 0x43, 0x74, 0x1d, 0x2c, 0xc1, 0x74, 0xb7, 0xd7, 0x77, 0xab,
+	0xb3, 0x01, 0xed, 0x6f, 0x60, 0x09, 0xa3, 0x8e, 0x99, 0x9f, 0xa2, 0xa2, 0xc1, 0xcf, 0x27, 0x4b,
+	0x76, 0x9d, 0x64, 0x64, 0xd7, 0x1d, 0xa7, 0xe1, 0x99, 0xf7, 0x6b, 0x01, 0x6a, 0x58, 0x69, 0x92,
+	0xdc, 0xf5, 0x01, 0x34, 0x98, 0xea, 0xdf, 0x23, 0xf5, 0x72, 0xcd, 0xf6, 0x6d, 0x7f, 0xa5, 0xb1,
+	0xfb, 0x1b, 0xb8, 0xce, 0xe6, 0xe6, 0x9b, 0xc3, 0xa1, 0x8f, 0xa1, 0x7a, 0x62, 0xfa, 0xda, 0xb1,
+	0xcc, 0x34, 0x2c, 0x36, 0x7b, 0x7f, 0x03, 0xe7, 0x0e, 0x79, 0x6e, 0xff, 0xd8, 0xd0, 0xd0, 0xb9,
+	0x0d, 0xd4, 0x34, 0xa2, 0x6d, 0xb0, 0x49, 0x20, 0xa2, 0x53, 0x3d, 0xd1, 0x65, 0x6c, 0x2c, 0x89,
+	0x9f, 0x90, 0x28, 0x36, 0x7b, 0x57, 0xb1, 0xb1, 0x50, 0x0b, 0x8a, 0x51, 0x68, 0x3a, 0xbd, 0x18,
+	0x85, 0x8b, 0x73, 0x53, 0xbe, 0x60, 0x6e, 0xec, 0x8b, 0xe6, 0xa6, 0x72, 0xd1, 0xdc, 0x54, 0x2f,
+	0x9c, 0x9b, 0xda, 0x1b, 0xe6, 0x06, 0xd6, 0xe7, 0x66, 0x1b, 0xec, 0x80, 0xc8, 0xf9, 0x50, 0xed,
+	0x5b, 0xc5, 0xc6, 0x42, 0x1f, 0x41, 0x9b, 0xd1, 0x1f, 0x32, 0xca, 0x05, 0xc7, 0x34, 0xa0, 0xd1,
+	0x29, 0x0d, 0x55, 0xeb, 0x96, 0xf0, 0x1a, 0x2e, 0xbb, 0x76, 0x86, 0xf5, 0x49, 0x12, 0xca, 0x32,
+	0x35, 0x95, 0xeb, 0x2a, 0x8c, 0x3c, 0x68, 0x8c, 0xc3, 0x6c, 0x32, 0xe5, 0xcf, 0x93, 0xbd, 0x88,
+	0x8f, 0x55, 0xc3, 0x96, 0xf0, 0x12, 0x76, 0xfe, 0x24, 0x6f, 0x5e, 0x6a, 0x92, 0xdb, 0xaf, 0x9b,
+	0xe4, 0x1d, 0xd8, 0x8a, 0xf8, 0x37, 0x54, 0xfc, 0x98, 0xb2, 0xf1, 0x5e, 0xc4, 0xc9, 0xb1, 0xcc,
+	0x75, 0x4b, 0x1d, 0x7c, 0x9d, 0x40, 0x3d, 0x68, 0x04, 0x19, 0x17, 0xe9, 0x44, 0x77, 0x87, 0x83,
+	0x94, 0x38, 0xdf, 0xf0, 0x17, 0x5b, 0xc6, 0xef, 0x2d, 0x78, 0xe8, 0x6f, 0xd6, 0xd2, 0xa2, 0xd7,
+	0x0b, 0xc1, 0x3b, 0x97, 0x14, 0x82, 0x2b, 0x97, 0x10, 0x82, 0xab, 0x6f, 0x2d, 0x04, 0xdb, 0xe7,
+	0x08, 0x81, 0xfb, 0x25, 0x6c, 0xad, 0x1d, 0xeb, 0x52, 0xdf, 0xda, 0x53, 0xa8, 0xf5, 0xd2, 0xe4,
+	0x24, 0x1a, 0xca, 0x99, 0xf7, 0xc1, 0x0e, 0x94, 0xe1, 0x14, 0x54, 0x01, 0xb7, 0xfd, 0x9c, 0x33,
+	0x4f, 0xba, 0x6e, 0xc6, 0xcb, 0xfd, 0x0c, 0xea, 0x0b, 0xf0, 0xa5, 0xe2, 0xb6, 0xa0, 0xa1, 0x97,
+	0xea, 0xc4, 0xbd, 0x3f, 0x8b, 0xd0, 0x7c, 0x9a, 0x0e, 0xb1, 0x6e, 0x43, 0x99, 0xcc, 0x0e, 0x94,
+	0x17, 0x95, 0xe7, 0x8a, 0xbf, 0x44, 0xfb, 0x33, 0xf5, 0xd1, 0x4e, 0xe8, 0x36, 0x58, 0x24, 0x18,
+	0x1b, 0xd9, 0x41, 0x2b, 0xbe, 0x8f, 0x83, 0xb1, 0x94, 0x43, 0x12, 0xc8, 0x9e, 0x2d, 0x33, 0x4a,
+	0xc2, 0x33, 0xc7, 0x3a, 0x77, 0x57, 0x2c, 0x39, 0xb9, 0xab, 0x72, 0x72, 0x7f, 0x82, 0xb2, 0x96,
+	0xb5, 0x87, 0x2b, 0x95, 0xe9, 0x9c, 0x97, 0xcd, 0xff, 0x5c, 0x23, 0xb7, 0x0c, 0xd6, 0xe3, 0x60,
+	0xec, 0x56, 0xa0, 0xac, 0xd2, 0xca, 0xc5, 0xf0, 0x5f, 0x0b, 0x5a, 0x2a, 0x3c, 0x9f, 0xa6, 0x09,
+	0xa7, 0xb2, 0x58, 0x77, 0xf2, 0x5b, 0x92, 0xcc, 0xee, 0x5d, 0x7f, 0x99, 0x96, 0x89, 0x09, 0x12,
+	0x25, 0x94, 0x69, 0x0d, 0x76, 0xff, 0xb2, 0xa0, 0x96, 0x63, 0xb2, 0xd5, 0xc8, 0x74, 0x1a, 0x47,
+	0x81, 0xea, 0xbc, 0x83, 0xd0, 0x64, 0xb7, 0x0c, 0xa2, 0xf7, 0x01, 0x4e, 0xb2, 0x24, 0x30, 0x2e,
+	0xe6, 0xba, 0x38, 0x47, 0xb4, 0x82, 0x99, 0x2d, 0x0f, 0xb4, 0xfc, 0xd6, 0xf0, 0x22, 0x84, 0x1e,
+	0x98, 0x24, 0x4b, 0x2a, 0xc9, 0x0f, 0x5e, 0x9b, 0xa4, 0x6f, 0x0a, 0x6b, 0x92, 0xfd, 0xb9, 0x08,
+	0x15, 0x83, 0x48, 0x11, 0x35, 0x4a, 0x95, 0xa7, 0x39, 0x07, 0xd0, 0xa3, 0xfc, 0xe3, 0x23, 0x03,
+	0xdc, 0x7e, 0x63, 0x00, 0xff, 0x69, 0x94, 0x50, 0x13, 0xe5, 0xf7, 0x02, 0x94, 0xa4, 0x29, 0x43,
+	0x88, 0x68, 0x42, 0xb9, 0x20, 0x93, 0xa9, 0x0a, 0x61, 0xe1, 0x39, 0x80, 0xf6, 0xc1, 0xe6, 0x69,
+	0xc6, 0x02, 0xfd, 0xba, 0x5a, 0xbb, 0x77, 0xde, 0x2e, 0x88, 0x3f, 0x50, 0x8b, 0xb0, 0x59, 0x9c,
+	0xdf, 0x6a, 0xad, 0xf9, 0xad, 0xd6, 0xeb, 0x80, 0xad, 0xbd, 0x10, 0x80, 0x3d, 0x38, 0xdc, 0x7b,
+	0xfe, 0xf2, 0xb0, 0xbd, 0x61, 0x9e, 0xf7, 0x31, 0x6e, 0x17, 0xbc, 0x5b, 0x50, 0xeb, 0x91, 0x24,
+	0xa0, 0xb1, 0x7c, 0xe5, 0xd7, 0xa0, 0xa2, 0xee, 0xf8, 0xd1, 0xac, 0x10, 0xb6, 0x34, 0x0f, 0x42,
+	0x6f, 0x07, 0x1a, 0xda, 0xcb, 0xe8, 0x9a, 0xfc, 0x2c, 0x29, 0x3b, 0xa6, 0xda, 0xb5, 0x8a, 0xe7,
+	0xc0, 0xee, 0x1f, 0x45, 0x68, 0x69, 0x99, 0x7c, 0x21, 0xff, 0x26, 0x82, 0x34, 0x46, 0xb7, 0xc0,
+	0xde, 0x4f, 0x86, 0xf2, 0x6e, 0x04, 0x7e, 0x7e, 0xcd, 0x70, 0xc1, 0xcf, 0x2f, 0x07, 0xdd, 0xc2,
+	0x27, 0x05, 0x74, 0x1f, 0xec, 0xd9, 0xb7, 0xd8, 0xd7, 0xff, 0x27, 0xfe, 0xec, 0xff, 0xc4, 0xdf,
+	0x97, 0x3f, 0x2f, 0x6e, 0x73, 0x49, 0x7f, 0x3d, 0xeb, 0x97, 0x62, 0x01, 0xed, 0xc0, 0xa6, 0x1e,
+	0x87, 0x8c, 0x51, 0xcd, 0xca, 0x20, 0x33, 0x95, 0x71, 0x9b, 0xfe, 0xa2, 0x2a, 0xa0, 0x7b, 0x00,
+	0x03, 0xc1, 0x28, 0x99, 0x3c, 0x4d, 0x87, 0x1c, 0xb5, 0x96, 0x87, 0xce, 0xdd, 0x5c, 0xa9, 0xbd,
+	0x4a, 0xeb, 0x1e, 0x54, 0xf4, 0xe2, 0x5d, 0x74, 0x6d, 0x2d, 0xaf, 0x81, 0xfa, 0x6f, 0x5a, 0x49,
+	0x0c, 0xdd, 0x04, 0x5b, 0x17, 0x4c, 0xa6, 0x32, 0xab, 0xaf, 0xdb, 0x34, 0xcf, 0xda, 0xe9, 0xd8,
+	0x56, 0x9b, 0x7c, 0xfa, 0xdf, 0x00, 0xe3, 0x82, 0xa0, 0xe0, 0xb7, 0x0d, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1465,6 +1549,8 @@ type RunnerProtocolClient interface {
 	// Output from the container is sent back via RunnerStatus.Details
 	// as before.
 	Status2(ctx context.Context, in *_struct.Struct, opts ...grpc.CallOption) (*RunnerStatus, error)
+	// Cancel a call running on the runner.
+	Cancel(ctx context.Context, in *CancelMsg, opts ...grpc.CallOption) (*CancelStatus, error)
 }
 
 type runnerProtocolClient struct {
@@ -1565,6 +1651,15 @@ func (c *runnerProtocolClient) Status2(ctx context.Context, in *_struct.Struct,
 	return out, nil
 }
 
+func (c *runnerProtocolClient) Cancel(ctx context.Context, in *CancelMsg, opts ...grpc.CallOption) (*CancelStatus, error) {
+	out := new(CancelStatus)
+	err := c.cc.Invoke(ctx, "/RunnerProtocol/Cancel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RunnerProtocolServer is the server API for RunnerProtocol service.
 type RunnerProtocolServer interface {
 	Engage(RunnerProtocol_EngageServer) error
@@ -1581,6 +1676,8 @@ type RunnerProtocolServer interface {
 	// Output from the container is sent back via RunnerStatus.Details
 	// as before.
 	Status2(context.Context, *_struct.Struct) (*RunnerStatus, error)
+	// Cancel a call running on the runner.
+	Cancel(context.Context, *CancelMsg) (*CancelStatus, error)
 }
 
 // UnimplementedRunnerProtocolServer can be embedded to have forward compatible implementations.
@@ -1602,6 +1699,9 @@ func (*UnimplementedRunnerProtocolServer) StreamLogs(srv RunnerProtocol_StreamLo
 func (*UnimplementedRunnerProtocolServer) Status2(ctx context.Context, req *_struct.Struct) (*RunnerStatus, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Status2 not implemented")
 }
+func (*UnimplementedRunnerProtocolServer) Cancel(ctx context.Context, req *CancelMsg) (*CancelStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
 
 func RegisterRunnerProtocolServer(s *grpc.Server, srv RunnerProtocolServer) {
 	s.RegisterService(&_RunnerProtocol_serviceDesc, srv)
@@ -1713,6 +1813,24 @@ func _RunnerProtocol_Status2_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RunnerProtocol_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RunnerProtocolServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/RunnerProtocol/Cancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RunnerProtocolServer).Cancel(ctx, req.(*CancelMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _RunnerProtocol_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "RunnerProtocol",
 	HandlerType: (*RunnerProtocolServer)(nil),
@@ -1729,6 +1847,10 @@ var _RunnerProtocol_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Status2",
 			Handler:    _RunnerProtocol_Status2_Handler,
 		},
+		{
+			MethodName: "Cancel",
+			Handler:    _RunnerProtocol_Cancel_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -11,6 +11,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,7 +25,8 @@ import (
 // TODO(reed): this should use the fn_go bindings now, most likely, but get trigger by source
 // needs to be dealt with before that can happen.
 
-// client implements agent.DataAccess
+// client implements agent.DataAccess, and models.Leaser along with the listing
+// of apps and triggers so that LB nodes can fire schedule triggers
 type client struct {
 	base string
 	http *http.Client
@@ -93,7 +95,7 @@ func (cl *client) GetAppByID(ctx context.Context, appID string) (*models.App, er
 	defer span.End()
 
 	var a models.App
-	err := cl.do(ctx, nil, &a, "GET", noQuery, "apps", appID)
+	err := cl.do(ctx, nil, &a, "GET", noQuery, "runner", "apps", appID)
 	return &a, err
 }
 
@@ -111,13 +113,101 @@ func (cl *client) GetFnByID(ctx context.Context, fnID string) (*models.Fn, error
 	defer span.End()
 
 	var fn models.Fn
-	err := cl.do(ctx, nil, &fn, "GET", noQuery, "fns", fnID)
+	err := cl.do(ctx, nil, &fn, "GET", noQuery, "runner", "fns", fnID)
 	if err != nil {
 		return nil, err
 	}
 	return &fn, nil
 }
 
+func (cl *client) GetDomainByHostname(ctx context.Context, hostname string) (*models.Domain, error) {
+	ctx, span := trace.StartSpan(ctx, "hybrid_client_get_domain_by_hostname")
+	defer span.End()
+
+	var domain models.Domain
+	err := cl.do(ctx, nil, &domain, "GET", noQuery, "runner", "domainByHostname", hostname)
+	if err, ok := err.(*httpErr); ok && err.code == http.StatusNotFound {
+		return nil, models.ErrDomainNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+func (cl *client) GetAliasByName(ctx context.Context, appID, name string) (*models.Alias, error) {
+	ctx, span := trace.StartSpan(ctx, "hybrid_client_get_alias_by_name")
+	defer span.End()
+
+	var alias models.Alias
+	err := cl.do(ctx, nil, &alias, "GET", noQuery, "runner", "apps", appID, "aliasByName", name)
+	if err, ok := err.(*httpErr); ok && err.code == http.StatusNotFound {
+		return nil, models.ErrAliasNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &alias, nil
+}
+
+func (cl *client) GetApps(ctx context.Context, filter *models.AppFilter) (*models.AppList, error) {
+	ctx, span := trace.StartSpan(ctx, "hybrid_client_get_apps")
+	defer span.End()
+
+	var apps models.AppList
+	err := cl.do(ctx, nil, &apps, "GET", pageQuery(map[string]string{"name": filter.Name}, filter.Cursor, filter.PerPage), "apps")
+	if err != nil {
+		return nil, err
+	}
+	return &apps, nil
+}
+
+func (cl *client) GetTriggers(ctx context.Context, filter *models.TriggerFilter) (*models.TriggerList, error) {
+	ctx, span := trace.StartSpan(ctx, "hybrid_client_get_triggers")
+	defer span.End()
+
+	query := map[string]string{"app_id": filter.AppID, "fn_id": filter.FnID, "name": filter.Name}
+	var triggers models.TriggerList
+	err := cl.do(ctx, nil, &triggers, "GET", pageQuery(query, filter.Cursor, filter.PerPage), "triggers")
+	if err != nil {
+		return nil, err
+	}
+	return &triggers, nil
+}
+
+// pageQuery adds the page parameters to query, leaving out empty values
+func pageQuery(query map[string]string, cursor string, perPage int) map[string]string {
+	query["cursor"] = cursor
+	if perPage > 0 {
+		query["per_page"] = strconv.Itoa(perPage)
+	}
+	for k, v := range query {
+		if v == "" {
+			delete(query, k)
+		}
+	}
+	return query
+}
+
+func (cl *client) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	ctx, span := trace.StartSpan(ctx, "hybrid_client_acquire_lease")
+	defer span.End()
+
+	var lease models.Lease
+	err := cl.do(ctx, &models.Lease{Holder: holder, TTL: ttl}, &lease, "PUT", noQuery, "runner", "leases", name)
+	if err != nil {
+		return false, err
+	}
+	return lease.Acquired, nil
+}
+
+func (cl *client) ReleaseLease(ctx context.Context, name, holder string) error {
+	ctx, span := trace.StartSpan(ctx, "hybrid_client_release_lease")
+	defer span.End()
+
+	return cl.do(ctx, nil, nil, "DELETE", map[string]string{"holder": holder}, "runner", "leases", name)
+}
+
 type httpErr struct {
 	code int
 	error
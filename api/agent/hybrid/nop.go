@@ -40,6 +40,18 @@ func (cl *nopDataStore) GetAppByID(ctx context.Context, appID string) (*models.A
 	return nil, errors.New("should not call GetAppByID on a NOP data store")
 }
 
+func (cl *nopDataStore) GetDomainByHostname(ctx context.Context, hostname string) (*models.Domain, error) {
+	ctx, span := trace.StartSpan(ctx, "nop_datastore_get_domain_by_hostname")
+	defer span.End()
+	return nil, errors.New("should not call GetDomainByHostname on a NOP data store")
+}
+
+func (cl *nopDataStore) GetAliasByName(ctx context.Context, appID, name string) (*models.Alias, error) {
+	ctx, span := trace.StartSpan(ctx, "nop_datastore_get_alias_by_name")
+	defer span.End()
+	return nil, errors.New("should not call GetAliasByName on a NOP data store")
+}
+
 func (cl *nopDataStore) Close() error {
 	return nil
 }
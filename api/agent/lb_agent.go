@@ -1,7 +1,6 @@
 package agent
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"io"
@@ -26,6 +25,9 @@ type lbAgent struct {
 	callOverrider CallOverrider
 	shutWg        *common.WaitGroup
 	callOpts      []CallOpt
+	callStore     models.CallStore
+	secrets       models.SecretStore
+	running       runningCalls
 }
 
 type DetachedResponseWriter struct {
@@ -93,6 +95,22 @@ func WithLBCallOptions(opts ...CallOpt) LBAgentOption {
 
 }
 
+// WithLBCallStore records the outcome of every call made through the LB agent in cs
+func WithLBCallStore(cs models.CallStore) LBAgentOption {
+	return func(a *lbAgent) error {
+		a.callStore = cs
+		return nil
+	}
+}
+
+// WithLBSecretStore decrypts the secret config of apps and fns with ss as calls are made to them
+func WithLBSecretStore(ss models.SecretStore) LBAgentOption {
+	return func(a *lbAgent) error {
+		a.secrets = ss
+		return nil
+	}
+}
+
 // NewLBAgent creates an Agent that knows how to load-balance function calls
 // across a group of runner nodes.
 func NewLBAgent(rp pool.RunnerPool, p pool.Placer, options ...LBAgentOption) (Agent, error) {
@@ -140,6 +158,7 @@ func (a *lbAgent) fireAfterCall(ctx context.Context, call *models.Call) error {
 // implements Agent
 func (a *lbAgent) GetCall(opts ...CallOpt) (Call, error) {
 	var c call
+	c.secrets = a.secrets
 
 	// add additional agent options after any call specific options
 	opts = append(opts, a.callOpts...)
@@ -168,6 +187,7 @@ func (a *lbAgent) GetCall(opts ...CallOpt) (Call, error) {
 	setupCtx(&c)
 
 	c.ct = a
+	c.callStore = a.callStore
 	c.stderr = common.NoopReadWriteCloser{}
 	return &c, nil
 }
@@ -217,13 +237,17 @@ func (a *lbAgent) Submit(callI Call) error {
 
 	// pre-read and buffer request body if already not done based
 	// on GetBody presence.
-	buf, err := a.setRequestBody(ctx, call)
-	if buf != nil {
-		defer bufPool.Put(buf)
-	}
+	body, err := a.setRequestBody(ctx, call)
 	if err != nil {
 		return a.handleCallEnd(ctx, call, err, false)
 	}
+	// detached calls are still being placed once Submit returns, and close the body themselves
+	closeBody := body != nil
+	defer func() {
+		if closeBody {
+			body.Close()
+		}
+	}()
 
 	err = call.Start(ctx)
 	if err != nil {
@@ -234,15 +258,16 @@ func (a *lbAgent) Submit(callI Call) error {
 	statsStartRun(ctx)
 
 	if call.Type == models.TypeDetached {
-		return a.placeDetachCall(ctx, call)
+		closeBody = false
+		return a.placeDetachCall(ctx, call, body)
 	}
 	return a.placeCall(ctx, call)
 }
 
-func (a *lbAgent) placeDetachCall(ctx context.Context, call *call) error {
+func (a *lbAgent) placeDetachCall(ctx context.Context, call *call, body *spool) error {
 	errPlace := make(chan error, 1)
 	rw := call.respWriter.(*DetachedResponseWriter)
-	go a.spawnPlaceCall(ctx, call, errPlace)
+	go a.spawnPlaceCall(ctx, call, body, errPlace)
 	select {
 	case err := <-errPlace:
 		return err
@@ -252,11 +277,18 @@ func (a *lbAgent) placeDetachCall(ctx context.Context, call *call) error {
 }
 
 func (a *lbAgent) placeCall(ctx context.Context, call *call) error {
+	ctx, done := a.running.track(ctx, call)
+	defer done()
+
 	err := a.placer.PlaceCall(ctx, a.rp, call)
 	return a.handleCallEnd(ctx, call, err, true)
 }
 
-func (a *lbAgent) spawnPlaceCall(ctx context.Context, call *call, errCh chan error) {
+func (a *lbAgent) spawnPlaceCall(ctx context.Context, call *call, body *spool, errCh chan error) {
+	if body != nil {
+		defer body.Close()
+	}
+
 	var cancel func()
 	ctx = common.BackgroundContext(ctx)
 	cfg := a.placer.GetPlacerConfig()
@@ -266,50 +298,81 @@ func (a *lbAgent) spawnPlaceCall(ctx context.Context, call *call, errCh chan err
 	ctx, cancel = context.WithTimeout(ctx, newCtxTimeout)
 	defer cancel()
 
+	ctx, done := a.running.track(ctx, call)
+	defer done()
+
 	err := a.placer.PlaceCall(ctx, a.rp, call)
 	errCh <- a.handleCallEnd(ctx, call, err, true)
 }
 
-// setRequestGetBody sets GetBody function on the given http.Request if it is missing.  GetBody allows
-// reading from the request body without mutating the state of the request.
-func (a *lbAgent) setRequestBody(ctx context.Context, call *call) (*bytes.Buffer, error) {
+// setRequestBody sets GetBody function on the given http.Request if it is missing.  GetBody allows
+// reading from the request body without mutating the state of the request. The body is held in
+// the returned spool, which must be closed once the call has been placed.
+func (a *lbAgent) setRequestBody(ctx context.Context, call *call) (*spool, error) {
 
 	r := call.req
 	if r.Body == nil || r.GetBody != nil {
 		return nil, nil
 	}
 
-	buf := bufPool.Get().(*bytes.Buffer)
-	buf.Reset()
+	body := newSpool(a.cfg.RequestSpoolThreshold, a.cfg.RequestSpoolDir)
 
 	// WARNING: we need to handle IO in a separate go-routine below
 	// to be able to detect a ctx timeout. When we timeout, we
-	// let gin/http-server to unblock the go-routine below.
-	errApp := make(chan error, 1)
+	// let gin/http-server to unblock the go-routine below, which
+	// then cleans up the spool.
+	errApp := make(chan error)
 	go func() {
-
-		_, err := buf.ReadFrom(r.Body)
-		if err != nil && err != io.EOF {
-			errApp <- err
-			return
+		_, err := io.Copy(body, r.Body)
+		select {
+		case errApp <- err:
+		case <-ctx.Done():
+			body.Close()
 		}
-
-		r.Body = ioutil.NopCloser(bytes.NewReader(buf.Bytes()))
-
-		// GetBody does not mutate the state of the request body
-		r.GetBody = func() (io.ReadCloser, error) {
-			return ioutil.NopCloser(bytes.NewReader(buf.Bytes())), nil
-		}
-
-		close(errApp)
 	}()
 
 	select {
 	case err := <-errApp:
-		return buf, err
+		if err != nil {
+			body.Close()
+			return nil, err
+		}
 	case <-ctx.Done():
-		return buf, ctx.Err()
+		return nil, ctx.Err()
+	}
+
+	r.Body = ioutil.NopCloser(body.Reader())
+
+	// GetBody does not mutate the state of the request body
+	r.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(body.Reader()), nil
 	}
+	return body, nil
+}
+
+// implements Agent
+func (a *lbAgent) CancelCall(ctx context.Context, callID string) error {
+	// the call may have been placed by another lb, so ask every runner
+	runners, err := a.rp.Runners(ctx, &call{Call: &models.Call{ID: callID}})
+	if err != nil {
+		return err
+	}
+	for _, r := range runners {
+		cancelled, err := r.Cancel(ctx, callID)
+		if err != nil {
+			common.Logger(ctx).WithError(err).WithField("runner_addr", r.Address()).Warn("Failed to cancel call on runner")
+			continue
+		}
+		if cancelled {
+			return nil
+		}
+	}
+
+	// a call this lb is still placing is on no runner yet
+	if a.running.cancel(callID) {
+		return nil
+	}
+	return models.ErrCallNotFound
 }
 
 // implements Agent
@@ -319,6 +382,11 @@ func (a *lbAgent) Enqueue(context.Context, *models.Call) error {
 }
 
 func (a *lbAgent) handleCallEnd(ctx context.Context, call *call, err error, isForwarded bool) error {
+	// whatever error cancelling the call caused, report it as cancelled
+	if err != nil && call.isCancelled() {
+		err = models.ErrCallCancelled
+	}
+
 	if isForwarded {
 		call.End(ctx, err)
 		statsStopRun(ctx)
@@ -351,7 +419,7 @@ func (a *lbAgent) handleCallEnd(ctx context.Context, call *call, err error, isFo
 		statsTooBusy(ctx)
 		recordCallLatency(ctx, call, serverBusyMetricName)
 		return models.ErrCallTimeoutServerBusy
-	} else if err == context.Canceled {
+	} else if err == context.Canceled || err == models.ErrCallCancelled {
 		statsCanceled(ctx)
 		recordCallLatency(ctx, call, canceledMetricName)
 	} else if err != nil {
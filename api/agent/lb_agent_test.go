@@ -87,6 +87,10 @@ func (r *mockRunner) TryExec(ctx context.Context, call pool.RunnerCall) (bool, e
 	return true, nil
 }
 
+func (r *mockRunner) Cancel(ctx context.Context, callID string) (bool, error) {
+	return false, nil
+}
+
 func (r *mockRunner) Close(context.Context) error {
 	go func() {
 		r.wg.Wait()
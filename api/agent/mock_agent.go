@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"context"
 	"fmt"
 	"github.com/fnproject/fn/fnext"
 	"github.com/stretchr/testify/mock"
@@ -50,6 +51,10 @@ func (m *MockAgent) Submit(c Call) error {
 	return m.Called(c).Error(0)
 }
 
+func (m *MockAgent) CancelCall(ctx context.Context, callID string) error {
+	return m.Called(ctx, callID).Error(0)
+}
+
 func (m *MockAgent) Close() error {
 	return m.Called().Error(0)
 }
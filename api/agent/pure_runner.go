@@ -643,6 +643,14 @@ func (pr *pureRunner) Submit(Call) error {
 	return errors.New("Submit cannot be called directly in a Pure Runner.")
 }
 
+// implements Agent
+func (pr *pureRunner) CancelCall(ctx context.Context, callID string) error {
+	if !pr.cancelCall(callID) {
+		return models.ErrCallNotFound
+	}
+	return nil
+}
+
 // implements Agent
 func (pr *pureRunner) Close() error {
 	// First stop accepting requests
@@ -675,8 +683,10 @@ func (pr *pureRunner) removeCallHandle(cID string) {
 
 func (pr *pureRunner) spawnSubmit(state *callHandle) {
 	go func() {
+		pr.saveCallHandle(state)
 		err := pr.a.Submit(state.c)
 		state.enqueueCallResponse(err)
+		pr.removeCallHandle(state.c.Model().ID)
 	}()
 }
 
@@ -828,6 +838,25 @@ func (pr *pureRunner) ConfigureRunner(ctx context.Context, config *runner.Config
 	return pr.configFunc(ctx, config)
 }
 
+// implements RunnerProtocolServer
+func (pr *pureRunner) Cancel(ctx context.Context, msg *runner.CancelMsg) (*runner.CancelStatus, error) {
+	return &runner.CancelStatus{Cancelled: pr.cancelCall(msg.CallId)}, nil
+}
+
+// cancelCall cancels the submit of a call, returning false if the call isn't running here
+func (pr *pureRunner) cancelCall(callID string) bool {
+	pr.callHandleLock.Lock()
+	ch := pr.callHandleMap[callID]
+	pr.callHandleLock.Unlock()
+	if ch == nil {
+		return false
+	}
+
+	ch.c.markCancelled()
+	ch.scancel()
+	return true
+}
+
 // implements RunnerProtocolServer
 func (pr *pureRunner) StreamLogs(logStream runner.RunnerProtocol_StreamLogsServer) error {
 	if pr.logStreamer != nil {
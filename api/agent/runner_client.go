@@ -175,6 +175,22 @@ func (r *gRPCRunner) Status(ctx context.Context) (*pool.RunnerStatus, error) {
 	return TranslateGRPCStatusToRunnerStatus(status), err
 }
 
+// implements Runner
+func (r *gRPCRunner) Cancel(ctx context.Context, callID string) (bool, error) {
+	rid := common.RequestIDFromContext(ctx)
+	if rid != "" {
+		// Create a new gRPC metadata where we store the request ID
+		mp := metadata.Pairs(common.RequestIDContextKey, rid)
+		ctx = metadata.NewOutgoingContext(ctx, mp)
+	}
+
+	status, err := r.client.Cancel(ctx, &pb.CancelMsg{CallId: callID})
+	if err != nil {
+		return false, err
+	}
+	return status.Cancelled, nil
+}
+
 // implements Runner
 func (r *gRPCRunner) TryExec(ctx context.Context, call pool.RunnerCall) (bool, error) {
 	log := common.Logger(ctx).WithField("runner_addr", r.address)
@@ -310,6 +326,10 @@ func parseError(msg *pb.CallFinished) error {
 	if eStr == "" {
 		eStr = "Unknown Error From Pure Runner"
 	}
+	// keep cancellations recognisable, so the call is recorded as cancelled
+	if int(eCode) == models.GetAPIErrorCode(models.ErrCallCancelled) && eStr == models.ErrCallCancelled.Error() {
+		return models.ErrCallCancelled
+	}
 	err := models.NewAPIError(int(eCode), errors.New(eStr))
 	if msg.GetErrorUser() {
 		return models.NewFuncError(err)
@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dockerdriver "github.com/fnproject/fn/api/agent/drivers/docker"
+	"github.com/fnproject/fn/api/common"
+)
+
+// secretFilesDockerMountDest is where the directory of the secret files of a
+// container is mounted, read only, in the container
+const secretFilesDockerMountDest = "/run/secrets"
+
+// secretFilesTmpfsOpts are the options of the tmpfs the secret files of a
+// container are written to, which only its owner can get into
+const secretFilesTmpfsOpts = "mode=0700"
+
+// newSecretFiles writes files into a tmpfs of their own, made alongside the
+// iofs of a container whether or not the iofs is a tmpfs, so that the secrets
+// never reach a disk. The files are readable by the user the container runs
+// as alone. If no tmpfs can be mounted, the secrets are not written at all and
+// an error is returned. Closing it removes the files.
+func newSecretFiles(ctx context.Context, cfg *Config, files map[string]string) (iofs, error) {
+	tmpfsCfg := *cfg
+	tmpfsCfg.IOFSOpts = secretFilesTmpfsOpts
+	dir, err := newTmpfsIOFS(ctx, &tmpfsCfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot make a tmpfs for secret files: %v", err)
+	}
+
+	handleErr := func() {
+		if err := dir.Close(); err != nil {
+			common.Logger(ctx).WithError(err).Error("failed to clean up secret files dir")
+		}
+	}
+
+	// unprivileged containers run as the fn user, which has to list the
+	// directory and read the files but not change them, others as root
+	uid, gid := os.Getuid(), os.Getgid()
+	if !cfg.DisableUnprivilegedContainers {
+		uid, gid = dockerdriver.FnUserId, dockerdriver.FnGroupId
+	}
+
+	for name, content := range files {
+		if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+			handleErr()
+			return nil, fmt.Errorf("invalid secret file name %q", name)
+		}
+		path := filepath.Join(dir.AgentPath(), name)
+		if err := ioutil.WriteFile(path, []byte(content), 0400); err != nil {
+			handleErr()
+			return nil, fmt.Errorf("cannot write secret file %s: %v", name, err)
+		}
+		if err := os.Chown(path, uid, gid); err != nil {
+			handleErr()
+			return nil, fmt.Errorf("cannot change secret file %s owner: %v", name, err)
+		}
+	}
+	if err := os.Chown(dir.AgentPath(), uid, gid); err != nil {
+		handleErr()
+		return nil, fmt.Errorf("cannot change secret files dir owner: %v", err)
+	}
+	if err := os.Chmod(dir.AgentPath(), 0500); err != nil {
+		handleErr()
+		return nil, fmt.Errorf("cannot change secret files dir mod: %v", err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	dockerdriver "github.com/fnproject/fn/api/agent/drivers/docker"
+	"golang.org/x/sys/unix"
+)
+
+func TestNewSecretFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secret-files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	cfg := &Config{IOFSAgentPath: dir, IOFSMountRoot: "/docker/iofs"}
+
+	files, err := newSecretFiles(ctx, cfg, map[string]string{"DB_PASSWORD": "hunter2", "tls.key": "key"})
+	if err != nil && os.Geteuid() != 0 {
+		t.Skipf("secret files need a tmpfs, which only root can mount: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fs unix.Statfs_t
+	if err := unix.Statfs(files.AgentPath(), &fs); err != nil || fs.Type != unix.TMPFS_MAGIC {
+		t.Errorf("expected the secret files to be on a tmpfs, got %x %v", fs.Type, err)
+	}
+	if fi, err := os.Stat(files.AgentPath()); err != nil || fi.Mode().Perm() != 0500 || fi.Sys().(*syscall.Stat_t).Uid != dockerdriver.FnUserId {
+		t.Errorf("expected the secret files dir to be only open to the fn user, got %v %v", fi.Mode(), err)
+	}
+	if filepath.Dir(files.DockerPath()) != cfg.IOFSMountRoot {
+		t.Errorf("expected the secret files to be mounted from under %s, got %s", cfg.IOFSMountRoot, files.DockerPath())
+	}
+	for name, expected := range map[string]string{"DB_PASSWORD": "hunter2", "tls.key": "key"} {
+		path := filepath.Join(files.AgentPath(), name)
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != expected {
+			t.Errorf("expected secret file %s to hold %q, got %q", name, expected, b)
+		}
+		fi, err := os.Stat(path)
+		if err != nil || fi.Mode().Perm() != 0400 {
+			t.Errorf("expected secret file %s to be read only by its owner, got %v %v", name, fi.Mode(), err)
+		}
+		if st := fi.Sys().(*syscall.Stat_t); st.Uid != dockerdriver.FnUserId || st.Gid != dockerdriver.FnGroupId {
+			t.Errorf("expected secret file %s to be owned by the fn user, got %d:%d", name, st.Uid, st.Gid)
+		}
+	}
+
+	if err := files.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(files.AgentPath()); !os.IsNotExist(err) {
+		t.Errorf("expected the secret files to be removed once closed, got %v", err)
+	}
+
+	for _, name := range []string{"../escape", ".hidden", "a/b"} {
+		if _, err := newSecretFiles(ctx, cfg, map[string]string{name: "hunter2"}); err == nil {
+			t.Errorf("expected secret file %q to be refused", name)
+		}
+	}
+	if left, _ := ioutil.ReadDir(dir); len(left) != 0 {
+		t.Errorf("expected refused secret files to be cleaned up, got %d dirs left", len(left))
+	}
+}
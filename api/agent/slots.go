@@ -354,6 +354,23 @@ func getSlotQueueKey(call *call, slotExtns string) string {
 	// we need to additionally delimit config and annotations to eliminate overlap bug
 	hash.Write(unsafeBytes("\x00"))
 
+	// secret files are written once per container, so they tell slots apart too
+	keys = keys[:0] // clear keys
+	for k := range call.SecretFiles {
+		i := sort.SearchStrings(keys, k)
+		keys = append(keys, "")
+		copy(keys[i+1:], keys[i:])
+		keys[i] = k
+	}
+
+	for _, k := range keys {
+		hash.Write(unsafeBytes(k))
+		hash.Write(unsafeBytes("\x00"))
+		hash.Write(unsafeBytes(call.SecretFiles[k]))
+		hash.Write(unsafeBytes("\x00"))
+	}
+	hash.Write(unsafeBytes("\x00"))
+
 	keys = keys[:0] // clear keys
 	for k := range call.Annotations {
 		i := sort.SearchStrings(keys, k)
@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// spool holds a request body so that it can be read again for each attempt
+// at placing a call. Bodies are kept in memory up to threshold bytes, larger
+// ones are spilled to a temp file in dir, which is removed on Close. A zero
+// threshold keeps every body in memory.
+type spool struct {
+	threshold uint64
+	dir       string
+
+	buf  *bytes.Buffer
+	file *os.File
+	size int64
+}
+
+func newSpool(threshold uint64, dir string) *spool {
+	buf := bufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return &spool{threshold: threshold, dir: dir, buf: buf}
+}
+
+// implements io.Writer
+func (s *spool) Write(p []byte) (int, error) {
+	if s.file == nil && s.threshold > 0 && uint64(s.buf.Len()+len(p)) > s.threshold {
+		if err := s.spill(); err != nil {
+			return 0, err
+		}
+	}
+	if s.file == nil {
+		return s.buf.Write(p)
+	}
+	n, err := s.file.Write(p)
+	s.size += int64(n)
+	return n, err
+}
+
+// spill moves what is in memory so far to a temp file, which takes all writes from then on
+func (s *spool) spill() error {
+	f, err := ioutil.TempFile(s.dir, "fn-request-")
+	if err != nil {
+		return err
+	}
+	s.file = f
+	n, err := s.buf.WriteTo(f)
+	s.size = n
+	bufPool.Put(s.buf)
+	s.buf = nil
+	return err
+}
+
+// Reader returns a reader of everything written to the spool, from the
+// start. Readers do not affect one another, but must not be used once the
+// spool is closed.
+func (s *spool) Reader() io.Reader {
+	if s.file == nil {
+		return bytes.NewReader(s.buf.Bytes())
+	}
+	return io.NewSectionReader(s.file, 0, s.size)
+}
+
+// implements io.Closer
+func (s *spool) Close() error {
+	if s.file == nil {
+		bufPool.Put(s.buf)
+		s.buf = nil
+		return nil
+	}
+	s.file.Close()
+	return os.Remove(s.file.Name())
+}
@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSpool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spool-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	spooled := func() []os.FileInfo {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return files
+	}
+	readAll := func(s *spool) string {
+		b, err := ioutil.ReadAll(s.Reader())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		threshold uint64
+		body      string
+		onDisk    bool
+	}{
+		{"small body", 16, "hello", false},
+		{"body at threshold", 5, "hello", false},
+		{"large body", 16, strings.Repeat("hello world ", 100), true},
+		{"no threshold", 0, strings.Repeat("hello world ", 100), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newSpool(tc.threshold, dir)
+			// small writes, so that a body is spilled part way through
+			if _, err := io.CopyBuffer(s, strings.NewReader(tc.body), make([]byte, 7)); err != nil {
+				t.Fatal(err)
+			}
+
+			if onDisk := len(spooled()) == 1; onDisk != tc.onDisk {
+				t.Fatalf("expected body on disk to be %v", tc.onDisk)
+			}
+
+			// every reader sees the whole body, however the others were read
+			first := s.Reader()
+			if _, err := io.CopyN(ioutil.Discard, first, 3); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 2; i++ {
+				if got := readAll(s); got != tc.body {
+					t.Fatalf("expected reader %d to read the body, got %q", i, got)
+				}
+			}
+
+			if err := s.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if files := spooled(); len(files) != 0 {
+				t.Fatalf("expected spooled body to be removed on close, found %v", files[0].Name())
+			}
+		})
+	}
+}
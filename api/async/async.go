@@ -0,0 +1,270 @@
+// Package async runs detached calls that were put on a queue. Calls stay on
+// the queue until they have run, so they are not lost if placing them fails
+// or the node running them dies; they may run more than once as a result.
+package async
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fnproject/fn/api/agent"
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/id"
+	"github.com/fnproject/fn/api/models"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultWorkers is how many queued calls a node runs at once
+	DefaultWorkers = 8
+
+	// DefaultPollInterval is how long an idle worker waits before looking for calls again
+	DefaultPollInterval = time.Second
+
+	// DefaultReserveTimeout is how long a call is kept from other workers
+	// while it is retried, after which another worker may take it over.
+	DefaultReserveTimeout = time.Hour
+
+	// DefaultMaxHops is how many fn destinations in a row the outcomes of a
+	// call may be sent through
+	DefaultMaxHops = 8
+)
+
+// DefaultRetry retries a call 5 times, waiting up to a minute between attempts
+var DefaultRetry = common.BackOffConfig{
+	MaxRetries: 5,
+	Interval:   1000,
+	MinDelay:   100,
+	MaxDelay:   60000,
+}
+
+// Config configures a Runner
+type Config struct {
+	Workers        int
+	PollInterval   time.Duration
+	ReserveTimeout time.Duration
+	Retry          common.BackOffConfig
+	MaxHops        int
+}
+
+// DefaultConfig returns the default Runner configuration
+func DefaultConfig() Config {
+	return Config{
+		Workers:        DefaultWorkers,
+		PollInterval:   DefaultPollInterval,
+		ReserveTimeout: DefaultReserveTimeout,
+		Retry:          DefaultRetry,
+		MaxHops:        DefaultMaxHops,
+	}
+}
+
+// Runner takes calls off a queue and runs them with an agent, retrying
+// failed attempts with backoff until they succeed or retries run out.
+type Runner struct {
+	cfg Config
+	mq  models.MQ
+	da  agent.ReadDataAccess
+	a   agent.Agent
+
+	client *http.Client
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRunner creates a Runner, which does nothing until started
+func NewRunner(mq models.MQ, da agent.ReadDataAccess, a agent.Agent, cfg Config) *Runner {
+	if cfg.Workers <= 0 {
+		cfg.Workers = DefaultWorkers
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.ReserveTimeout <= 0 {
+		cfg.ReserveTimeout = DefaultReserveTimeout
+	}
+	return &Runner{
+		cfg:    cfg,
+		mq:     mq,
+		da:     da,
+		a:      a,
+		client: &http.Client{Timeout: deliveryTimeout},
+	}
+}
+
+// Start runs the workers in the background until ctx is done or Stop is called
+func (r *Runner) Start(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+	for i := 0; i < r.cfg.Workers; i++ {
+		r.wg.Add(1)
+		go r.work(ctx)
+	}
+}
+
+// Stop waits for the attempts in progress to finish. Calls waiting to be
+// retried stay reserved, and are taken over by another worker once their
+// reservation lapses.
+func (r *Runner) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *Runner) work(ctx context.Context) {
+	defer r.wg.Done()
+	for {
+		call, err := r.mq.Reserve(ctx, r.cfg.ReserveTimeout)
+		if err != nil && ctx.Err() == nil {
+			common.Logger(ctx).WithError(err).Error("failed to reserve queued call")
+		}
+		if call == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(r.cfg.PollInterval):
+			}
+			continue
+		}
+
+		r.run(ctx, call)
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// run makes attempts at a queued call until one succeeds or fails in a way
+// that trying again won't fix, then takes the call off the queue. The request
+// of a call that finally failed goes to the dead letter of its fn, and its
+// outcome to the destinations of its fn, unless it was cancelled.
+func (r *Runner) run(ctx context.Context, queued *models.Call) {
+	ctx, log := common.LoggerWithFields(ctx, logrus.Fields{"call_id": queued.ID, "fn_id": queued.FnID, "app_id": queued.AppID})
+
+	backoff := common.NewBackOff(r.cfg.Retry)
+	var res *result
+	var err error
+	for {
+		// let attempts finish as the runner stops, the agent waits for them anyway
+		res, err = r.attempt(common.BackgroundContext(ctx), queued)
+		if err == nil || !retryable(err) {
+			break
+		}
+
+		delay, ok := backoff.NextBackOff()
+		if !ok {
+			break
+		}
+		log.WithError(err).WithField("delay", delay).Info("queued call failed, retrying")
+		select {
+		case <-ctx.Done():
+			// leave it reserved, it runs again once the reservation lapses
+			return
+		case <-time.After(delay):
+		}
+	}
+
+	if cancelled(err) {
+		// cancelled on purpose, the call neither failed nor succeeded
+		log.Info("queued call was cancelled")
+	} else {
+		if err != nil {
+			log.WithError(err).Error("queued call failed")
+			if !r.deadLetter(ctx, queued, err) {
+				// stopped before the dead letter took it, leave the call to run again
+				return
+			}
+		}
+		if !r.forward(ctx, queued, res, err) {
+			return
+		}
+	}
+
+	if err := r.mq.Delete(common.BackgroundContext(ctx), queued); err != nil {
+		log.WithError(err).Error("failed to delete queued call, it may run again")
+	}
+}
+
+// attempt runs a queued call once. The runner waits for calls to complete,
+// rather than have the agent detach them, so that failures can be retried
+// and responses forwarded: an LB agent returns from a detached call as soon
+// as a runner accepts it, before the fn errors or times out.
+func (r *Runner) attempt(ctx context.Context, queued *models.Call) (*result, error) {
+	fn, err := r.da.GetFnByID(ctx, queued.FnID)
+	if err != nil {
+		return nil, err
+	}
+	app, err := r.da.GetAppByID(ctx, fn.AppID)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(queued.Method, queued.URL, strings.NewReader(queued.Payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = cloneHeader(queued.Headers)
+	req = req.WithContext(ctx)
+
+	res := &result{header: make(http.Header), status: http.StatusOK}
+	call, err := r.a.GetCall(
+		agent.WithWriter(res),
+		agent.FromHTTPFnRequest(app, fn, req),
+		agent.WithQueuedCall(queued),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return res, r.a.Submit(call)
+}
+
+// credentialHeaders are the headers of requests that are not kept with
+// queued calls, which are stored and may be passed on to dead letters
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// NewCall makes a detached call of fn, to be put on a queue. The credentials
+// the request came with are left out of it.
+func NewCall(app *models.App, fn *models.Fn, method, url string, header http.Header, body []byte) *models.Call {
+	header = cloneHeader(header)
+	for _, h := range credentialHeaders {
+		header.Del(h)
+	}
+	return &models.Call{
+		ID:        id.New().String(),
+		Type:      models.TypeDetached,
+		Status:    "queued",
+		Payload:   string(body),
+		URL:       url,
+		Method:    method,
+		Headers:   header,
+		CreatedAt: common.DateTime(time.Now()),
+		AppID:     app.ID,
+		AppName:   app.Name,
+		FnID:      fn.ID,
+	}
+}
+
+func cloneHeader(h http.Header) http.Header {
+	clone := make(http.Header, len(h))
+	for k, vs := range h {
+		clone[k] = append([]string(nil), vs...)
+	}
+	return clone
+}
+
+// cancelled tells if an attempt failed because the call was cancelled, the
+// error may have come back from a runner with its code alone
+func cancelled(err error) bool {
+	return err != nil && models.GetAPIErrorCode(err) == models.GetAPIErrorCode(models.ErrCallCancelled)
+}
+
+// retryable tells if an attempt that failed with err might succeed later;
+// errors that are not api errors are failures of the service, not the call.
+func retryable(err error) bool {
+	code := models.GetAPIErrorCode(err)
+	return code == 0 || code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
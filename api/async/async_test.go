@@ -0,0 +1,141 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fnproject/fn/api/agent"
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/id"
+	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/api/mqs"
+)
+
+type testCall struct {
+	agent.Call
+}
+
+// testAgent fails the first len(errs) calls submitted with errs, in order
+type testAgent struct {
+	agent.Agent
+
+	lock     sync.Mutex
+	errs     []error
+	attempts int
+}
+
+func (a *testAgent) GetCall(opts ...agent.CallOpt) (agent.Call, error) {
+	return testCall{}, nil
+}
+
+func (a *testAgent) Submit(agent.Call) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.attempts++
+	if len(a.errs) > 0 {
+		err := a.errs[0]
+		a.errs = a.errs[1:]
+		return err
+	}
+	return nil
+}
+
+func (a *testAgent) count() int {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.attempts
+}
+
+func setup(t *testing.T, errs ...error) (*Runner, *testAgent, models.MQ, *models.Call) {
+	app := &models.App{ID: id.New().String(), Name: "myapp"}
+	fn := &models.Fn{ID: id.New().String(), AppID: app.ID, Name: "myfn", Image: "fnproject/fn-test-utils"}
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn})
+
+	mq := mqs.NewMemoryMQ()
+	call := &models.Call{
+		ID:      id.New().String(),
+		AppID:   app.ID,
+		FnID:    fn.ID,
+		Type:    models.TypeDetached,
+		Method:  http.MethodPost,
+		URL:     "http://localhost:8080/invoke/" + fn.ID,
+		Payload: "hello",
+	}
+	if _, err := mq.Push(context.Background(), call); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &testAgent{errs: errs}
+	cfg := DefaultConfig()
+	cfg.Retry = common.BackOffConfig{MaxRetries: 2, Interval: 1, MaxDelay: 1}
+	return NewRunner(mq, ds, a, cfg), a, mq, call
+}
+
+func checkQueueEmpty(t *testing.T, mq models.MQ) {
+	t.Helper()
+	// the call would have been reserved by the runner, wait for that to lapse
+	time.Sleep(5 * time.Millisecond)
+	call, err := mq.Reserve(context.Background(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call != nil {
+		t.Fatalf("expected call to be taken off the queue, found %s", call.ID)
+	}
+}
+
+func TestRunnerRetriesFailedCalls(t *testing.T) {
+	r, a, mq, call := setup(t, errors.New("placement failed"), models.ErrCallTimeoutServerBusy)
+
+	ctx := context.Background()
+	r.run(ctx, call)
+	if a.count() != 3 {
+		t.Fatalf("expected the call to succeed on its third attempt, got %d attempts", a.count())
+	}
+	checkQueueEmpty(t, mq)
+}
+
+func TestRunnerGivesUpAfterRetries(t *testing.T) {
+	err := errors.New("placement failed")
+	r, a, mq, call := setup(t, err, err, err, err)
+
+	r.run(context.Background(), call)
+	if a.count() != 3 {
+		t.Fatalf("expected the call to be attempted once and retried twice, got %d attempts", a.count())
+	}
+	checkQueueEmpty(t, mq)
+}
+
+func TestRunnerDoesNotRetryCallErrors(t *testing.T) {
+	r, a, mq, call := setup(t, models.ErrInvalidPayload)
+
+	r.run(context.Background(), call)
+	if a.count() != 1 {
+		t.Fatalf("expected a call that can't succeed not to be retried, got %d attempts", a.count())
+	}
+	checkQueueEmpty(t, mq)
+}
+
+func TestRunnerRunsQueuedCalls(t *testing.T) {
+	r, a, mq, _ := setup(t)
+	r.cfg.PollInterval = time.Millisecond
+
+	r.Start(context.Background())
+	for i := 0; a.count() == 0; i++ {
+		if i > 100 {
+			t.Fatal("queued call was never run")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	r.Stop()
+
+	if a.count() != 1 {
+		t.Fatalf("expected the queued call to run once, got %d attempts", a.count())
+	}
+	checkQueueEmpty(t, mq)
+}
@@ -0,0 +1,62 @@
+package async
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/models"
+)
+
+const (
+	// DeadLetterCallIDHeader carries the id of the call a dead letter is for
+	DeadLetterCallIDHeader = "Fn-Dead-Letter-Call-Id"
+
+	// DeadLetterErrorCodeHeader carries the status code of the error the call finally failed with
+	DeadLetterErrorCodeHeader = "Fn-Dead-Letter-Error-Code"
+
+	// DeadLetterErrorHeader carries the error the call finally failed with
+	DeadLetterErrorHeader = "Fn-Dead-Letter-Error"
+)
+
+// deadLetter sends the request of a call that finally failed with callErr to
+// the dead letter of its fn, if it has one. It returns false only if the
+// runner stopped before the delivery was made.
+func (r *Runner) deadLetter(ctx context.Context, queued *models.Call, callErr error) bool {
+	log := common.Logger(ctx)
+
+	// dead letters that fail are dropped, or they could go round forever
+	if queued.Headers.Get(DeadLetterCallIDHeader) != "" {
+		return true
+	}
+
+	fn, err := r.da.GetFnByID(ctx, queued.FnID)
+	if err != nil {
+		log.WithError(err).Error("failed to look up the dead letter of failed call")
+		return true
+	}
+	if fn.DeadLetter == "" {
+		return true
+	}
+
+	code := models.GetAPIErrorCode(callErr)
+	if code == 0 {
+		code = http.StatusInternalServerError
+	}
+	header := cloneHeader(queued.Headers)
+	for _, h := range credentialHeaders {
+		header.Del(h)
+	}
+	header.Set(DeadLetterCallIDHeader, queued.ID)
+	header.Set(DeadLetterErrorCodeHeader, strconv.Itoa(code))
+	header.Set(DeadLetterErrorHeader, headerValue(callErr.Error()))
+
+	err = r.deliver(ctx, fn.DeadLetter, queued, header, []byte(queued.Payload))
+	if err != nil && ctx.Err() != nil {
+		return false
+	} else if err != nil {
+		log.WithError(err).WithField("dead_letter", fn.DeadLetter).Error("failed to deliver dead letter")
+	}
+	return true
+}
@@ -0,0 +1,130 @@
+package async
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/id"
+	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/api/mqs"
+)
+
+type delivery struct {
+	header http.Header
+	body   string
+}
+
+func setupDeadLetter(t *testing.T, deadLetter string, errs ...error) (*Runner, models.MQ, *models.Call, *models.Fn) {
+	app := &models.App{ID: id.New().String(), Name: "myapp"}
+	fn := &models.Fn{ID: id.New().String(), AppID: app.ID, Name: "myfn", Image: "fnproject/fn-test-utils"}
+	dlFn := &models.Fn{ID: id.New().String(), AppID: app.ID, Name: "dead-letters", Image: "fnproject/fn-test-utils"}
+	if deadLetter == "" {
+		deadLetter = dlFn.ID
+	}
+	fn.DeadLetter = deadLetter
+	// would go round forever if dead letters were dead lettered
+	dlFn.DeadLetter = dlFn.ID
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn, dlFn})
+
+	mq := mqs.NewMemoryMQ()
+	header := http.Header{"Content-Type": {"text/plain"}, "Authorization": {"Bearer secret"}, "Cookie": {"session=secret"}}
+	call := NewCall(app, fn, http.MethodPost, "http://localhost:8080/invoke/"+fn.ID, header, []byte("hello"))
+
+	cfg := DefaultConfig()
+	cfg.Retry = common.BackOffConfig{MaxRetries: 1, Interval: 1, MaxDelay: 1}
+	return NewRunner(mq, ds, &testAgent{errs: errs}, cfg), mq, call, dlFn
+}
+
+func TestDeadLetterToWebhook(t *testing.T) {
+	deliveries := make(chan delivery, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		deliveries <- delivery{req.Header, string(body)}
+	}))
+	defer srv.Close()
+
+	callErr := models.ErrCallTimeout
+	r, mq, call, _ := setupDeadLetter(t, srv.URL, callErr, callErr)
+	if _, err := mq.Push(context.Background(), call); err != nil {
+		t.Fatal(err)
+	}
+
+	r.run(context.Background(), call)
+
+	select {
+	case d := <-deliveries:
+		if d.body != "hello" || d.header.Get("Content-Type") != "text/plain" {
+			t.Fatalf("expected the request of the call to be delivered, got %q %v", d.body, d.header)
+		}
+		if d.header.Get("Authorization") != "" || d.header.Get("Cookie") != "" {
+			t.Fatalf("expected the credentials of the call not to be delivered, got %v", d.header)
+		}
+		if d.header.Get(DeadLetterCallIDHeader) != call.ID {
+			t.Fatalf("expected the id of the failed call, got %q", d.header.Get(DeadLetterCallIDHeader))
+		}
+		if d.header.Get(DeadLetterErrorCodeHeader) != strconv.Itoa(models.GetAPIErrorCode(callErr)) {
+			t.Fatalf("expected the error code of the failure, got %q", d.header.Get(DeadLetterErrorCodeHeader))
+		}
+		if d.header.Get(DeadLetterErrorHeader) != callErr.Error() {
+			t.Fatalf("expected the error of the failure, got %q", d.header.Get(DeadLetterErrorHeader))
+		}
+	default:
+		t.Fatal("expected the failed call to be delivered to the dead letter")
+	}
+	if len(deliveries) != 0 {
+		t.Fatal("expected the failed call to be delivered once")
+	}
+	checkQueueEmpty(t, mq)
+}
+
+func TestDeadLetterToFn(t *testing.T) {
+	r, mq, call, dlFn := setupDeadLetter(t, "", models.ErrInvalidPayload)
+
+	r.run(context.Background(), call)
+
+	dl, err := mq.Reserve(context.Background(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dl == nil {
+		t.Fatal("expected a call of the dead letter fn to be queued")
+	}
+	if dl.FnID != dlFn.ID || dl.Type != models.TypeDetached || dl.Payload != "hello" {
+		t.Fatalf("expected a detached call of the dead letter fn with the failed request, got %+v", dl)
+	}
+	if dl.URL != "http://localhost:8080/invoke/"+dlFn.ID {
+		t.Fatalf("expected the dead letter call to be made to its fn, got %q", dl.URL)
+	}
+	if dl.Headers.Get("Authorization") != "" || dl.Headers.Get("Cookie") != "" {
+		t.Fatalf("expected the credentials of the call not to be passed on, got %v", dl.Headers)
+	}
+	if dl.Headers.Get(DeadLetterCallIDHeader) != call.ID || dl.Headers.Get(DeadLetterErrorCodeHeader) != "400" {
+		t.Fatalf("expected the failure to be described, got %v", dl.Headers)
+	}
+
+	// dead letters that fail are not dead lettered again
+	r.a.(*testAgent).errs = []error{models.ErrInvalidPayload}
+	r.run(context.Background(), dl)
+	checkQueueEmpty(t, mq)
+}
+
+func TestNoDeadLetterOnSuccess(t *testing.T) {
+	r, mq, call, _ := setupDeadLetter(t, "")
+
+	r.run(context.Background(), call)
+	checkQueueEmpty(t, mq)
+}
+
+func TestNoDeadLetterOnCancel(t *testing.T) {
+	r, mq, call, _ := setupDeadLetter(t, "", models.ErrCallCancelled)
+
+	r.run(context.Background(), call)
+	checkQueueEmpty(t, mq)
+}
@@ -0,0 +1,120 @@
+package async
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/models"
+)
+
+// how long a webhook has to accept a delivery
+const deliveryTimeout = time.Minute
+
+// DestinationHopsHeader carries how many fn destinations in a row the
+// outcomes of a call have been sent through, to break loops of destinations
+const DestinationHopsHeader = "Fn-Destination-Hops"
+
+// deliver sends body to dest, which is the url of a webhook or the id of a
+// fn; calls to fns are queued like any other detached call. queued is the
+// call the delivery is about.
+func (r *Runner) deliver(ctx context.Context, dest string, queued *models.Call, header http.Header, body []byte) error {
+	if u, ok := models.DestinationURL(dest); ok {
+		return r.post(ctx, u.String(), header, body)
+	}
+
+	hops := destinationHops(queued) + 1
+	if hops > r.cfg.MaxHops {
+		return fmt.Errorf("call has been through %d fn destinations already", hops-1)
+	}
+
+	fn, err := r.da.GetFnByID(ctx, dest)
+	if err != nil {
+		return err
+	}
+	app, err := r.da.GetAppByID(ctx, fn.AppID)
+	if err != nil {
+		return err
+	}
+
+	header.Set(DestinationHopsHeader, strconv.Itoa(hops))
+	call := NewCall(app, fn, http.MethodPost, invokeURL(queued.URL, fn.ID), header, body)
+	_, err = r.mq.Push(common.BackgroundContext(ctx), call)
+	return err
+}
+
+// post sends body to a webhook, retrying until it is accepted or retries run out
+func (r *Runner) post(ctx context.Context, u string, header http.Header, body []byte) error {
+	backoff := common.NewBackOff(r.cfg.Retry)
+	for {
+		err := r.postOnce(ctx, u, header, body)
+		if err == nil || !retryable(err) {
+			return err
+		}
+
+		delay, ok := backoff.NextBackOff()
+		if !ok {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (r *Runner) postOnce(ctx context.Context, u string, header http.Header, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = cloneHeader(header)
+	resp, err := r.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return models.NewAPIError(resp.StatusCode, fmt.Errorf("%s responded with %s", u, resp.Status))
+	}
+	return nil
+}
+
+// destinationHops is how many fn destinations in a row queued was made by
+func destinationHops(queued *models.Call) int {
+	hops, err := strconv.Atoi(queued.Headers.Get(DestinationHopsHeader))
+	if err != nil || hops < 0 {
+		return 0
+	}
+	return hops
+}
+
+// invokeURL is the url fnID would be invoked at, on the host reqURL was made to
+func invokeURL(reqURL, fnID string) string {
+	u, err := url.Parse(reqURL)
+	if err != nil {
+		u = &url.URL{}
+	}
+	u.Path = "/invoke/" + fnID
+	u.RawPath = ""
+	u.RawQuery = ""
+	return u.String()
+}
+
+// headerValue makes s safe to send as the value of a header
+func headerValue(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, s)
+}
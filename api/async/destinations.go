@@ -0,0 +1,84 @@
+package async
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/models"
+	"github.com/sirupsen/logrus"
+)
+
+// HTTPStatusHeader carries the status of the response forwarded to a destination
+const HTTPStatusHeader = "Fn-Http-Status"
+
+// result is the response of a queued call
+type result struct {
+	header http.Header
+	status int
+	bytes.Buffer
+}
+
+var _ http.ResponseWriter = new(result)
+
+func (r *result) Header() http.Header  { return r.header }
+func (r *result) WriteHeader(code int) { r.status = code }
+
+// forward sends the outcome of a queued call to the on_success or on_failure
+// destination of its fn: the response of a call that succeeded, or the error
+// of one that finally failed. Calls whose fn responded with a server error
+// failed too, their response goes to on_failure. It returns false only if the
+// runner stopped before the delivery was made.
+func (r *Runner) forward(ctx context.Context, queued *models.Call, res *result, callErr error) bool {
+	log := common.Logger(ctx)
+
+	fn, err := r.da.GetFnByID(ctx, queued.FnID)
+	if err != nil {
+		if callErr == nil {
+			log.WithError(err).Error("failed to look up the destinations of queued call")
+		}
+		return true
+	}
+
+	dest := fn.OnSuccess
+	if callErr != nil || res.status >= http.StatusInternalServerError {
+		dest = fn.OnFailure
+	}
+	if dest == "" {
+		return true
+	}
+
+	header := make(http.Header)
+	var body []byte
+	if callErr == nil {
+		body = res.Bytes()
+		if ct := res.header.Get("Content-Type"); ct != "" {
+			header.Set("Content-Type", ct)
+		}
+		header.Set(HTTPStatusHeader, strconv.Itoa(res.status))
+	} else {
+		code := models.GetAPIErrorCode(callErr)
+		if code == 0 {
+			code = http.StatusInternalServerError
+		}
+		body, err = json.Marshal(&models.Error{Message: callErr.Error()})
+		if err != nil {
+			log.WithError(err).Error("failed to encode error of queued call")
+			return true
+		}
+		header.Set("Content-Type", "application/json")
+		header.Set(HTTPStatusHeader, strconv.Itoa(code))
+	}
+	header.Set("Fn-Call-Id", queued.ID)
+
+	err = r.deliver(ctx, dest, queued, header, body)
+	if err != nil && ctx.Err() != nil {
+		return false
+	} else if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"destination": dest}).Error("failed to forward outcome of queued call")
+	}
+	return true
+}
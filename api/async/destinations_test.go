@@ -0,0 +1,168 @@
+package async
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/id"
+	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/api/mqs"
+)
+
+// setupDestinations makes a runner for a fn with the given destinations, an
+// empty destination is replaced by the id of a second fn, which is returned
+func setupDestinations(t *testing.T, onSuccess, onFailure string, errs ...error) (*Runner, models.MQ, *models.Call, *models.Fn) {
+	app := &models.App{ID: id.New().String(), Name: "myapp"}
+	fn := &models.Fn{ID: id.New().String(), AppID: app.ID, Name: "myfn", Image: "fnproject/fn-test-utils"}
+	next := &models.Fn{ID: id.New().String(), AppID: app.ID, Name: "next", Image: "fnproject/fn-test-utils"}
+	if onSuccess == "" {
+		onSuccess = next.ID
+	}
+	if onFailure == "" {
+		onFailure = next.ID
+	}
+	fn.OnSuccess, fn.OnFailure = onSuccess, onFailure
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn, next})
+
+	mq := mqs.NewMemoryMQ()
+	call := NewCall(app, fn, http.MethodPost, "http://localhost:8080/invoke/"+fn.ID, http.Header{}, []byte("hello"))
+
+	cfg := DefaultConfig()
+	cfg.Retry = common.BackOffConfig{MaxRetries: 1, Interval: 1, MaxDelay: 1}
+	return NewRunner(mq, ds, &testAgent{errs: errs}, cfg), mq, call, next
+}
+
+func TestForwardSuccessToWebhook(t *testing.T) {
+	deliveries := make(chan delivery, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		deliveries <- delivery{req.Header, string(body)}
+	}))
+	defer srv.Close()
+
+	r, _, call, _ := setupDestinations(t, srv.URL, "")
+
+	res := &result{header: http.Header{"Content-Type": {"application/json"}}, status: http.StatusCreated}
+	res.WriteString(`{"created": true}`)
+	if !r.forward(context.Background(), call, res, nil) {
+		t.Fatal("expected the response to be forwarded")
+	}
+
+	d := <-deliveries
+	if d.body != `{"created": true}` || d.header.Get("Content-Type") != "application/json" {
+		t.Fatalf("expected the response of the call to be forwarded, got %q %v", d.body, d.header)
+	}
+	if d.header.Get(HTTPStatusHeader) != "201" || d.header.Get("Fn-Call-Id") != call.ID {
+		t.Fatalf("expected the status and id of the call to be forwarded, got %v", d.header)
+	}
+}
+
+func TestForwardFailureToFn(t *testing.T) {
+	r, mq, call, next := setupDestinations(t, "https://example.com/unused", "", models.ErrCallTimeout, models.ErrCallTimeout)
+
+	r.run(context.Background(), call)
+
+	fwd, err := mq.Reserve(context.Background(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fwd == nil {
+		t.Fatal("expected a call of the on_failure fn to be queued")
+	}
+	if fwd.FnID != next.ID || fwd.Payload != `{"message":"`+models.ErrCallTimeout.Error()+`"}` {
+		t.Fatalf("expected the error of the call to be forwarded, got %+v", fwd)
+	}
+	if fwd.Headers.Get(HTTPStatusHeader) != "504" || fwd.Headers.Get("Fn-Call-Id") != call.ID {
+		t.Fatalf("expected the status and id of the call to be forwarded, got %v", fwd.Headers)
+	}
+}
+
+func TestForwardSuccessOfRun(t *testing.T) {
+	r, mq, call, next := setupDestinations(t, "", "https://example.com/unused")
+
+	r.run(context.Background(), call)
+
+	fwd, err := mq.Reserve(context.Background(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fwd == nil || fwd.FnID != next.ID {
+		t.Fatalf("expected a call of the on_success fn to be queued, got %+v", fwd)
+	}
+	if fwd.Headers.Get(HTTPStatusHeader) != "200" {
+		t.Fatalf("expected the status of the call to be forwarded, got %v", fwd.Headers)
+	}
+}
+
+func TestForwardStopsAfterMaxHops(t *testing.T) {
+	r, mq, call, next := setupDestinations(t, "", "https://example.com/unused")
+	ctx := context.Background()
+
+	call.Headers.Set(DestinationHopsHeader, strconv.Itoa(r.cfg.MaxHops-1))
+	res := &result{header: make(http.Header), status: http.StatusOK}
+	if !r.forward(ctx, call, res, nil) {
+		t.Fatal("expected the response to be forwarded")
+	}
+	fwd, err := mq.Reserve(ctx, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fwd == nil || fwd.FnID != next.ID {
+		t.Fatalf("expected a call of the on_success fn to be queued, got %+v", fwd)
+	}
+	if hops := fwd.Headers.Get(DestinationHopsHeader); hops != strconv.Itoa(r.cfg.MaxHops) {
+		t.Fatalf("expected the call to count its hops, got %q", hops)
+	}
+
+	// as if the outcome had come back round to the fn
+	call.Headers = fwd.Headers
+	if !r.forward(ctx, call, res, nil) {
+		t.Fatal("expected the forward to be given up")
+	}
+	fwd, err = mq.Reserve(ctx, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fwd != nil {
+		t.Fatalf("expected no call to be queued past the maximum hops, got %+v", fwd)
+	}
+}
+
+func TestForwardServerErrorToFailure(t *testing.T) {
+	deliveries := make(chan delivery, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		deliveries <- delivery{req.Header, string(body)}
+	}))
+	defer srv.Close()
+
+	r, _, call, _ := setupDestinations(t, "https://example.com/unused", srv.URL)
+
+	res := &result{header: make(http.Header), status: http.StatusBadGateway}
+	res.WriteString("upstream down")
+	if !r.forward(context.Background(), call, res, nil) {
+		t.Fatal("expected the response to be forwarded")
+	}
+
+	d := <-deliveries
+	if d.body != "upstream down" || d.header.Get(HTTPStatusHeader) != "502" {
+		t.Fatalf("expected the server error of the fn to go to on_failure, got %q %v", d.body, d.header)
+	}
+}
+
+func TestCancelledCallNotForwarded(t *testing.T) {
+	r, mq, call, _ := setupDestinations(t, "", "", models.ErrCallCancelled)
+	if _, err := mq.Push(context.Background(), call); err != nil {
+		t.Fatal(err)
+	}
+
+	r.run(context.Background(), call)
+	checkQueueEmpty(t, mq)
+}
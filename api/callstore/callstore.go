@@ -0,0 +1,45 @@
+package callstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/models"
+	"github.com/sirupsen/logrus"
+)
+
+// New creates a CallStore from the specified URL
+func New(ctx context.Context, storeURL string) (models.CallStore, error) {
+	log := common.Logger(ctx)
+	log.WithFields(logrus.Fields{"call_store": common.MaskPassword(storeURL)}).Debug("creating new call store")
+
+	for _, provider := range providers {
+		if provider.Supports(storeURL) {
+			return provider.New(ctx, storeURL)
+		}
+	}
+	return nil, fmt.Errorf("no call store provider found for storage url %s", storeURL)
+}
+
+// Wrap adds argument validation and tracing to a CallStore
+func Wrap(cs models.CallStore) models.CallStore {
+	return metricCS(newValidator(cs))
+}
+
+// Provider is a call store provider
+type Provider interface {
+	fmt.Stringer
+	// Supports indicates if this provider can handle a given call store.
+	Supports(url string) bool
+	// New creates a new call store from the specified URL
+	New(ctx context.Context, url string) (models.CallStore, error)
+}
+
+var providers []Provider
+
+// Register globally registers a call store provider
+func Register(provider Provider) {
+	logrus.Infof("Registering call store provider '%s'", provider)
+	providers = append(providers, provider)
+}
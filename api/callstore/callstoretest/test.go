@@ -0,0 +1,193 @@
+package callstoretest
+
+// Call store correctness tests -
+// These tests run validation tests on an underlying call store implementation and can be re-used for new call stores.
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/id"
+	"github.com/fnproject/fn/api/models"
+)
+
+// CallStoreFunc provides an instance of a call store
+type CallStoreFunc func(*testing.T) models.CallStore
+
+func newCall(fnID, status string, createdAt time.Time) *models.Call {
+	return &models.Call{
+		ID:          id.New().String(),
+		FnID:        fnID,
+		AppID:       id.New().String(),
+		Status:      status,
+		CreatedAt:   common.DateTime(createdAt),
+		StartedAt:   common.DateTime(createdAt),
+		CompletedAt: common.DateTime(createdAt.Add(time.Second)),
+	}
+}
+
+// RunAllTests runs the call store test suite against the call store provided by csf
+func RunAllTests(t *testing.T, csf CallStoreFunc) {
+	ctx := context.Background()
+
+	t.Run("insert and get call", func(t *testing.T) {
+		cs := csf(t)
+		fnID := id.New().String()
+		call := newCall(fnID, "success", time.Now().Truncate(time.Millisecond))
+		call.Error = "oops"
+
+		if err := cs.InsertCall(ctx, call); err != nil {
+			t.Fatalf("failed to insert call: %v", err)
+		}
+
+		got, err := cs.GetCall(ctx, fnID, call.ID)
+		if err != nil {
+			t.Fatalf("failed to get call: %v", err)
+		}
+		if got.ID != call.ID || got.FnID != fnID || got.AppID != call.AppID {
+			t.Fatalf("call mismatch, expected %+v got %+v", call, got)
+		}
+		if got.Status != call.Status || got.Error != call.Error {
+			t.Fatalf("call status mismatch, expected %s/%s got %s/%s", call.Status, call.Error, got.Status, got.Error)
+		}
+		if !time.Time(got.CreatedAt).Equal(time.Time(call.CreatedAt)) {
+			t.Fatalf("created_at mismatch, expected %v got %v", call.CreatedAt, got.CreatedAt)
+		}
+	})
+
+	t.Run("insert replaces existing call", func(t *testing.T) {
+		cs := csf(t)
+		fnID := id.New().String()
+		call := newCall(fnID, "running", time.Now())
+		if err := cs.InsertCall(ctx, call); err != nil {
+			t.Fatalf("failed to insert call: %v", err)
+		}
+		call.Status = "error"
+		if err := cs.InsertCall(ctx, call); err != nil {
+			t.Fatalf("failed to re-insert call: %v", err)
+		}
+		got, err := cs.GetCall(ctx, fnID, call.ID)
+		if err != nil {
+			t.Fatalf("failed to get call: %v", err)
+		}
+		if got.Status != "error" {
+			t.Fatalf("expected status error, got %s", got.Status)
+		}
+	})
+
+	t.Run("get missing call", func(t *testing.T) {
+		cs := csf(t)
+		fnID := id.New().String()
+		_, err := cs.GetCall(ctx, fnID, id.New().String())
+		if err != models.ErrCallNotFound {
+			t.Fatalf("expected %v, got %v", models.ErrCallNotFound, err)
+		}
+
+		call := newCall(fnID, "success", time.Now())
+		if err := cs.InsertCall(ctx, call); err != nil {
+			t.Fatalf("failed to insert call: %v", err)
+		}
+		_, err = cs.GetCall(ctx, id.New().String(), call.ID)
+		if err != models.ErrCallNotFound {
+			t.Fatalf("expected %v for call of another fn, got %v", models.ErrCallNotFound, err)
+		}
+	})
+
+	t.Run("empty arguments", func(t *testing.T) {
+		cs := csf(t)
+		if err := cs.InsertCall(ctx, &models.Call{FnID: "fn"}); err != models.ErrDatastoreEmptyCallID {
+			t.Fatalf("expected %v, got %v", models.ErrDatastoreEmptyCallID, err)
+		}
+		if _, err := cs.GetCall(ctx, "", "call"); err != models.ErrDatastoreEmptyFnID {
+			t.Fatalf("expected %v, got %v", models.ErrDatastoreEmptyFnID, err)
+		}
+		if _, err := cs.GetCall(ctx, "fn", ""); err != models.ErrDatastoreEmptyCallID {
+			t.Fatalf("expected %v, got %v", models.ErrDatastoreEmptyCallID, err)
+		}
+		if _, err := cs.GetCalls(ctx, &models.CallFilter{}); err != models.ErrDatastoreEmptyFnID {
+			t.Fatalf("expected %v, got %v", models.ErrDatastoreEmptyFnID, err)
+		}
+	})
+
+	t.Run("list calls", func(t *testing.T) {
+		cs := csf(t)
+		fnID := id.New().String()
+		base := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+
+		var calls []*models.Call
+		for i := 0; i < 5; i++ {
+			status := "success"
+			if i%2 == 1 {
+				status = "error"
+			}
+			c := newCall(fnID, status, base.Add(time.Duration(i)*time.Minute))
+			if err := cs.InsertCall(ctx, c); err != nil {
+				t.Fatalf("failed to insert call: %v", err)
+			}
+			calls = append(calls, c)
+		}
+		// a call for another fn, which must never be listed
+		if err := cs.InsertCall(ctx, newCall(id.New().String(), "success", base)); err != nil {
+			t.Fatalf("failed to insert call: %v", err)
+		}
+
+		res, err := cs.GetCalls(ctx, &models.CallFilter{FnID: fnID, PerPage: 100})
+		if err != nil {
+			t.Fatalf("failed to list calls: %v", err)
+		}
+		if len(res.Items) != 5 {
+			t.Fatalf("expected 5 calls, got %d", len(res.Items))
+		}
+		if res.Items[0].ID != calls[4].ID || res.Items[4].ID != calls[0].ID {
+			t.Fatalf("expected calls newest first, got %v .. %v", res.Items[0].ID, res.Items[4].ID)
+		}
+		if res.NextCursor != "" {
+			t.Fatalf("expected no cursor, got %s", res.NextCursor)
+		}
+
+		// paging
+		res, err = cs.GetCalls(ctx, &models.CallFilter{FnID: fnID, PerPage: 2})
+		if err != nil {
+			t.Fatalf("failed to list calls: %v", err)
+		}
+		if len(res.Items) != 2 || res.NextCursor == "" {
+			t.Fatalf("expected 2 calls and a cursor, got %d %q", len(res.Items), res.NextCursor)
+		}
+		res, err = cs.GetCalls(ctx, &models.CallFilter{FnID: fnID, PerPage: 2, Cursor: res.NextCursor})
+		if err != nil {
+			t.Fatalf("failed to list calls: %v", err)
+		}
+		if len(res.Items) != 2 || res.Items[0].ID != calls[2].ID {
+			t.Fatalf("expected second page to start at %s, got %+v", calls[2].ID, res.Items)
+		}
+
+		// status
+		res, err = cs.GetCalls(ctx, &models.CallFilter{FnID: fnID, Status: "error", PerPage: 100})
+		if err != nil {
+			t.Fatalf("failed to list calls: %v", err)
+		}
+		if len(res.Items) != 2 {
+			t.Fatalf("expected 2 errored calls, got %d", len(res.Items))
+		}
+		for _, c := range res.Items {
+			if c.Status != "error" {
+				t.Fatalf("expected only errored calls, got %s", c.Status)
+			}
+		}
+
+		// time range, exclusive at both ends
+		res, err = cs.GetCalls(ctx, &models.CallFilter{
+			FnID:     fnID,
+			FromTime: calls[0].CreatedAt,
+			ToTime:   calls[4].CreatedAt,
+			PerPage:  100,
+		})
+		if err != nil {
+			t.Fatalf("failed to list calls: %v", err)
+		}
+		if len(res.Items) != 3 || res.Items[0].ID != calls[3].ID || res.Items[2].ID != calls[1].ID {
+			t.Fatalf("expected calls 1-3 in time range, got %+v", res.Items)
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package callstore
+
+import (
+	"context"
+
+	"github.com/fnproject/fn/api/models"
+	"go.opencensus.io/trace"
+)
+
+func metricCS(cs models.CallStore) models.CallStore {
+	return &metriccs{cs}
+}
+
+type metriccs struct {
+	cs models.CallStore
+}
+
+func (m *metriccs) InsertCall(ctx context.Context, call *models.Call) error {
+	ctx, span := trace.StartSpan(ctx, "cs_insert_call")
+	defer span.End()
+	return m.cs.InsertCall(ctx, call)
+}
+
+func (m *metriccs) GetCall(ctx context.Context, fnID, callID string) (*models.Call, error) {
+	ctx, span := trace.StartSpan(ctx, "cs_get_call")
+	defer span.End()
+	return m.cs.GetCall(ctx, fnID, callID)
+}
+
+func (m *metriccs) GetCalls(ctx context.Context, filter *models.CallFilter) (*models.CallList, error) {
+	ctx, span := trace.StartSpan(ctx, "cs_get_calls")
+	defer span.End()
+	return m.cs.GetCalls(ctx, filter)
+}
@@ -0,0 +1,98 @@
+package callstore
+
+import (
+	"context"
+	"encoding/base64"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fnproject/fn/api/models"
+)
+
+type mock struct {
+	mu    sync.RWMutex
+	Calls map[string]*models.Call
+}
+
+// NewMock creates a new in-memory call store, suitable for tests
+func NewMock() models.CallStore {
+	return NewMockInit()
+}
+
+// NewMockInit creates an in-memory call store populated with calls
+func NewMockInit(calls ...*models.Call) models.CallStore {
+	m := &mock{Calls: make(map[string]*models.Call)}
+	for _, c := range calls {
+		cp := *c
+		m.Calls[c.ID] = &cp
+	}
+	return newValidator(m)
+}
+
+func (m *mock) InsertCall(ctx context.Context, call *models.Call) error {
+	cp := *call
+	m.mu.Lock()
+	m.Calls[call.ID] = &cp
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *mock) GetCall(ctx context.Context, fnID, callID string) (*models.Call, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, ok := m.Calls[callID]
+	if !ok || c.FnID != fnID {
+		return nil, models.ErrCallNotFound
+	}
+	cp := *c
+	return &cp, nil
+}
+
+type callsByIDDesc []*models.Call
+
+func (s callsByIDDesc) Len() int           { return len(s) }
+func (s callsByIDDesc) Less(i, j int) bool { return s[i].ID > s[j].ID }
+func (s callsByIDDesc) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func (m *mock) GetCalls(ctx context.Context, filter *models.CallFilter) (*models.CallList, error) {
+	var cursor string
+	if filter.Cursor != "" {
+		s, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = string(s)
+	}
+
+	m.mu.RLock()
+	calls := make(callsByIDDesc, 0, len(m.Calls))
+	for _, c := range m.Calls {
+		cp := *c
+		calls = append(calls, &cp)
+	}
+	m.mu.RUnlock()
+	sort.Sort(calls)
+
+	res := &models.CallList{Items: []*models.Call{}}
+	for _, c := range calls {
+		if filter.PerPage > 0 && len(res.Items) == filter.PerPage {
+			break
+		}
+
+		if c.FnID != filter.FnID ||
+			(cursor != "" && c.ID >= cursor) ||
+			(filter.Status != "" && c.Status != filter.Status) ||
+			(!time.Time(filter.FromTime).IsZero() && !time.Time(c.CreatedAt).After(time.Time(filter.FromTime))) ||
+			(!time.Time(filter.ToTime).IsZero() && !time.Time(c.CreatedAt).Before(time.Time(filter.ToTime))) {
+			continue
+		}
+		res.Items = append(res.Items, c)
+	}
+
+	if len(res.Items) > 0 && len(res.Items) == filter.PerPage {
+		last := []byte(res.Items[len(res.Items)-1].ID)
+		res.NextCursor = base64.RawURLEncoding.EncodeToString(last)
+	}
+	return res, nil
+}
@@ -0,0 +1,15 @@
+package callstore
+
+import (
+	"testing"
+
+	"github.com/fnproject/fn/api/callstore/callstoretest"
+	"github.com/fnproject/fn/api/models"
+)
+
+func TestCallStore(t *testing.T) {
+	f := func(t *testing.T) models.CallStore {
+		return NewMock()
+	}
+	callstoretest.RunAllTests(t, f)
+}
@@ -0,0 +1,43 @@
+package callstore
+
+import (
+	"context"
+
+	"github.com/fnproject/fn/api/models"
+)
+
+// newValidator returns a models.CallStore which validates certain arguments before delegating to cs.
+func newValidator(cs models.CallStore) models.CallStore {
+	return &validator{cs}
+}
+
+type validator struct {
+	models.CallStore
+}
+
+func (v *validator) InsertCall(ctx context.Context, call *models.Call) error {
+	if call.ID == "" {
+		return models.ErrDatastoreEmptyCallID
+	}
+	if call.FnID == "" {
+		return models.ErrDatastoreEmptyFnID
+	}
+	return v.CallStore.InsertCall(ctx, call)
+}
+
+func (v *validator) GetCall(ctx context.Context, fnID, callID string) (*models.Call, error) {
+	if fnID == "" {
+		return nil, models.ErrDatastoreEmptyFnID
+	}
+	if callID == "" {
+		return nil, models.ErrDatastoreEmptyCallID
+	}
+	return v.CallStore.GetCall(ctx, fnID, callID)
+}
+
+func (v *validator) GetCalls(ctx context.Context, filter *models.CallFilter) (*models.CallList, error) {
+	if filter == nil || filter.FnID == "" {
+		return nil, models.ErrDatastoreEmptyFnID
+	}
+	return v.CallStore.GetCalls(ctx, filter)
+}
@@ -15,6 +15,16 @@ const (
 	FnID string = "fn_id"
 	// TriggerSource is the triggers source parameter
 	TriggerSource string = "trigger_source"
+	// DomainID is the url path parameter for domain id
+	DomainID string = "domain_id"
+	// Hostname is the url path parameter for the hostname of a domain - only used in hybrid API
+	Hostname string = "hostname"
+	// AliasID is the url path parameter for alias id
+	AliasID string = "alias_id"
+	// AliasName is the url path parameter for the name of an alias
+	AliasName string = "alias_name"
+	// LeaseName is the url path parameter for the name of a lease - only used in hybrid API
+	LeaseName string = "lease_name"
 
 	//TriggerType is the trigger type parameter - only used in hybrid API
 	TriggerType string = "trigger_type"
@@ -344,6 +344,31 @@ func RunAppsTest(t *testing.T, dsf DataStoreFunc, rp ResourceProvider) {
 			}
 		})
 
+		t.Run("secret config vars", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+
+			app := rp.ValidApp()
+			app.SecretConfig = map[string]string{"DB_PASSWORD": "sealed1", "API_KEY": "sealed2"}
+			testApp := h.GivenAppInDb(app)
+
+			updated, err := ds.UpdateApp(ctx, &models.App{ID: testApp.ID, SecretConfig: map[string]string{"DB_PASSWORD": "sealed3", "API_KEY": ""}})
+			if err != nil {
+				t.Fatalf("error when updating app: %v", err)
+			}
+			expected := map[string]string{"DB_PASSWORD": "sealed3"}
+			if !updated.SecretConfig.Equals(expected) {
+				t.Fatalf("expected updated secret config `%v` but got `%v`", expected, updated.SecretConfig)
+			}
+			stored, err := ds.GetAppByID(ctx, testApp.ID)
+			if err != nil {
+				t.Fatalf("error when getting app: %v", err)
+			}
+			if !stored.SecretConfig.Equals(expected) {
+				t.Fatalf("expected stored secret config `%v` but got `%v`", expected, stored.SecretConfig)
+			}
+		})
+
 		// Testing get app
 
 		t.Run("Get with empty App ID", func(t *testing.T) {
@@ -663,8 +688,405 @@ func RunFnsTest(t *testing.T, dsf DataStoreFunc, rp ResourceProvider) {
 					"THIRD": "3",
 				},
 			}
-			if !expected.EqualsWithAnnotationSubset(updated) {
-				t.Fatalf("expected updated:\n`%v`\nbut got:\n`%v`", expected, updated)
+			if !expected.EqualsWithAnnotationSubset(updated) {
+				t.Fatalf("expected updated:\n`%v`\nbut got:\n`%v`", expected, updated)
+			}
+		})
+
+		t.Run("Update function cpus", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			testFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+
+			updated, err := ds.UpdateFn(ctx, &models.Fn{
+				ID:             testFn.ID,
+				ResourceConfig: models.ResourceConfig{CPUs: 1500},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if updated.CPUs != 1500 {
+				t.Fatalf("expected updated cpus to be 1500m, got %v", updated.CPUs)
+			}
+
+			fn, err := ds.GetFnByID(ctx, testFn.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fn.CPUs != 1500 {
+				t.Fatalf("expected stored cpus to be 1500m, got %v", fn.CPUs)
+			}
+		})
+
+		t.Run("Update function tmpfs size", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			testFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+
+			updated, err := ds.UpdateFn(ctx, &models.Fn{
+				ID:             testFn.ID,
+				ResourceConfig: models.ResourceConfig{TmpFsSize: 128},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if updated.TmpFsSize != 128 {
+				t.Fatalf("expected updated tmpfs_size to be 128, got %v", updated.TmpFsSize)
+			}
+
+			fn, err := ds.GetFnByID(ctx, testFn.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fn.TmpFsSize != 128 {
+				t.Fatalf("expected stored tmpfs_size to be 128, got %v", fn.TmpFsSize)
+			}
+		})
+
+		t.Run("Update function dead letter", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			testFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+
+			deadLetter := "https://example.com/dead-letters"
+			updated, err := ds.UpdateFn(ctx, &models.Fn{
+				ID:         testFn.ID,
+				DeadLetter: deadLetter,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if updated.DeadLetter != deadLetter {
+				t.Fatalf("expected updated dead_letter to be %q, got %q", deadLetter, updated.DeadLetter)
+			}
+
+			fn, err := ds.GetFnByID(ctx, testFn.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fn.DeadLetter != deadLetter {
+				t.Fatalf("expected stored dead_letter to be %q, got %q", deadLetter, fn.DeadLetter)
+			}
+		})
+
+		t.Run("Update function destinations", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			testFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			next := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+
+			onFailure := "https://example.com/errors"
+			updated, err := ds.UpdateFn(ctx, &models.Fn{
+				ID:        testFn.ID,
+				OnSuccess: next.ID,
+				OnFailure: onFailure,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if updated.OnSuccess != next.ID || updated.OnFailure != onFailure {
+				t.Fatalf("expected updated destinations to be %q and %q, got %q and %q", next.ID, onFailure, updated.OnSuccess, updated.OnFailure)
+			}
+
+			fn, err := ds.GetFnByID(ctx, testFn.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fn.OnSuccess != next.ID || fn.OnFailure != onFailure {
+				t.Fatalf("expected stored destinations to be %q and %q, got %q and %q", next.ID, onFailure, fn.OnSuccess, fn.OnFailure)
+			}
+		})
+
+		t.Run("Update function records revisions", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			testFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+
+			_, err := ds.UpdateFn(ctx, &models.Fn{ID: testFn.ID, Image: "fnproject/fn-test-utils:v2", Config: models.Config{"K": "v2"}, SecretConfig: models.Config{"PASSWORD": "sealed-v2"}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// changes to how the fn is called record no revision
+			_, err = ds.UpdateFn(ctx, &models.Fn{ID: testFn.ID, DeadLetter: "https://example.com/dead-letters"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_, err = ds.UpdateFn(ctx, &models.Fn{ID: testFn.ID, Image: "fnproject/fn-test-utils:v3", SecretConfig: models.Config{"PASSWORD": "sealed-v3"}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			revisions, err := ds.GetFnRevisions(ctx, &models.FnRevisionFilter{FnID: testFn.ID, PerPage: 1})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(revisions.Items) != 1 || revisions.NextCursor == "" {
+				t.Fatalf("expected a page of 1 revision and a cursor, got %d and %q", len(revisions.Items), revisions.NextCursor)
+			}
+			latest := revisions.Items[0]
+			if latest.FnID != testFn.ID || latest.AppID != testApp.ID || latest.Image != "fnproject/fn-test-utils:v2" || latest.Config["K"] != "v2" || latest.SecretConfig["PASSWORD"] != "sealed-v2" {
+				t.Fatalf("expected the latest revision to be how the fn ran before the last update, got %#v", latest)
+			}
+
+			revisions, err = ds.GetFnRevisions(ctx, &models.FnRevisionFilter{FnID: testFn.ID, Cursor: revisions.NextCursor})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(revisions.Items) != 1 || revisions.Items[0].Image != testFn.Image || !revisions.Items[0].Config.Equals(testFn.Config) {
+				t.Fatalf("expected the first revision to be how the fn was created, got %#v", revisions.Items)
+			}
+			first := revisions.Items[0]
+
+			// rolling back to the latest revision is undone by rolling back again
+			fn, err := ds.RollbackFn(ctx, testFn.ID, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fn.Image != "fnproject/fn-test-utils:v2" || fn.SecretConfig["PASSWORD"] != "sealed-v2" || fn.DeadLetter != "https://example.com/dead-letters" {
+				t.Fatalf("expected fn to run as it did at the latest revision, got %#v", fn)
+			}
+			fn, err = ds.RollbackFn(ctx, testFn.ID, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fn.Image != "fnproject/fn-test-utils:v3" || fn.SecretConfig["PASSWORD"] != "sealed-v3" {
+				t.Fatalf("expected the rollback to be undone, got image %q", fn.Image)
+			}
+
+			fn, err = ds.RollbackFn(ctx, testFn.ID, first.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			stored, err := ds.GetFnByID(ctx, testFn.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !fn.Equals(stored) || stored.Image != testFn.Image || !stored.Config.Equals(testFn.Config) || len(stored.SecretConfig) != 0 {
+				t.Fatalf("expected fn to be stored as it was created, got %#v", stored)
+			}
+
+			_, err = ds.RollbackFn(ctx, testFn.ID, "nonexistent")
+			if err != models.ErrFnRevisionNotFound {
+				t.Fatalf("expected %v, got %v", models.ErrFnRevisionNotFound, err)
+			}
+			other := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			_, err = ds.RollbackFn(ctx, other.ID, "")
+			if err != models.ErrFnRevisionNotFound {
+				t.Fatalf("expected %v for a fn without revisions, got %v", models.ErrFnRevisionNotFound, err)
+			}
+			_, err = ds.RollbackFn(ctx, "nonexistent", "")
+			if err != models.ErrFnsNotFound {
+				t.Fatalf("expected %v, got %v", models.ErrFnsNotFound, err)
+			}
+
+			if err := ds.RemoveFn(ctx, testFn.ID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			revisions, err = ds.GetFnRevisions(ctx, &models.FnRevisionFilter{FnID: testFn.ID})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(revisions.Items) != 0 {
+				t.Fatalf("expected revisions to be removed with the fn, got %d", len(revisions.Items))
+			}
+		})
+
+		t.Run("Update function keeps the latest revisions", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			testFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			other := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+
+			_, err := ds.UpdateFn(ctx, &models.Fn{ID: other.ID, Config: models.Config{"K": "other"}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for i := 0; i <= models.MaxFnRevisions; i++ {
+				_, err := ds.UpdateFn(ctx, &models.Fn{ID: testFn.ID, Config: models.Config{"K": fmt.Sprint(i)}})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			revisions, err := ds.GetFnRevisions(ctx, &models.FnRevisionFilter{FnID: testFn.ID})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(revisions.Items) != models.MaxFnRevisions {
+				t.Fatalf("expected %d revisions to be kept, got %d", models.MaxFnRevisions, len(revisions.Items))
+			}
+			if oldest := revisions.Items[len(revisions.Items)-1]; oldest.Config["K"] != "0" {
+				t.Fatalf("expected the oldest revision to be removed, got %#v as the oldest kept", oldest)
+			}
+
+			revisions, err = ds.GetFnRevisions(ctx, &models.FnRevisionFilter{FnID: other.ID})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(revisions.Items) != 1 {
+				t.Fatalf("expected revisions of other fns to be kept, got %d", len(revisions.Items))
+			}
+		})
+
+		t.Run("function traffic", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			canary := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			otherApp := h.GivenAppInDb(rp.ValidApp())
+			otherFn := h.GivenFnInDb(rp.ValidFn(otherApp.ID))
+
+			testFn := rp.ValidFn(testApp.ID)
+			testFn.Traffic = &models.FnTraffic{Splits: []models.TrafficSplit{{FnID: canary.ID, Weight: 5}}, StickyHeader: "X-User-Id"}
+			testFn, err := ds.InsertFn(ctx, testFn)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			fn, err := ds.GetFnByID(ctx, testFn.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !fn.Traffic.Equals(testFn.Traffic) {
+				t.Fatalf("expected stored traffic to be %v, got %v", testFn.Traffic, fn.Traffic)
+			}
+
+			_, err = ds.UpdateFn(ctx, &models.Fn{ID: testFn.ID, Traffic: &models.FnTraffic{Splits: []models.TrafficSplit{{FnID: otherFn.ID, Weight: 5}}}})
+			if err != models.ErrFnsTrafficFnNotSameApp {
+				t.Fatalf("expected %v, got %v", models.ErrFnsTrafficFnNotSameApp, err)
+			}
+			_, err = ds.UpdateFn(ctx, &models.Fn{ID: testFn.ID, Traffic: &models.FnTraffic{Splits: []models.TrafficSplit{{FnID: "nonexistent", Weight: 5}}}})
+			if err != models.ErrFnsNotFound {
+				t.Fatalf("expected %v, got %v", models.ErrFnsNotFound, err)
+			}
+
+			fn, err = ds.UpdateFn(ctx, &models.Fn{ID: testFn.ID, Traffic: &models.FnTraffic{}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fn.Traffic != nil {
+				t.Fatalf("expected an empty traffic split to remove it, got %v", fn.Traffic)
+			}
+			fn, err = ds.GetFnByID(ctx, testFn.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fn.Traffic != nil {
+				t.Fatalf("expected stored traffic to be removed, got %v", fn.Traffic)
+			}
+		})
+
+		t.Run("function traffic of removed fns", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			canary := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			other := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+
+			testFn := rp.ValidFn(testApp.ID)
+			testFn.Traffic = &models.FnTraffic{Splits: []models.TrafficSplit{{FnID: canary.ID, Weight: 5}, {FnID: other.ID, Weight: 10}}}
+			testFn = h.GivenFnInDb(testFn)
+
+			if err := ds.RemoveFn(ctx, canary.ID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			fn, err := ds.GetFnByID(ctx, testFn.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := &models.FnTraffic{Splits: []models.TrafficSplit{{FnID: other.ID, Weight: 10}}}
+			if !fn.Traffic.Equals(expected) {
+				t.Fatalf("expected the split to the removed fn to be removed, got %v", fn.Traffic)
+			}
+
+			_, err = ds.UpdateFn(ctx, &models.Fn{ID: testFn.ID, Image: "fnproject/fn-test-utils:latest"})
+			if err != nil {
+				t.Fatalf("expected the fn to be updated, got %v", err)
+			}
+
+			if err := ds.RemoveFn(ctx, other.ID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			fn, err = ds.GetFnByID(ctx, testFn.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fn.Traffic != nil {
+				t.Fatalf("expected traffic to be removed with the last fn it was split to, got %v", fn.Traffic)
+			}
+		})
+
+		t.Run("function destination fns", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			next := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			otherApp := h.GivenAppInDb(rp.ValidApp())
+			otherFn := h.GivenFnInDb(rp.ValidFn(otherApp.ID))
+
+			testFn := rp.ValidFn(testApp.ID)
+			testFn.OnFailure = otherFn.ID
+			_, err := ds.InsertFn(ctx, testFn)
+			if err != models.ErrFnsDestinationNotSameApp {
+				t.Fatalf("expected %v, got %v", models.ErrFnsDestinationNotSameApp, err)
+			}
+
+			testFn.OnFailure = next.ID
+			testFn, err = ds.InsertFn(ctx, testFn)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, err = ds.UpdateFn(ctx, &models.Fn{ID: testFn.ID, DeadLetter: "nonexistent"})
+			if err != models.ErrFnsNotFound {
+				t.Fatalf("expected %v, got %v", models.ErrFnsNotFound, err)
+			}
+			_, err = ds.UpdateFn(ctx, &models.Fn{ID: testFn.ID, OnSuccess: otherFn.ID})
+			if err != models.ErrFnsDestinationNotSameApp {
+				t.Fatalf("expected %v, got %v", models.ErrFnsDestinationNotSameApp, err)
+			}
+			_, err = ds.UpdateFn(ctx, &models.Fn{ID: testFn.ID, DeadLetter: testFn.ID})
+			if err != models.ErrFnsDestinationSelf {
+				t.Fatalf("expected %v, got %v", models.ErrFnsDestinationSelf, err)
+			}
+			fn, err := ds.UpdateFn(ctx, &models.Fn{ID: testFn.ID, OnSuccess: next.ID})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fn.OnSuccess != next.ID || fn.OnFailure != next.ID {
+				t.Fatalf("expected both destinations to be %q, got %q and %q", next.ID, fn.OnSuccess, fn.OnFailure)
+			}
+		})
+
+		t.Run("function secret config", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+
+			testFn := rp.ValidFn(testApp.ID)
+			testFn.SecretConfig = map[string]string{"DB_PASSWORD": "sealed1"}
+			testFn, err := ds.InsertFn(ctx, testFn)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, err = ds.UpdateFn(ctx, &models.Fn{ID: testFn.ID, SecretConfig: map[string]string{"API_KEY": "sealed2"}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			fn, err := ds.GetFnByID(ctx, testFn.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := models.Config{"DB_PASSWORD": "sealed1", "API_KEY": "sealed2"}
+			if !fn.SecretConfig.Equals(expected) {
+				t.Fatalf("expected stored secret config to be %v, got %v", expected, fn.SecretConfig)
 			}
 		})
 
@@ -941,7 +1363,7 @@ func RunTriggersTest(t *testing.T, dsf DataStoreFunc, rp ResourceProvider) {
 
 			for i := 1; i < 10; i++ {
 				if triggers.Items[i-1].Name > triggers.Items[i].Name {
-					t.Fatalf("Test GetTriggers(page triggers), names out of order, %s, %s", triggers.Items[i-1], triggers.Items[i])
+					t.Fatalf("Test GetTriggers(page triggers), names out of order, %v, %v", triggers.Items[i-1], triggers.Items[i])
 				}
 			}
 
@@ -957,7 +1379,7 @@ func RunTriggersTest(t *testing.T, dsf DataStoreFunc, rp ResourceProvider) {
 
 			for i := 0; i < 5; i++ {
 				if !triggers.Items[i].EqualsWithAnnotationSubset(storedTriggers[i]) {
-					t.Fatalf("Test GetTriggers(first five page triggers), expect equal, %s, %s", triggers.Items[i], storedTriggers[i])
+					t.Fatalf("Test GetTriggers(first five page triggers), expect equal, %v, %v", triggers.Items[i], storedTriggers[i])
 				}
 			}
 
@@ -977,7 +1399,7 @@ func RunTriggersTest(t *testing.T, dsf DataStoreFunc, rp ResourceProvider) {
 
 			for i := 0; i < 5; i++ {
 				if !triggers.Items[i].EqualsWithAnnotationSubset(storedTriggers[i+5]) {
-					t.Fatalf("Test GetTriggers(second five page triggers), expect equal, %s, %s", triggers.Items[i], storedTriggers[i+5])
+					t.Fatalf("Test GetTriggers(second five page triggers), expect equal, %v, %v", triggers.Items[i], storedTriggers[i+5])
 				}
 			}
 
@@ -1125,6 +1547,121 @@ func RunTriggersTest(t *testing.T, dsf DataStoreFunc, rp ResourceProvider) {
 
 		})
 
+		t.Run("trigger methods", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			reader := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			writer := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			otherApp := h.GivenAppInDb(rp.ValidApp())
+			otherFn := h.GivenFnInDb(rp.ValidFn(otherApp.ID))
+
+			newTrigger := rp.ValidTrigger(testApp.ID, reader.ID)
+			newTrigger.Methods = models.HTTPMethods{"GET"}
+			newTrigger.MethodFns = models.MethodFns{"POST": otherFn.ID}
+			_, err := ds.InsertTrigger(ctx, newTrigger)
+			if err != models.ErrTriggerFnIDNotSameApp {
+				t.Fatalf("expected methods mapped to fns of other apps to be refused, got %v", err)
+			}
+
+			newTrigger.MethodFns = models.MethodFns{"POST": writer.ID}
+			testTrigger := h.GivenTriggerInDb(newTrigger)
+			gotTrigger, err := ds.GetTriggerByID(ctx, testTrigger.ID)
+			if err != nil {
+				t.Fatalf("expecting no error, got: %s", err)
+			}
+			if !gotTrigger.Methods.Equals(newTrigger.Methods) || !gotTrigger.MethodFns.Equals(newTrigger.MethodFns) {
+				t.Fatalf("expected methods to be stored, got %v %v", gotTrigger.Methods, gotTrigger.MethodFns)
+			}
+
+			err = ds.RemoveFn(ctx, writer.ID)
+			if err != models.ErrFnsHandlesMethods {
+				t.Fatalf("expected removing a fn handling trigger methods to be refused, got %v", err)
+			}
+
+			_, err = ds.UpdateTrigger(ctx, &models.Trigger{ID: testTrigger.ID, MethodFns: models.MethodFns{"PUT": "notreal"}})
+			if err != models.ErrFnsNotFound {
+				t.Fatalf("expected methods mapped to missing fns to be refused, got %v", err)
+			}
+
+			_, err = ds.UpdateTrigger(ctx, &models.Trigger{ID: testTrigger.ID, Methods: models.HTTPMethods{}, MethodFns: models.MethodFns{}})
+			if err != nil {
+				t.Fatalf("error when updating trigger: %s", err)
+			}
+			gotTrigger, err = ds.GetTriggerByID(ctx, testTrigger.ID)
+			if err != nil {
+				t.Fatalf("expecting no error, got: %s", err)
+			}
+			if len(gotTrigger.Methods) != 0 || len(gotTrigger.MethodFns) != 0 {
+				t.Fatalf("expected methods to be cleared, got %v %v", gotTrigger.Methods, gotTrigger.MethodFns)
+			}
+
+			err = ds.RemoveFn(ctx, writer.ID)
+			if err != nil {
+				t.Fatalf("expected fn no longer handling trigger methods to be removed, got %v", err)
+			}
+		})
+
+		t.Run("trigger cors", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			testFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+
+			newTrigger := rp.ValidTrigger(testApp.ID, testFn.ID)
+			newTrigger.CORS = &models.TriggerCORS{AllowOrigins: []string{"https://example.com"}, AllowHeaders: []string{"X-Token"}, MaxAge: 600}
+			testTrigger := h.GivenTriggerInDb(newTrigger)
+			gotTrigger, err := ds.GetTriggerByID(ctx, testTrigger.ID)
+			if err != nil {
+				t.Fatalf("expecting no error, got: %s", err)
+			}
+			if !gotTrigger.CORS.Equals(newTrigger.CORS) {
+				t.Fatalf("expected cors policy to be stored, got %#v", gotTrigger.CORS)
+			}
+
+			_, err = ds.UpdateTrigger(ctx, &models.Trigger{ID: testTrigger.ID, CORS: &models.TriggerCORS{}})
+			if err != nil {
+				t.Fatalf("error when updating trigger: %s", err)
+			}
+			gotTrigger, err = ds.GetTriggerByID(ctx, testTrigger.ID)
+			if err != nil {
+				t.Fatalf("expecting no error, got: %s", err)
+			}
+			if gotTrigger.CORS != nil {
+				t.Fatalf("expected cors policy to be removed, got %#v", gotTrigger.CORS)
+			}
+		})
+
+		t.Run("trigger signature", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			testFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+
+			newTrigger := rp.ValidTrigger(testApp.ID, testFn.ID)
+			newTrigger.Signature = &models.TriggerSignature{Secret: "secret", Header: "X-Hub-Signature-256", Algorithm: "sha256", Prefix: "sha256="}
+			testTrigger := h.GivenTriggerInDb(newTrigger)
+			gotTrigger, err := ds.GetTriggerByID(ctx, testTrigger.ID)
+			if err != nil {
+				t.Fatalf("expecting no error, got: %s", err)
+			}
+			if !gotTrigger.Signature.Equals(newTrigger.Signature) {
+				t.Fatalf("expected signature to be stored, got %#v", gotTrigger.Signature)
+			}
+
+			_, err = ds.UpdateTrigger(ctx, &models.Trigger{ID: testTrigger.ID, Signature: &models.TriggerSignature{}})
+			if err != nil {
+				t.Fatalf("error when updating trigger: %s", err)
+			}
+			gotTrigger, err = ds.GetTriggerByID(ctx, testTrigger.ID)
+			if err != nil {
+				t.Fatalf("expecting no error, got: %s", err)
+			}
+			if gotTrigger.Signature != nil {
+				t.Fatalf("expected signature to be removed, got %#v", gotTrigger.Signature)
+			}
+		})
+
 		t.Run("remove non-existant", func(t *testing.T) {
 			err := ds.RemoveTrigger(ctx, "nonexistant")
 
@@ -1217,8 +1754,368 @@ func RunTriggerBySourceTests(t *testing.T, dsf DataStoreFunc, rp ResourceProvide
 			}
 		})
 
+		t.Run("get_trigger_templated_http_route", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			testFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+
+			templates := make(map[string]*models.Trigger)
+			for i, source := range []string{"/users/{id}", "/users/{id}/*rest", "/users/me"} {
+				tr := rp.ValidTrigger(testApp.ID, testFn.ID)
+				tr.Name = fmt.Sprintf("template%d", i)
+				tr.Source = source
+				templates[source] = h.GivenTriggerInDb(tr)
+			}
+
+			for path, source := range map[string]string{
+				"/users/1":         "/users/{id}",
+				"/users/1/posts/2": "/users/{id}/*rest",
+				"/users/me":        "/users/me",
+			} {
+				trigger, err := ds.GetTriggerBySource(ctx, testApp.ID, "http", path)
+				if err != nil {
+					t.Fatalf("Expecting trigger for %s, got error %s", path, err)
+				}
+				if !trigger.Equals(templates[source]) {
+					t.Errorf("Expecting %s to match trigger %#v got %#v", path, templates[source], trigger)
+				}
+			}
+
+			_, err := ds.GetTriggerBySource(ctx, testApp.ID, "http", "/teams/1")
+			if err != models.ErrTriggerNotFound {
+				t.Fatalf("Expecting trigger not found, got %s", err)
+			}
+		})
+
+	})
+
+}
+
+func RunDomainsTest(t *testing.T, dsf DataStoreFunc, rp ResourceProvider) {
+	t.Run("domains", func(t *testing.T) {
+		ds := dsf(t)
+		ctx := rp.DefaultCtx()
+
+		t.Run("insert invalid hostname", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			_, err := ds.InsertDomain(ctx, &models.Domain{AppID: testApp.ID, Hostname: "localhost"})
+			if err != models.ErrDomainInvalidHostname {
+				t.Fatalf("expected invalid hostname, got %v", err)
+			}
+		})
+
+		t.Run("insert non-existant app", func(t *testing.T) {
+			_, err := ds.InsertDomain(ctx, &models.Domain{AppID: "notreal", Hostname: "api.example.com"})
+			if err != models.ErrAppsNotFound {
+				t.Fatalf("expected app not found, got %v", err)
+			}
+		})
+
+		t.Run("hostnames are unique across apps", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			otherApp := h.GivenAppInDb(rp.ValidApp())
+
+			domain, err := ds.InsertDomain(ctx, &models.Domain{AppID: testApp.ID, Hostname: "api.example.com"})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if domain.ID == "" || time.Time(domain.CreatedAt).IsZero() {
+				t.Fatalf("expected domain to have an id and created time, got %#v", domain)
+			}
+
+			_, err = ds.InsertDomain(ctx, &models.Domain{AppID: otherApp.ID, Hostname: "api.example.com"})
+			if err != models.ErrDomainExists {
+				t.Fatalf("expected domain exists, got %v", err)
+			}
+
+			got, err := ds.GetDomainByHostname(ctx, "api.example.com")
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !got.Equals(domain) {
+				t.Fatalf("expected %#v, got %#v", domain, got)
+			}
+			got, err = ds.GetDomainByID(ctx, domain.ID)
+			if err != nil || !got.Equals(domain) {
+				t.Fatalf("expected %#v, got %#v %v", domain, got, err)
+			}
+		})
+
+		t.Run("page domains", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			otherApp := h.GivenAppInDb(rp.ValidApp())
+			for _, hostname := range []string{"c.example.com", "a.example.com", "b.example.com"} {
+				if _, err := ds.InsertDomain(ctx, &models.Domain{AppID: testApp.ID, Hostname: hostname}); err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			}
+			if _, err := ds.InsertDomain(ctx, &models.Domain{AppID: otherApp.ID, Hostname: "other.example.com"}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			domains, err := ds.GetDomains(ctx, &models.DomainFilter{AppID: testApp.ID, PerPage: 2})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(domains.Items) != 2 || domains.Items[0].Hostname != "a.example.com" || domains.Items[1].Hostname != "b.example.com" {
+				t.Fatalf("expected the first page of domains in order, got %v", domains.Items)
+			}
+			domains, err = ds.GetDomains(ctx, &models.DomainFilter{AppID: testApp.ID, PerPage: 2, Cursor: domains.NextCursor})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(domains.Items) != 1 || domains.Items[0].Hostname != "c.example.com" {
+				t.Fatalf("expected the last page of domains, got %v", domains.Items)
+			}
+		})
+
+		t.Run("remove domain", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			domain, err := ds.InsertDomain(ctx, &models.Domain{AppID: testApp.ID, Hostname: "api.example.com"})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if err := ds.RemoveDomain(ctx, domain.ID); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if err := ds.RemoveDomain(ctx, domain.ID); err != models.ErrDomainNotFound {
+				t.Fatalf("expected domain not found, got %v", err)
+			}
+			if _, err := ds.GetDomainByHostname(ctx, "api.example.com"); err != models.ErrDomainNotFound {
+				t.Fatalf("expected domain not found, got %v", err)
+			}
+		})
+
+		t.Run("remove app should remove domains", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			domain, err := ds.InsertDomain(ctx, &models.Domain{AppID: testApp.ID, Hostname: "api.example.com"})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if err := ds.RemoveApp(ctx, testApp.ID); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if _, err := ds.GetDomainByID(ctx, domain.ID); err != models.ErrDomainNotFound {
+				t.Fatalf("expected domain not found, got %v", err)
+			}
+		})
 	})
+}
+
+func RunAliasesTest(t *testing.T, dsf DataStoreFunc, rp ResourceProvider) {
+	t.Run("aliases", func(t *testing.T) {
+		ds := dsf(t)
+		ctx := rp.DefaultCtx()
+
+		t.Run("insert invalid alias", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			testFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			_, err := ds.InsertAlias(ctx, &models.Alias{AppID: testApp.ID, Name: "not/valid", FnID: testFn.ID})
+			if err != models.ErrAliasInvalidName {
+				t.Fatalf("expected invalid name, got %v", err)
+			}
+			_, err = ds.InsertAlias(ctx, &models.Alias{AppID: testApp.ID, Name: "prod", FnID: "notreal"})
+			if err != models.ErrFnsNotFound {
+				t.Fatalf("expected fn not found, got %v", err)
+			}
+			otherApp := h.GivenAppInDb(rp.ValidApp())
+			_, err = ds.InsertAlias(ctx, &models.Alias{AppID: otherApp.ID, Name: "prod", FnID: testFn.ID})
+			if err != models.ErrAliasFnIDNotSameApp {
+				t.Fatalf("expected fn of another app, got %v", err)
+			}
+		})
+
+		t.Run("insert non-existant app", func(t *testing.T) {
+			_, err := ds.InsertAlias(ctx, &models.Alias{AppID: "notreal", Name: "prod", FnID: "fn"})
+			if err != models.ErrAppsNotFound {
+				t.Fatalf("expected app not found, got %v", err)
+			}
+		})
+
+		t.Run("names are unique in apps", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			testFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			otherApp := h.GivenAppInDb(rp.ValidApp())
+			otherFn := h.GivenFnInDb(rp.ValidFn(otherApp.ID))
+
+			alias, err := ds.InsertAlias(ctx, &models.Alias{AppID: testApp.ID, Name: "prod", FnID: testFn.ID})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if alias.ID == "" || time.Time(alias.CreatedAt).IsZero() {
+				t.Fatalf("expected alias to have an id and created time, got %#v", alias)
+			}
+
+			_, err = ds.InsertAlias(ctx, &models.Alias{AppID: testApp.ID, Name: "prod", FnID: testFn.ID})
+			if err != models.ErrAliasExists {
+				t.Fatalf("expected alias exists, got %v", err)
+			}
+			if _, err = ds.InsertAlias(ctx, &models.Alias{AppID: otherApp.ID, Name: "prod", FnID: otherFn.ID}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			got, err := ds.GetAliasByName(ctx, testApp.ID, "prod")
+			if err != nil || !got.Equals(alias) {
+				t.Fatalf("expected %#v, got %#v %v", alias, got, err)
+			}
+			got, err = ds.GetAliasByID(ctx, alias.ID)
+			if err != nil || !got.Equals(alias) {
+				t.Fatalf("expected %#v, got %#v %v", alias, got, err)
+			}
+		})
+
+		t.Run("repoint alias", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			blue := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			green := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			otherApp := h.GivenAppInDb(rp.ValidApp())
+			otherFn := h.GivenFnInDb(rp.ValidFn(otherApp.ID))
 
+			alias, err := ds.InsertAlias(ctx, &models.Alias{AppID: testApp.ID, Name: "prod", FnID: blue.ID})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			updated, err := ds.UpdateAlias(ctx, &models.Alias{ID: alias.ID, FnID: green.ID})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if updated.FnID != green.ID || updated.Name != "prod" || updated.AppID != testApp.ID {
+				t.Fatalf("expected alias to point to %s, got %#v", green.ID, updated)
+			}
+			got, err := ds.GetAliasByName(ctx, testApp.ID, "prod")
+			if err != nil || !got.Equals(updated) {
+				t.Fatalf("expected %#v, got %#v %v", updated, got, err)
+			}
+
+			if _, err := ds.UpdateAlias(ctx, &models.Alias{ID: alias.ID, FnID: otherFn.ID}); err != models.ErrAliasFnIDNotSameApp {
+				t.Fatalf("expected fn of another app, got %v", err)
+			}
+			if _, err := ds.UpdateAlias(ctx, &models.Alias{ID: "notreal", FnID: green.ID}); err != models.ErrAliasNotFound {
+				t.Fatalf("expected alias not found, got %v", err)
+			}
+
+			if err := ds.RemoveFn(ctx, green.ID); err != models.ErrFnsAliased {
+				t.Fatalf("expected removing the fn an alias points to to be refused, got %v", err)
+			}
+			if err := ds.RemoveFn(ctx, blue.ID); err != nil {
+				t.Fatalf("expected the fn the alias no longer points to to be removed, got %v", err)
+			}
+		})
+
+		t.Run("page aliases", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			testFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			otherFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			for _, name := range []string{"c", "a", "b"} {
+				if _, err := ds.InsertAlias(ctx, &models.Alias{AppID: testApp.ID, Name: name, FnID: testFn.ID}); err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			}
+			if _, err := ds.InsertAlias(ctx, &models.Alias{AppID: testApp.ID, Name: "d", FnID: otherFn.ID}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			aliases, err := ds.GetAliases(ctx, &models.AliasFilter{AppID: testApp.ID, FnID: testFn.ID, PerPage: 2})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(aliases.Items) != 2 || aliases.Items[0].Name != "a" || aliases.Items[1].Name != "b" {
+				t.Fatalf("expected the first page of aliases in order, got %v", aliases.Items)
+			}
+			aliases, err = ds.GetAliases(ctx, &models.AliasFilter{AppID: testApp.ID, FnID: testFn.ID, PerPage: 2, Cursor: aliases.NextCursor})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(aliases.Items) != 1 || aliases.Items[0].Name != "c" {
+				t.Fatalf("expected the last page of aliases, got %v", aliases.Items)
+			}
+		})
+
+		t.Run("triggers target aliases", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			testFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+
+			trigger := rp.ValidTrigger(testApp.ID, "")
+			trigger.Alias = "prod"
+			if _, err := ds.InsertTrigger(ctx, trigger); err != models.ErrAliasNotFound {
+				t.Fatalf("expected alias not found, got %v", err)
+			}
+
+			alias, err := ds.InsertAlias(ctx, &models.Alias{AppID: testApp.ID, Name: "prod", FnID: testFn.ID})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			trigger, err = ds.InsertTrigger(ctx, trigger)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			got, err := ds.GetTriggerByID(ctx, trigger.ID)
+			if err != nil || got.Alias != "prod" || got.FnID != "" {
+				t.Fatalf("expected the trigger to target the alias, got %#v %v", got, err)
+			}
+
+			if err := ds.RemoveAlias(ctx, alias.ID); err != models.ErrAliasInUse {
+				t.Fatalf("expected alias in use, got %v", err)
+			}
+
+			// pointing the trigger to a fn frees the alias
+			updated, err := ds.UpdateTrigger(ctx, &models.Trigger{ID: trigger.ID, FnID: testFn.ID})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if updated.FnID != testFn.ID || updated.Alias != "" {
+				t.Fatalf("expected the trigger to target the fn, got %#v", updated)
+			}
+			if err := ds.RemoveAlias(ctx, alias.ID); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if err := ds.RemoveAlias(ctx, alias.ID); err != models.ErrAliasNotFound {
+				t.Fatalf("expected alias not found, got %v", err)
+			}
+			if _, err := ds.UpdateTrigger(ctx, &models.Trigger{ID: trigger.ID, Alias: "prod"}); err != models.ErrAliasNotFound {
+				t.Fatalf("expected alias not found, got %v", err)
+			}
+		})
+
+		t.Run("remove app should remove aliases", func(t *testing.T) {
+			h := NewHarness(t, ctx, ds)
+			defer h.Cleanup()
+			testApp := h.GivenAppInDb(rp.ValidApp())
+			testFn := h.GivenFnInDb(rp.ValidFn(testApp.ID))
+			alias, err := ds.InsertAlias(ctx, &models.Alias{AppID: testApp.ID, Name: "prod", FnID: testFn.ID})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if err := ds.RemoveApp(ctx, testApp.ID); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if _, err := ds.GetAliasByID(ctx, alias.ID); err != models.ErrAliasNotFound {
+				t.Fatalf("expected alias not found, got %v", err)
+			}
+		})
+	})
 }
 
 func RunAllTests(t *testing.T, dsf DataStoreFunc, rp ResourceProvider) {
@@ -1233,5 +2130,7 @@ func RunAllTests(t *testing.T, dsf DataStoreFunc, rp ResourceProvider) {
 	RunFnsTest(t, dsf, rp)
 	RunTriggersTest(t, dsf, rp)
 	RunTriggerBySourceTests(t, dsf, rp)
+	RunDomainsTest(t, dsf, rp)
+	RunAliasesTest(t, dsf, rp)
 
 }
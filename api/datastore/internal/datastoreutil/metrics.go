@@ -88,6 +88,72 @@ func (m *metricds) GetTriggers(ctx context.Context, filter *models.TriggerFilter
 	return m.ds.GetTriggers(ctx, filter)
 }
 
+func (m *metricds) InsertDomain(ctx context.Context, domain *models.Domain) (*models.Domain, error) {
+	ctx, span := trace.StartSpan(ctx, "ds_insert_domain")
+	defer span.End()
+	return m.ds.InsertDomain(ctx, domain)
+}
+
+func (m *metricds) GetDomainByID(ctx context.Context, domainID string) (*models.Domain, error) {
+	ctx, span := trace.StartSpan(ctx, "ds_get_domain_by_id")
+	defer span.End()
+	return m.ds.GetDomainByID(ctx, domainID)
+}
+
+func (m *metricds) GetDomainByHostname(ctx context.Context, hostname string) (*models.Domain, error) {
+	ctx, span := trace.StartSpan(ctx, "ds_get_domain_by_hostname")
+	defer span.End()
+	return m.ds.GetDomainByHostname(ctx, hostname)
+}
+
+func (m *metricds) GetDomains(ctx context.Context, filter *models.DomainFilter) (*models.DomainList, error) {
+	ctx, span := trace.StartSpan(ctx, "ds_get_domains")
+	defer span.End()
+	return m.ds.GetDomains(ctx, filter)
+}
+
+func (m *metricds) RemoveDomain(ctx context.Context, domainID string) error {
+	ctx, span := trace.StartSpan(ctx, "ds_remove_domain")
+	defer span.End()
+	return m.ds.RemoveDomain(ctx, domainID)
+}
+
+func (m *metricds) InsertAlias(ctx context.Context, alias *models.Alias) (*models.Alias, error) {
+	ctx, span := trace.StartSpan(ctx, "ds_insert_alias")
+	defer span.End()
+	return m.ds.InsertAlias(ctx, alias)
+}
+
+func (m *metricds) UpdateAlias(ctx context.Context, alias *models.Alias) (*models.Alias, error) {
+	ctx, span := trace.StartSpan(ctx, "ds_update_alias")
+	defer span.End()
+	return m.ds.UpdateAlias(ctx, alias)
+}
+
+func (m *metricds) GetAliasByID(ctx context.Context, aliasID string) (*models.Alias, error) {
+	ctx, span := trace.StartSpan(ctx, "ds_get_alias_by_id")
+	defer span.End()
+	return m.ds.GetAliasByID(ctx, aliasID)
+}
+
+func (m *metricds) GetAliasByName(ctx context.Context, appID, name string) (*models.Alias, error) {
+	ctx, span := trace.StartSpan(ctx, "ds_get_alias_by_name")
+	defer span.End()
+	return m.ds.GetAliasByName(ctx, appID, name)
+}
+
+func (m *metricds) GetAliases(ctx context.Context, filter *models.AliasFilter) (*models.AliasList, error) {
+	ctx, span := trace.StartSpan(ctx, "ds_get_aliases")
+	defer span.End()
+	return m.ds.GetAliases(ctx, filter)
+}
+
+func (m *metricds) RemoveAlias(ctx context.Context, aliasID string) error {
+	ctx, span := trace.StartSpan(ctx, "ds_remove_alias")
+	defer span.End()
+	return m.ds.RemoveAlias(ctx, aliasID)
+}
+
 func (m *metricds) InsertFn(ctx context.Context, fn *models.Fn) (*models.Fn, error) {
 	ctx, span := trace.StartSpan(ctx, "ds_insert_func")
 	defer span.End()
@@ -112,6 +178,18 @@ func (m *metricds) GetFnByID(ctx context.Context, fnID string) (*models.Fn, erro
 	return m.ds.GetFnByID(ctx, fnID)
 }
 
+func (m *metricds) GetFnRevisions(ctx context.Context, filter *models.FnRevisionFilter) (*models.FnRevisionList, error) {
+	ctx, span := trace.StartSpan(ctx, "ds_get_func_revisions")
+	defer span.End()
+	return m.ds.GetFnRevisions(ctx, filter)
+}
+
+func (m *metricds) RollbackFn(ctx context.Context, fnID, revisionID string) (*models.Fn, error) {
+	ctx, span := trace.StartSpan(ctx, "ds_rollback_func")
+	defer span.End()
+	return m.ds.RollbackFn(ctx, fnID, revisionID)
+}
+
 func (m *metricds) RemoveFn(ctx context.Context, fnID string) error {
 	ctx, span := trace.StartSpan(ctx, "ds_remove_func")
 	defer span.End()
@@ -99,6 +99,98 @@ func (v *validator) RemoveTrigger(ctx context.Context, triggerID string) error {
 	return v.Datastore.RemoveTrigger(ctx, triggerID)
 }
 
+func (v *validator) InsertDomain(ctx context.Context, d *models.Domain) (*models.Domain, error) {
+	if d.ID != "" {
+		return nil, models.ErrDomainIDProvided
+	}
+	if !time.Time(d.CreatedAt).IsZero() {
+		return nil, models.ErrCreatedAtProvided
+	}
+
+	return v.Datastore.InsertDomain(ctx, d)
+}
+
+func (v *validator) GetDomainByID(ctx context.Context, domainID string) (*models.Domain, error) {
+	if domainID == "" {
+		return nil, models.ErrMissingID
+	}
+
+	return v.Datastore.GetDomainByID(ctx, domainID)
+}
+
+func (v *validator) GetDomainByHostname(ctx context.Context, hostname string) (*models.Domain, error) {
+	if hostname == "" {
+		return nil, models.ErrDomainMissingHostname
+	}
+
+	return v.Datastore.GetDomainByHostname(ctx, hostname)
+}
+
+func (v *validator) GetDomains(ctx context.Context, filter *models.DomainFilter) (*models.DomainList, error) {
+	if filter.AppID == "" {
+		return nil, models.ErrDomainMissingAppID
+	}
+
+	return v.Datastore.GetDomains(ctx, filter)
+}
+
+func (v *validator) RemoveDomain(ctx context.Context, domainID string) error {
+	if domainID == "" {
+		return models.ErrMissingID
+	}
+
+	return v.Datastore.RemoveDomain(ctx, domainID)
+}
+
+func (v *validator) InsertAlias(ctx context.Context, a *models.Alias) (*models.Alias, error) {
+	if a.ID != "" {
+		return nil, models.ErrAliasIDProvided
+	}
+	if !time.Time(a.CreatedAt).IsZero() {
+		return nil, models.ErrCreatedAtProvided
+	}
+	if !time.Time(a.UpdatedAt).IsZero() {
+		return nil, models.ErrUpdatedAtProvided
+	}
+
+	return v.Datastore.InsertAlias(ctx, a)
+}
+
+func (v *validator) GetAliasByID(ctx context.Context, aliasID string) (*models.Alias, error) {
+	if aliasID == "" {
+		return nil, models.ErrMissingID
+	}
+
+	return v.Datastore.GetAliasByID(ctx, aliasID)
+}
+
+func (v *validator) GetAliasByName(ctx context.Context, appID, name string) (*models.Alias, error) {
+	if appID == "" {
+		return nil, models.ErrAliasMissingAppID
+	}
+	if name == "" {
+		return nil, models.ErrAliasMissingName
+	}
+
+	return v.Datastore.GetAliasByName(ctx, appID, name)
+}
+
+func (v *validator) GetAliases(ctx context.Context, filter *models.AliasFilter) (*models.AliasList, error) {
+	if filter.AppID == "" {
+		return nil, models.ErrAliasMissingAppID
+	}
+
+	return v.Datastore.GetAliases(ctx, filter)
+}
+
+func (v *validator) RemoveAlias(ctx context.Context, aliasID string) error {
+	if aliasID == "" {
+		return models.ErrMissingID
+	}
+
+	return v.Datastore.RemoveAlias(ctx, aliasID)
+}
+
 func (v *validator) InsertFn(ctx context.Context, fn *models.Fn) (*models.Fn, error) {
 	if fn == nil {
 		return nil, models.ErrDatastoreEmptyFn
@@ -132,6 +224,22 @@ func (v *validator) GetFns(ctx context.Context, filter *models.FnFilter) (*model
 	return v.Datastore.GetFns(ctx, filter)
 }
 
+func (v *validator) GetFnRevisions(ctx context.Context, filter *models.FnRevisionFilter) (*models.FnRevisionList, error) {
+	if filter.FnID == "" {
+		return nil, models.ErrDatastoreEmptyFnID
+	}
+
+	return v.Datastore.GetFnRevisions(ctx, filter)
+}
+
+func (v *validator) RollbackFn(ctx context.Context, fnID, revisionID string) (*models.Fn, error) {
+	if fnID == "" {
+		return nil, models.ErrDatastoreEmptyFnID
+	}
+
+	return v.Datastore.RollbackFn(ctx, fnID, revisionID)
+}
+
 func (v *validator) RemoveFn(ctx context.Context, fnID string) error {
 	if fnID == "" {
 		return models.ErrDatastoreEmptyFnID
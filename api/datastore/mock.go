@@ -18,6 +18,10 @@ type mock struct {
 	Apps     []*models.App
 	Fns      []*models.Fn
 	Triggers []*models.Trigger
+	Domains  []*models.Domain
+	Aliases  []*models.Alias
+
+	FnRevisions []*models.FnRevision
 }
 
 // NewMock creates a new mock datastore
@@ -34,6 +38,18 @@ func (m *mock) GetTriggerBySource(ctx context.Context, appID string, triggerType
 		}
 	}
 
+	if triggerType == models.TriggerTypeHTTP {
+		var candidates []*models.Trigger
+		for _, t := range m.Triggers {
+			if t.AppID == appID && t.Type == triggerType {
+				candidates = append(candidates, t)
+			}
+		}
+		if t := models.BestTriggerSourceMatch(candidates, source); t != nil {
+			return t, nil
+		}
+	}
+
 	return nil, models.ErrTriggerNotFound
 }
 
@@ -48,6 +64,10 @@ func NewMockInit(args ...interface{}) models.Datastore {
 			mocker.Fns = x
 		case []*models.Trigger:
 			mocker.Triggers = x
+		case []*models.Domain:
+			mocker.Domains = x
+		case []*models.Alias:
+			mocker.Aliases = x
 
 		default:
 			panic("not accounted for data type sent to mock init. add it")
@@ -165,6 +185,7 @@ func (m *mock) RemoveApp(ctx context.Context, appID string) error {
 		if a.ID == appID {
 			var newFns []*models.Fn
 			var newTriggers []*models.Trigger
+			var newDomains []*models.Domain
 			newApps := append(m.Apps[0:i], m.Apps[i+1:]...)
 
 			for _, fn := range m.Fns {
@@ -179,8 +200,31 @@ func (m *mock) RemoveApp(ctx context.Context, appID string) error {
 				}
 			}
 
+			for _, d := range m.Domains {
+				if d.AppID != appID {
+					newDomains = append(newDomains, d)
+				}
+			}
+
+			var newRevisions []*models.FnRevision
+			for _, r := range m.FnRevisions {
+				if r.AppID != appID {
+					newRevisions = append(newRevisions, r)
+				}
+			}
+			m.FnRevisions = newRevisions
+
+			var newAliases []*models.Alias
+			for _, a := range m.Aliases {
+				if a.AppID != appID {
+					newAliases = append(newAliases, a)
+				}
+			}
+			m.Aliases = newAliases
+
 			m.Apps = newApps
 			m.Triggers = newTriggers
+			m.Domains = newDomains
 			m.Fns = newFns
 			return nil
 
@@ -211,6 +255,12 @@ func (m *mock) InsertFn(ctx context.Context, fn *models.Fn) (*models.Fn, error)
 	if err != nil {
 		return nil, err
 	}
+	if err := m.checkTrafficFns(ctx, nil, fn); err != nil {
+		return nil, err
+	}
+	if err := m.checkDestinationFns(ctx, nil, fn); err != nil {
+		return nil, err
+	}
 
 	m.Fns = append(m.Fns, cl)
 
@@ -227,6 +277,13 @@ func (m *mock) UpdateFn(ctx context.Context, fn *models.Fn) (*models.Fn, error)
 			if err != nil {
 				return nil, err
 			}
+			if err := m.checkTrafficFns(ctx, f, clone); err != nil {
+				return nil, err
+			}
+			if err := m.checkDestinationFns(ctx, f, clone); err != nil {
+				return nil, err
+			}
+			m.recordFnRevision(f, clone)
 			*f = *clone
 			return f, nil
 		}
@@ -235,6 +292,146 @@ func (m *mock) UpdateFn(ctx context.Context, fn *models.Fn) (*models.Fn, error)
 	return nil, models.ErrFnsNotFound
 }
 
+func (m *mock) checkTrafficFns(ctx context.Context, original, fn *models.Fn) error {
+	if fn.Traffic == nil {
+		return nil
+	}
+	splitTo := make(map[string]bool)
+	if original != nil && original.Traffic != nil {
+		for _, fnID := range original.Traffic.FnIDs() {
+			splitTo[fnID] = true
+		}
+	}
+	for _, fnID := range fn.Traffic.FnIDs() {
+		if splitTo[fnID] {
+			continue
+		}
+		f, err := m.GetFnByID(ctx, fnID)
+		if err != nil {
+			return err
+		}
+		if f.AppID != fn.AppID {
+			return models.ErrFnsTrafficFnNotSameApp
+		}
+	}
+	return nil
+}
+
+func (m *mock) checkDestinationFns(ctx context.Context, original, fn *models.Fn) error {
+	sentTo := make(map[string]bool)
+	if original != nil {
+		for _, fnID := range original.DestinationFnIDs() {
+			sentTo[fnID] = true
+		}
+	}
+	for _, fnID := range fn.DestinationFnIDs() {
+		if sentTo[fnID] {
+			continue
+		}
+		f, err := m.GetFnByID(ctx, fnID)
+		if err != nil {
+			return err
+		}
+		if f.AppID != fn.AppID {
+			return models.ErrFnsDestinationNotSameApp
+		}
+	}
+	return nil
+}
+
+// recordFnRevision records how f ran, if updating it to updated changes that,
+// keeping the latest models.MaxFnRevisions of f
+func (m *mock) recordFnRevision(f, updated *models.Fn) {
+	if f.RevisionEquals(updated) {
+		return
+	}
+	r := models.NewFnRevision(f)
+	r.ID = id.New().String()
+	m.FnRevisions = append(m.FnRevisions, r)
+
+	// revisions are recorded in order, the oldest of f go first
+	excess := -models.MaxFnRevisions
+	for _, r := range m.FnRevisions {
+		if r.FnID == f.ID {
+			excess++
+		}
+	}
+	var revisions []*models.FnRevision
+	for _, r := range m.FnRevisions {
+		if r.FnID == f.ID && excess > 0 {
+			excess--
+			continue
+		}
+		revisions = append(revisions, r)
+	}
+	m.FnRevisions = revisions
+}
+
+func (m *mock) GetFnRevisions(ctx context.Context, filter *models.FnRevisionFilter) (*models.FnRevisionList, error) {
+	var cursor string
+	if filter.Cursor != "" {
+		s, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = string(s)
+	}
+
+	// revisions are recorded in order, newest last
+	res := []*models.FnRevision{}
+	for i := len(m.FnRevisions) - 1; i >= 0; i-- {
+		r := m.FnRevisions[i]
+		if filter.PerPage > 0 && len(res) == filter.PerPage {
+			break
+		}
+		if r.FnID == filter.FnID && (cursor == "" || strings.Compare(r.ID, cursor) < 0) {
+			clone := *r
+			res = append(res, &clone)
+		}
+	}
+
+	var nextCursor string
+	if len(res) > 0 && len(res) == filter.PerPage {
+		last := []byte(res[len(res)-1].ID)
+		nextCursor = base64.RawURLEncoding.EncodeToString(last)
+	}
+
+	return &models.FnRevisionList{
+		NextCursor: nextCursor,
+		Items:      res,
+	}, nil
+}
+
+func (m *mock) RollbackFn(ctx context.Context, fnID, revisionID string) (*models.Fn, error) {
+	var f *models.Fn
+	for _, fn := range m.Fns {
+		if fn.ID == fnID {
+			f = fn
+		}
+	}
+	if f == nil {
+		return nil, models.ErrFnsNotFound
+	}
+
+	var revision *models.FnRevision
+	for i := len(m.FnRevisions) - 1; i >= 0; i-- {
+		r := m.FnRevisions[i]
+		if r.FnID == fnID && (revisionID == "" || r.ID == revisionID) {
+			revision = r
+			break
+		}
+	}
+	if revision == nil {
+		return nil, models.ErrFnRevisionNotFound
+	}
+
+	clone := f.Clone()
+	clone.Rollback(revision)
+	m.recordFnRevision(f, clone)
+	*f = *clone
+	return f.Clone(), nil
+}
+
 type sortF []*models.Fn
 
 func (s sortF) Len() int           { return len(s) }
@@ -293,7 +490,24 @@ func (m *mock) GetFnByID(ctx context.Context, fnID string) (*models.Fn, error) {
 func (m *mock) RemoveFn(ctx context.Context, fnID string) error {
 	for i, f := range m.Fns {
 		if f.ID == fnID {
+			for _, a := range m.Aliases {
+				if a.FnID == f.ID {
+					return models.ErrFnsAliased
+				}
+			}
+			for _, t := range m.Triggers {
+				for _, methodFnID := range t.MethodFns {
+					if t.FnID != f.ID && methodFnID == f.ID {
+						return models.ErrFnsHandlesMethods
+					}
+				}
+			}
 			m.Fns = append(m.Fns[:i], m.Fns[i+1:]...)
+			for _, other := range m.Fns {
+				if other.Traffic != nil {
+					other.Traffic = other.Traffic.Without(f.ID)
+				}
+			}
 			var newTriggers []*models.Trigger
 			for _, t := range m.Triggers {
 				if t.FnID != f.ID {
@@ -301,7 +515,15 @@ func (m *mock) RemoveFn(ctx context.Context, fnID string) error {
 				}
 			}
 
+			var newRevisions []*models.FnRevision
+			for _, r := range m.FnRevisions {
+				if r.FnID != f.ID {
+					newRevisions = append(newRevisions, r)
+				}
+			}
+
 			m.Triggers = newTriggers
+			m.FnRevisions = newRevisions
 			return nil
 		}
 	}
@@ -314,13 +536,22 @@ func (m *mock) InsertTrigger(ctx context.Context, trigger *models.Trigger) (*mod
 	if err != nil {
 		return nil, err
 	}
-	fn, err := m.GetFnByID(ctx, trigger.FnID)
-	if err != nil {
-		return nil, err
-	}
+	if trigger.Alias != "" {
+		if _, err := m.GetAliasByName(ctx, trigger.AppID, trigger.Alias); err != nil {
+			return nil, err
+		}
+	} else {
+		fn, err := m.GetFnByID(ctx, trigger.FnID)
+		if err != nil {
+			return nil, err
+		}
 
-	if fn.AppID != trigger.AppID {
-		return nil, models.ErrTriggerFnIDNotSameApp
+		if fn.AppID != trigger.AppID {
+			return nil, models.ErrTriggerFnIDNotSameApp
+		}
+	}
+	if err := m.checkMethodFns(ctx, trigger); err != nil {
+		return nil, err
 	}
 
 	for _, t := range m.Triggers {
@@ -360,6 +591,14 @@ func (m *mock) UpdateTrigger(ctx context.Context, trigger *models.Trigger) (*mod
 			if err != nil {
 				return nil, err
 			}
+			if cl.Alias != "" {
+				if _, err := m.GetAliasByName(ctx, cl.AppID, cl.Alias); err != nil {
+					return nil, err
+				}
+			}
+			if err := m.checkMethodFns(ctx, cl); err != nil {
+				return nil, err
+			}
 			*t = *cl
 			return cl.Clone(), nil
 		}
@@ -367,6 +606,19 @@ func (m *mock) UpdateTrigger(ctx context.Context, trigger *models.Trigger) (*mod
 	return nil, models.ErrTriggerNotFound
 }
 
+func (m *mock) checkMethodFns(ctx context.Context, trigger *models.Trigger) error {
+	for _, fnID := range trigger.MethodFns {
+		fn, err := m.GetFnByID(ctx, fnID)
+		if err != nil {
+			return err
+		}
+		if fn.AppID != trigger.AppID {
+			return models.ErrTriggerFnIDNotSameApp
+		}
+	}
+	return nil
+}
+
 func (m *mock) GetTrigger(ctx context.Context, appId, fnId, triggerName string) (*models.Trigger, error) {
 	for _, t := range m.Triggers {
 		if t.AppID == appId && t.FnID == fnId && t.Name == triggerName {
@@ -452,6 +704,222 @@ func (m *mock) RemoveTrigger(ctx context.Context, triggerID string) error {
 	return models.ErrTriggerNotFound
 }
 
+func (m *mock) InsertDomain(ctx context.Context, domain *models.Domain) (*models.Domain, error) {
+	if _, err := m.GetAppByID(ctx, domain.AppID); err != nil {
+		return nil, err
+	}
+	if err := domain.Validate(); err != nil {
+		return nil, err
+	}
+	for _, d := range m.Domains {
+		if d.Hostname == domain.Hostname {
+			return nil, models.ErrDomainExists
+		}
+	}
+
+	cl := domain.Clone()
+	cl.CreatedAt = common.DateTime(time.Now())
+	cl.ID = id.New().String()
+	m.Domains = append(m.Domains, cl)
+	return cl.Clone(), nil
+}
+
+func (m *mock) GetDomainByID(ctx context.Context, domainID string) (*models.Domain, error) {
+	for _, d := range m.Domains {
+		if d.ID == domainID {
+			return d.Clone(), nil
+		}
+	}
+	return nil, models.ErrDomainNotFound
+}
+
+func (m *mock) GetDomainByHostname(ctx context.Context, hostname string) (*models.Domain, error) {
+	for _, d := range m.Domains {
+		if d.Hostname == hostname {
+			return d.Clone(), nil
+		}
+	}
+	return nil, models.ErrDomainNotFound
+}
+
+type sortD []*models.Domain
+
+func (s sortD) Len() int           { return len(s) }
+func (s sortD) Less(i, j int) bool { return strings.Compare(s[i].Hostname, s[j].Hostname) < 0 }
+func (s sortD) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func (m *mock) GetDomains(ctx context.Context, filter *models.DomainFilter) (*models.DomainList, error) {
+	sort.Sort(sortD(m.Domains))
+
+	var cursor string
+	if filter.Cursor != "" {
+		s, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = string(s)
+	}
+
+	res := []*models.Domain{}
+	for _, d := range m.Domains {
+		if filter.PerPage > 0 && len(res) == filter.PerPage {
+			break
+		}
+		if d.AppID == filter.AppID && strings.Compare(cursor, d.Hostname) < 0 {
+			res = append(res, d.Clone())
+		}
+	}
+
+	var nextCursor string
+	if len(res) > 0 && len(res) == filter.PerPage {
+		last := []byte(res[len(res)-1].Hostname)
+		nextCursor = base64.RawURLEncoding.EncodeToString(last)
+	}
+
+	return &models.DomainList{
+		NextCursor: nextCursor,
+		Items:      res,
+	}, nil
+}
+
+func (m *mock) RemoveDomain(ctx context.Context, domainID string) error {
+	for i, d := range m.Domains {
+		if d.ID == domainID {
+			m.Domains = append(m.Domains[:i], m.Domains[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrDomainNotFound
+}
+
+func (m *mock) InsertAlias(ctx context.Context, alias *models.Alias) (*models.Alias, error) {
+	if _, err := m.GetAppByID(ctx, alias.AppID); err != nil {
+		return nil, err
+	}
+	if err := alias.Validate(); err != nil {
+		return nil, err
+	}
+	if err := m.checkAliasFn(ctx, alias); err != nil {
+		return nil, err
+	}
+	for _, a := range m.Aliases {
+		if a.AppID == alias.AppID && a.Name == alias.Name {
+			return nil, models.ErrAliasExists
+		}
+	}
+
+	cl := alias.Clone()
+	cl.CreatedAt = common.DateTime(time.Now())
+	cl.UpdatedAt = cl.CreatedAt
+	cl.ID = id.New().String()
+	m.Aliases = append(m.Aliases, cl)
+	return cl.Clone(), nil
+}
+
+func (m *mock) UpdateAlias(ctx context.Context, alias *models.Alias) (*models.Alias, error) {
+	for _, a := range m.Aliases {
+		if a.ID == alias.ID {
+			cl := a.Clone()
+			cl.Update(alias)
+			if err := cl.Validate(); err != nil {
+				return nil, err
+			}
+			if err := m.checkAliasFn(ctx, cl); err != nil {
+				return nil, err
+			}
+			*a = *cl
+			return cl.Clone(), nil
+		}
+	}
+	return nil, models.ErrAliasNotFound
+}
+
+func (m *mock) checkAliasFn(ctx context.Context, alias *models.Alias) error {
+	fn, err := m.GetFnByID(ctx, alias.FnID)
+	if err != nil {
+		return err
+	}
+	if fn.AppID != alias.AppID {
+		return models.ErrAliasFnIDNotSameApp
+	}
+	return nil
+}
+
+func (m *mock) GetAliasByID(ctx context.Context, aliasID string) (*models.Alias, error) {
+	for _, a := range m.Aliases {
+		if a.ID == aliasID {
+			return a.Clone(), nil
+		}
+	}
+	return nil, models.ErrAliasNotFound
+}
+
+func (m *mock) GetAliasByName(ctx context.Context, appID, name string) (*models.Alias, error) {
+	for _, a := range m.Aliases {
+		if a.AppID == appID && a.Name == name {
+			return a.Clone(), nil
+		}
+	}
+	return nil, models.ErrAliasNotFound
+}
+
+type sortAl []*models.Alias
+
+func (s sortAl) Len() int           { return len(s) }
+func (s sortAl) Less(i, j int) bool { return strings.Compare(s[i].Name, s[j].Name) < 0 }
+func (s sortAl) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func (m *mock) GetAliases(ctx context.Context, filter *models.AliasFilter) (*models.AliasList, error) {
+	sort.Sort(sortAl(m.Aliases))
+
+	var cursor string
+	if filter.Cursor != "" {
+		s, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = string(s)
+	}
+
+	res := []*models.Alias{}
+	for _, a := range m.Aliases {
+		if filter.PerPage > 0 && len(res) == filter.PerPage {
+			break
+		}
+		if a.AppID == filter.AppID &&
+			(filter.FnID == "" || a.FnID == filter.FnID) &&
+			strings.Compare(cursor, a.Name) < 0 {
+			res = append(res, a.Clone())
+		}
+	}
+
+	var nextCursor string
+	if len(res) > 0 && len(res) == filter.PerPage {
+		last := []byte(res[len(res)-1].Name)
+		nextCursor = base64.RawURLEncoding.EncodeToString(last)
+	}
+
+	return &models.AliasList{
+		NextCursor: nextCursor,
+		Items:      res,
+	}, nil
+}
+
+func (m *mock) RemoveAlias(ctx context.Context, aliasID string) error {
+	for i, a := range m.Aliases {
+		if a.ID == aliasID {
+			for _, t := range m.Triggers {
+				if t.AppID == a.AppID && t.Alias == a.Name {
+					return models.ErrAliasInUse
+				}
+			}
+			m.Aliases = append(m.Aliases[:i], m.Aliases[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrAliasNotFound
+}
+
 func (m *mock) Close() error {
 	return nil
 }
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/fnproject/fn/api/datastore/sql/migratex"
+	"github.com/jmoiron/sqlx"
+)
+
+func up29(ctx context.Context, tx *sqlx.Tx) error {
+	// existing fns are unlimited, which is what they have been all along
+	_, err := tx.ExecContext(ctx, "ALTER TABLE fns ADD cpus int NOT NULL DEFAULT 0;")
+	return err
+}
+
+func down29(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, "ALTER TABLE fns DROP COLUMN cpus;")
+	return err
+}
+
+func init() {
+	Migrations = append(Migrations, &migratex.MigFields{
+		VersionFunc: vfunc(29),
+		UpFunc:      up29,
+		DownFunc:    down29,
+	})
+}
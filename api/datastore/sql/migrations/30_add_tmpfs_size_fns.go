@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/fnproject/fn/api/datastore/sql/migratex"
+	"github.com/jmoiron/sqlx"
+)
+
+func up30(ctx context.Context, tx *sqlx.Tx) error {
+	// existing fns get the agent's default tmpfs, as they have all along
+	_, err := tx.ExecContext(ctx, "ALTER TABLE fns ADD tmpfs_size int NOT NULL DEFAULT 0;")
+	return err
+}
+
+func down30(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, "ALTER TABLE fns DROP COLUMN tmpfs_size;")
+	return err
+}
+
+func init() {
+	Migrations = append(Migrations, &migratex.MigFields{
+		VersionFunc: vfunc(30),
+		UpFunc:      up30,
+		DownFunc:    down30,
+	})
+}
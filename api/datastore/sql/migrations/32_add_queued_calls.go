@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/fnproject/fn/api/datastore/sql/migratex"
+	"github.com/jmoiron/sqlx"
+)
+
+func up32(ctx context.Context, tx *sqlx.Tx) error {
+	createQuery := `CREATE TABLE IF NOT EXISTS queued_calls (
+	id varchar(256) NOT NULL PRIMARY KEY,
+	app_id varchar(256),
+	fn_id varchar(256) NOT NULL,
+	call text NOT NULL,
+	payload text NOT NULL,
+	due_at bigint NOT NULL,
+	reserved_until bigint NOT NULL DEFAULT 0
+);`
+	_, err := tx.ExecContext(ctx, createQuery)
+	return err
+}
+
+func down32(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, "DROP TABLE queued_calls;")
+	return err
+}
+
+func init() {
+	Migrations = append(Migrations, &migratex.MigFields{
+		VersionFunc: vfunc(32),
+		UpFunc:      up32,
+		DownFunc:    down32,
+	})
+}
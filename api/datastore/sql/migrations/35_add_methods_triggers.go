@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/fnproject/fn/api/datastore/sql/migratex"
+	"github.com/jmoiron/sqlx"
+)
+
+func up35(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, "ALTER TABLE triggers ADD methods varchar(1024) NOT NULL DEFAULT '';")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE triggers ADD method_fns varchar(4096) NOT NULL DEFAULT '';")
+	return err
+}
+
+func down35(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, "ALTER TABLE triggers DROP COLUMN methods;")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE triggers DROP COLUMN method_fns;")
+	return err
+}
+
+func init() {
+	Migrations = append(Migrations, &migratex.MigFields{
+		VersionFunc: vfunc(35),
+		UpFunc:      up35,
+		DownFunc:    down35,
+	})
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/fnproject/fn/api/datastore/sql/migratex"
+	"github.com/jmoiron/sqlx"
+)
+
+func up39(ctx context.Context, tx *sqlx.Tx) error {
+	createQuery := `CREATE TABLE IF NOT EXISTS fn_revisions (
+	id varchar(256) NOT NULL PRIMARY KEY,
+	fn_id varchar(256) NOT NULL,
+	app_id varchar(256) NOT NULL,
+	image varchar(256) NOT NULL,
+	memory int NOT NULL,
+	timeout int NOT NULL,
+	idle_timeout int NOT NULL,
+	cpus int NOT NULL DEFAULT 0,
+	tmpfs_size int NOT NULL DEFAULT 0,
+	config text NOT NULL,
+	annotations text NOT NULL,
+	created_at varchar(256) NOT NULL
+);`
+	_, err := tx.ExecContext(ctx, createQuery)
+	return err
+}
+
+func down39(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, "DROP TABLE fn_revisions;")
+	return err
+}
+
+func init() {
+	Migrations = append(Migrations, &migratex.MigFields{
+		VersionFunc: vfunc(39),
+		UpFunc:      up39,
+		DownFunc:    down39,
+	})
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/fnproject/fn/api/datastore/sql/migratex"
+	"github.com/jmoiron/sqlx"
+)
+
+func up46(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, "CREATE INDEX calls_fn_id_id ON calls (fn_id, id);")
+	return err
+}
+
+func down46(ctx context.Context, tx *sqlx.Tx) error {
+	switch tx.DriverName() {
+	case "mysql":
+		_, err := tx.ExecContext(ctx, "DROP INDEX calls_fn_id_id ON calls;")
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, "DROP INDEX calls_fn_id_id;")
+	return err
+}
+
+func init() {
+	Migrations = append(Migrations, &migratex.MigFields{
+		VersionFunc: vfunc(46),
+		UpFunc:      up46,
+		DownFunc:    down46,
+	})
+}
@@ -5,19 +5,25 @@ import (
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/fnproject/fn/api/callstore"
 	"github.com/fnproject/fn/api/common"
 	"github.com/fnproject/fn/api/datastore"
 	"github.com/fnproject/fn/api/datastore/sql/dbhelper"
 	"github.com/fnproject/fn/api/datastore/sql/migratex"
 	"github.com/fnproject/fn/api/datastore/sql/migrations"
 	"github.com/fnproject/fn/api/id"
+	"github.com/fnproject/fn/api/logs"
 	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/api/mqs"
 	"github.com/jmoiron/sqlx"
 	"github.com/sirupsen/logrus"
 )
@@ -40,7 +46,8 @@ var tables = [...]string{
 	syslog_url text,
 	created_at varchar(256),
 	updated_at varchar(256),
-	shape text
+	shape text,
+	secret_config text
 );`,
 
 	`CREATE TABLE IF NOT EXISTS triggers (
@@ -53,6 +60,11 @@ var tables = [...]string{
 	type varchar(256) NOT NULL,
 	source varchar(256) NOT NULL,
     annotations text NOT NULL,
+	methods varchar(1024) NOT NULL DEFAULT '',
+	method_fns varchar(4096) NOT NULL DEFAULT '',
+	cors text,
+	signature text,
+	alias varchar(256) NOT NULL DEFAULT '',
     CONSTRAINT name_app_id_fn_id_unique UNIQUE (app_id, fn_id, name)
 );`,
 
@@ -69,30 +81,134 @@ var tables = [...]string{
 	created_at varchar(256) NOT NULL,
 	updated_at varchar(256) NOT NULL,
 	shape text,
+	cpus int NOT NULL DEFAULT 0,
+	tmpfs_size int NOT NULL DEFAULT 0,
+	dead_letter varchar(1024) NOT NULL DEFAULT '',
+	on_success varchar(1024) NOT NULL DEFAULT '',
+	on_failure varchar(1024) NOT NULL DEFAULT '',
+	traffic text,
+	secret_config text,
     CONSTRAINT name_app_id_unique UNIQUE (app_id, name)
 );`,
+
+	`CREATE TABLE IF NOT EXISTS calls (
+	id varchar(256) NOT NULL PRIMARY KEY,
+	fn_id varchar(256) NOT NULL,
+	app_id varchar(256),
+	app_name varchar(256),
+	trigger_id varchar(256),
+	status varchar(256) NOT NULL,
+	created_at varchar(256) NOT NULL,
+	started_at varchar(256),
+	completed_at varchar(256),
+	execution_duration bigint,
+	stats text,
+	error text
+);`,
+
+	`CREATE TABLE IF NOT EXISTS logs (
+	id varchar(256) NOT NULL PRIMARY KEY,
+	app_id varchar(256),
+	fn_id varchar(256),
+	log text NOT NULL
+);`,
+
+	`CREATE TABLE IF NOT EXISTS leases (
+	name varchar(256) NOT NULL PRIMARY KEY,
+	holder varchar(256) NOT NULL,
+	expires_at bigint NOT NULL
+);`,
+
+	`CREATE TABLE IF NOT EXISTS queued_calls (
+	id varchar(256) NOT NULL PRIMARY KEY,
+	app_id varchar(256),
+	fn_id varchar(256) NOT NULL,
+	call text NOT NULL,
+	payload text NOT NULL,
+	due_at bigint NOT NULL,
+	reserved_until bigint NOT NULL DEFAULT 0
+);`,
+
+	`CREATE TABLE IF NOT EXISTS domains (
+	id varchar(256) NOT NULL PRIMARY KEY,
+	hostname varchar(256) NOT NULL UNIQUE,
+	app_id varchar(256) NOT NULL,
+	created_at varchar(256) NOT NULL
+);`,
+
+	`CREATE TABLE IF NOT EXISTS fn_revisions (
+	id varchar(256) NOT NULL PRIMARY KEY,
+	fn_id varchar(256) NOT NULL,
+	app_id varchar(256) NOT NULL,
+	image varchar(256) NOT NULL,
+	memory int NOT NULL,
+	timeout int NOT NULL,
+	idle_timeout int NOT NULL,
+	cpus int NOT NULL DEFAULT 0,
+	tmpfs_size int NOT NULL DEFAULT 0,
+	config text NOT NULL,
+	secret_config text,
+	annotations text NOT NULL,
+	created_at varchar(256) NOT NULL
+);`,
+
+	`CREATE TABLE IF NOT EXISTS aliases (
+	id varchar(256) NOT NULL PRIMARY KEY,
+	name varchar(256) NOT NULL,
+	app_id varchar(256) NOT NULL,
+	fn_id varchar(256) NOT NULL,
+	created_at varchar(256) NOT NULL,
+	updated_at varchar(256) NOT NULL,
+	CONSTRAINT alias_app_id_name_unique UNIQUE (app_id, name)
+);`,
+}
+
+// indexes are created with the tables of a new db, migrations add them to
+// existing ones
+var indexes = [...]string{
+	// calls of a fn are listed newest first
+	`CREATE INDEX calls_fn_id_id ON calls (fn_id, id);`,
 }
 
 const (
-	appIDSelector     = `SELECT id, name, config, annotations, syslog_url, created_at, updated_at, shape FROM apps WHERE id=?`
+	appIDSelector     = `SELECT id, name, config, secret_config, annotations, syslog_url, created_at, updated_at, shape FROM apps WHERE id=?`
 	ensureAppSelector = `SELECT id FROM apps WHERE name=?`
 
-	fnSelector   = `SELECT id,name,app_id,image,memory,timeout,idle_timeout,config,annotations,created_at,updated_at,shape FROM fns`
+	fnSelector   = `SELECT id,name,app_id,image,memory,timeout,idle_timeout,cpus,tmpfs_size,dead_letter,on_success,on_failure,traffic,config,secret_config,annotations,created_at,updated_at,shape FROM fns`
 	fnIDSelector = fnSelector + ` WHERE id=?`
 
-	triggerSelector   = `SELECT id,name,app_id,fn_id,type,source,annotations,created_at,updated_at FROM triggers`
+	fnRevisionSelector = `SELECT id,fn_id,app_id,image,memory,timeout,idle_timeout,cpus,tmpfs_size,config,secret_config,annotations,created_at FROM fn_revisions`
+
+	triggerSelector   = `SELECT id,name,app_id,fn_id,type,source,annotations,methods,method_fns,cors,signature,alias,created_at,updated_at FROM triggers`
 	triggerIDSelector = triggerSelector + ` WHERE id=?`
 
 	triggerIDSourceSelector = triggerSelector + ` WHERE app_id=? AND type=? AND source=?`
+	triggerTemplateSelector = triggerSelector + ` WHERE app_id=? AND type=? AND (source LIKE '%{%' OR source LIKE '%/*%')`
+
+	domainSelector         = `SELECT id,hostname,app_id,created_at FROM domains`
+	domainIDSelector       = domainSelector + ` WHERE id=?`
+	domainHostnameSelector = domainSelector + ` WHERE hostname=?`
+
+	aliasSelector     = `SELECT id,name,app_id,fn_id,created_at,updated_at FROM aliases`
+	aliasIDSelector   = aliasSelector + ` WHERE id=?`
+	aliasNameSelector = aliasSelector + ` WHERE app_id=? AND name=?`
+
+	callSelector   = `SELECT id,fn_id,app_id,app_name,trigger_id,status,created_at,started_at,completed_at,execution_duration,stats,error FROM calls`
+	callIDSelector = callSelector + ` WHERE fn_id=? AND id=?`
 
 	EnvDBPingMaxRetries = "FN_DS_DB_PING_MAX_RETRIES"
 )
 
 var ( // compiler will yell nice things about our upbringing as a child
 	_ models.Datastore = new(SQLStore)
+	_ models.CallStore = new(SQLStore)
+	_ models.LogStore  = new(SQLStore)
+	_ models.Leaser    = new(SQLStore)
+	_ models.MQ        = new(SQLStore)
 )
 
-// SQLStore implements models.Datastore
+// SQLStore implements models.Datastore, models.CallStore, models.LogStore,
+// models.Leaser and models.MQ
 type SQLStore struct {
 	helper dbhelper.Helper
 	db     *sqlx.DB
@@ -120,6 +236,48 @@ func (sqlDsProvider) String() string {
 	return "sql"
 }
 
+type sqlCsProvider int
+
+func (sqlCsProvider) Supports(u string) bool {
+	return sqlDsProvider(0).Supports(u)
+}
+
+func (sqlCsProvider) New(ctx context.Context, u string) (models.CallStore, error) {
+	return newDS(ctx, u)
+}
+
+func (sqlCsProvider) String() string {
+	return "sql"
+}
+
+type sqlLsProvider int
+
+func (sqlLsProvider) Supports(u string) bool {
+	return sqlDsProvider(0).Supports(u)
+}
+
+func (sqlLsProvider) New(ctx context.Context, u string) (models.LogStore, error) {
+	return newDS(ctx, u)
+}
+
+func (sqlLsProvider) String() string {
+	return "sql"
+}
+
+type sqlMqProvider int
+
+func (sqlMqProvider) Supports(u string) bool {
+	return sqlDsProvider(0).Supports(u)
+}
+
+func (sqlMqProvider) New(ctx context.Context, u string) (models.MQ, error) {
+	return newDS(ctx, u)
+}
+
+func (sqlMqProvider) String() string {
+	return "sql"
+}
+
 // for test methods, return concrete type, but don't expose
 func newDS(ctx context.Context, url string) (*SQLStore, error) {
 	driver := strings.SplitN(url, ":", 2)[0]
@@ -178,7 +336,7 @@ func newDS(ctx context.Context, url string) (*SQLStore, error) {
 	// run all migrations necessary to get up to the latest, inserting that version,
 	// [and the tables exist so CREATE IF NOT EXIST guards us when we run the create queries].
 	err = sdb.Tx(func(tx *sqlx.Tx) error {
-		err = sdb.runMigrations(ctx, tx, migrations.Migrations)
+		created, err := sdb.runMigrations(ctx, tx, migrations.Migrations)
 		if err != nil {
 			log.WithError(err).Error("error running migrations")
 			return err
@@ -191,6 +349,16 @@ func newDS(ctx context.Context, url string) (*SQLStore, error) {
 				return err
 			}
 		}
+
+		if created {
+			for _, v := range indexes {
+				_, err = tx.ExecContext(ctx, v)
+				if err != nil {
+					log.WithError(err).Error("error creating indexes")
+					return err
+				}
+			}
+		}
 		return nil
 	})
 
@@ -240,18 +408,19 @@ func pingWithRetry(ctx context.Context, db *sqlx.DB) (err error) {
 // check if the db already existed, if the db is brand new then we can skip
 // over all the migrations BUT we must be sure to set the right migration
 // number so that only current migrations are skipped, not any future ones.
-func (ds *SQLStore) runMigrations(ctx context.Context, tx *sqlx.Tx, migrations []migratex.Migration) error {
+// Whether the db is brand new is returned.
+func (ds *SQLStore) runMigrations(ctx context.Context, tx *sqlx.Tx, migrations []migratex.Migration) (bool, error) {
 	dbExists, err := ds.helper.CheckTableExists(tx, "apps")
 	if err != nil {
-		return err
+		return false, err
 	}
 	if !dbExists {
 		// set to highest and bail
-		return migratex.SetVersion(ctx, tx, latestVersion(migrations), false)
+		return true, migratex.SetVersion(ctx, tx, latestVersion(migrations), false)
 	}
 
 	// run any migrations needed to get to latest, if any
-	return migratex.Up(ctx, tx, migrations)
+	return false, migratex.Up(ctx, tx, migrations)
 }
 
 // latest version will find the latest version from a list of migration
@@ -285,6 +454,48 @@ func (ds *SQLStore) clear() error {
 
 		query = tx.Rebind(`DELETE FROM fns`)
 		_, err = tx.Exec(query)
+		if err != nil {
+			return err
+		}
+
+		query = tx.Rebind(`DELETE FROM calls`)
+		_, err = tx.Exec(query)
+		if err != nil {
+			return err
+		}
+
+		query = tx.Rebind(`DELETE FROM logs`)
+		_, err = tx.Exec(query)
+		if err != nil {
+			return err
+		}
+
+		query = tx.Rebind(`DELETE FROM leases`)
+		_, err = tx.Exec(query)
+		if err != nil {
+			return err
+		}
+
+		query = tx.Rebind(`DELETE FROM queued_calls`)
+		_, err = tx.Exec(query)
+		if err != nil {
+			return err
+		}
+
+		query = tx.Rebind(`DELETE FROM domains`)
+		_, err = tx.Exec(query)
+		if err != nil {
+			return err
+		}
+
+		query = tx.Rebind(`DELETE FROM aliases`)
+		_, err = tx.Exec(query)
+		if err != nil {
+			return err
+		}
+
+		query = tx.Rebind(`DELETE FROM fn_revisions`)
+		_, err = tx.Exec(query)
 		return err
 	})
 }
@@ -320,6 +531,7 @@ func (ds *SQLStore) InsertApp(ctx context.Context, newApp *models.App) (*models.
 		id,
 		name,
 		config,
+		secret_config,
 		annotations,
 		syslog_url,
 		created_at,
@@ -330,6 +542,7 @@ func (ds *SQLStore) InsertApp(ctx context.Context, newApp *models.App) (*models.
 		:id,
 		:name,
 		:config,
+		:secret_config,
 		:annotations,
 		:syslog_url,
 		:created_at,
@@ -374,7 +587,7 @@ func (ds *SQLStore) UpdateApp(ctx context.Context, newapp *models.App) (*models.
 		if err != nil {
 			return err
 		}
-		query = tx.Rebind(`UPDATE apps SET config=:config, annotations=:annotations, syslog_url=:syslog_url, updated_at=:updated_at WHERE name=:name`)
+		query = tx.Rebind(`UPDATE apps SET config=:config, secret_config=:secret_config, annotations=:annotations, syslog_url=:syslog_url, updated_at=:updated_at WHERE name=:name`)
 
 		res, err := tx.NamedExecContext(ctx, query, app)
 		if err != nil {
@@ -414,6 +627,9 @@ func (ds *SQLStore) RemoveApp(ctx context.Context, appID string) error {
 		deletes := []string{
 			`DELETE FROM fns WHERE app_id=?`,
 			`DELETE FROM triggers WHERE app_id=?`,
+			`DELETE FROM domains WHERE app_id=?`,
+			`DELETE FROM fn_revisions WHERE app_id=?`,
+			`DELETE FROM aliases WHERE app_id=?`,
 		}
 		for _, stmt := range deletes {
 			_, err := tx.ExecContext(ctx, tx.Rebind(stmt), appID)
@@ -450,7 +666,7 @@ func (ds *SQLStore) GetApps(ctx context.Context, filter *models.AppFilter) (*mod
 		return nil, err
 	}
 	/* #nosec */
-	query = ds.db.Rebind(fmt.Sprintf("SELECT DISTINCT id, name, config, annotations, syslog_url, created_at, updated_at, shape FROM apps %s", query))
+	query = ds.db.Rebind(fmt.Sprintf("SELECT DISTINCT id, name, config, secret_config, annotations, syslog_url, created_at, updated_at, shape FROM apps %s", query))
 
 	rows, err := ds.db.QueryxContext(ctx, query, args...)
 	if err != nil {
@@ -515,6 +731,13 @@ func (ds *SQLStore) InsertFn(ctx context.Context, newFn *models.Fn) (*models.Fn,
 		//Setting the fn shape same as the application shape
 		fn.Shape = app.Shape
 
+		if err := checkTrafficFns(ctx, tx, nil, fn); err != nil {
+			return err
+		}
+		if err := checkDestinationFns(ctx, tx, nil, fn); err != nil {
+			return err
+		}
+
 		query = tx.Rebind(`INSERT INTO fns (
 				id,
 				name,
@@ -523,7 +746,14 @@ func (ds *SQLStore) InsertFn(ctx context.Context, newFn *models.Fn) (*models.Fn,
 				memory,
 				timeout,
 				idle_timeout,
+				cpus,
+				tmpfs_size,
+				dead_letter,
+				on_success,
+				on_failure,
+				traffic,
 				config,
+				secret_config,
 				annotations,
 				created_at,
 				updated_at,
@@ -537,7 +767,14 @@ func (ds *SQLStore) InsertFn(ctx context.Context, newFn *models.Fn) (*models.Fn,
 				:memory,
 				:timeout,
 				:idle_timeout,
+				:cpus,
+				:tmpfs_size,
+				:dead_letter,
+				:on_success,
+				:on_failure,
+				:traffic,
 				:config,
+				:secret_config,
 				:annotations,
 				:created_at,
 				:updated_at,
@@ -571,33 +808,194 @@ func (ds *SQLStore) UpdateFn(ctx context.Context, fn *models.Fn) (*models.Fn, er
 			return err
 		}
 
+		original := dst.Clone()
 		dst.Update(fn)
-		err = dst.Validate()
+		fn = &dst // set for query & to return
+		return ds.updateFn(ctx, tx, original, fn)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return fn, nil
+}
+
+// updateFn stores fn, which was original, recording how original ran as a
+// revision if fn runs differently
+func (ds *SQLStore) updateFn(ctx context.Context, tx *sqlx.Tx, original, fn *models.Fn) error {
+	err := fn.Validate()
+	if err != nil {
+		return err
+	}
+
+	if err := checkTrafficFns(ctx, tx, original, fn); err != nil {
+		return err
+	}
+	if err := checkDestinationFns(ctx, tx, original, fn); err != nil {
+		return err
+	}
+
+	if !original.RevisionEquals(fn) {
+		r := models.NewFnRevision(original)
+		r.ID = id.New().String()
+		query := tx.Rebind(`INSERT INTO fn_revisions (
+				id,
+				fn_id,
+				app_id,
+				image,
+				memory,
+				timeout,
+				idle_timeout,
+				cpus,
+				tmpfs_size,
+				config,
+				secret_config,
+				annotations,
+				created_at
+			)
+			VALUES (
+				:id,
+				:fn_id,
+				:app_id,
+				:image,
+				:memory,
+				:timeout,
+				:idle_timeout,
+				:cpus,
+				:tmpfs_size,
+				:config,
+				:secret_config,
+				:annotations,
+				:created_at
+			);`)
+		_, err = tx.NamedExecContext(ctx, query, r)
 		if err != nil {
 			return err
 		}
-		fn = &dst // set for query & to return
+		if err := trimFnRevisions(ctx, tx, fn.ID); err != nil {
+			return err
+		}
+	}
 
-		query = tx.Rebind(`UPDATE fns SET
+	query := tx.Rebind(`UPDATE fns SET
 				name = :name,
 				image = :image,
 				memory = :memory,
 				timeout = :timeout,
 				idle_timeout = :idle_timeout,
+				cpus = :cpus,
+				tmpfs_size = :tmpfs_size,
+				dead_letter = :dead_letter,
+				on_success = :on_success,
+				on_failure = :on_failure,
+				traffic = :traffic,
 				config = :config,
+				secret_config = :secret_config,
 				annotations = :annotations,
 				updated_at = :updated_at,
 				shape = :shape
 			    WHERE id=:id;`)
 
-		_, err = tx.NamedExecContext(ctx, query, fn)
+	_, err = tx.NamedExecContext(ctx, query, fn)
+	return err
+}
+
+// trimFnRevisions removes the revisions of fn older than the latest
+// models.MaxFnRevisions
+func trimFnRevisions(ctx context.Context, tx *sqlx.Tx, fnID string) error {
+	var oldest string
+	query := tx.Rebind(`SELECT id FROM fn_revisions WHERE fn_id=? ORDER BY id DESC LIMIT 1 OFFSET ?`)
+	err := tx.QueryRowxContext(ctx, query, fnID, models.MaxFnRevisions-1).Scan(&oldest)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
 		return err
+	}
+
+	query = tx.Rebind(`DELETE FROM fn_revisions WHERE fn_id=? AND id<?`)
+	_, err = tx.ExecContext(ctx, query, fnID, oldest)
+	return err
+}
+
+func (ds *SQLStore) GetFnRevisions(ctx context.Context, filter *models.FnRevisionFilter) (*models.FnRevisionList, error) {
+	res := &models.FnRevisionList{Items: []*models.FnRevision{}}
+
+	var b bytes.Buffer
+	args := []interface{}{filter.FnID}
+	fmt.Fprintf(&b, `%s WHERE fn_id = ?`, fnRevisionSelector)
+	if filter.Cursor != "" {
+		s, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, ` AND id < ?`)
+		args = append(args, string(s))
+	}
+	fmt.Fprintf(&b, ` ORDER BY id DESC`) // ids are time ordered, newest first
+	if filter.PerPage > 0 {
+		fmt.Fprintf(&b, ` LIMIT ?`)
+		args = append(args, filter.PerPage)
+	}
+
+	query := ds.db.Rebind(b.String())
+	rows, err := ds.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r models.FnRevision
+		if err := rows.StructScan(&r); err != nil {
+			return nil, err
+		}
+		res.Items = append(res.Items, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(res.Items) > 0 && len(res.Items) == filter.PerPage {
+		last := []byte(res.Items[len(res.Items)-1].ID)
+		res.NextCursor = base64.RawURLEncoding.EncodeToString(last)
+	}
+	return res, nil
+}
+
+func (ds *SQLStore) RollbackFn(ctx context.Context, fnID, revisionID string) (*models.Fn, error) {
+	var fn models.Fn
+	err := ds.Tx(func(tx *sqlx.Tx) error {
+		query := tx.Rebind(fnIDSelector)
+		err := tx.QueryRowxContext(ctx, query, fnID).StructScan(&fn)
+		if err == sql.ErrNoRows {
+			return models.ErrFnsNotFound
+		} else if err != nil {
+			return err
+		}
+
+		var r models.FnRevision
+		if revisionID == "" {
+			query = tx.Rebind(fnRevisionSelector + ` WHERE fn_id=? ORDER BY id DESC LIMIT 1`)
+			err = tx.QueryRowxContext(ctx, query, fnID).StructScan(&r)
+		} else {
+			query = tx.Rebind(fnRevisionSelector + ` WHERE fn_id=? AND id=?`)
+			err = tx.QueryRowxContext(ctx, query, fnID, revisionID).StructScan(&r)
+		}
+		if err == sql.ErrNoRows {
+			return models.ErrFnRevisionNotFound
+		} else if err != nil {
+			return err
+		}
+
+		original := fn.Clone()
+		fn.Rollback(&r)
+		return ds.updateFn(ctx, tx, original, &fn)
 	})
 
 	if err != nil {
 		return nil, err
 	}
-	return fn, nil
+	return &fn, nil
 }
 
 func (ds *SQLStore) GetFns(ctx context.Context, filter *models.FnFilter) (*models.FnList, error) {
@@ -670,6 +1068,31 @@ func (ds *SQLStore) RemoveFn(ctx context.Context, fnID string) error {
 		err := row.StructScan(&fn)
 		if err == sql.ErrNoRows {
 			return models.ErrFnsNotFound
+		} else if err != nil {
+			return err
+		}
+
+		// aliases would have nothing to call
+		query = tx.Rebind(`SELECT 1 FROM aliases WHERE fn_id=?`)
+		err = tx.QueryRowContext(ctx, query, fnID).Scan(new(int))
+		if err == nil {
+			return models.ErrFnsAliased
+		} else if err != sql.ErrNoRows {
+			return err
+		}
+
+		// triggers of other fns would send methods to nowhere, or let any
+		// method through once they were taken out, so users repoint them
+		handles, err := fnHandlesMethods(ctx, tx, &fn)
+		if err != nil {
+			return err
+		}
+		if handles {
+			return models.ErrFnsHandlesMethods
+		}
+
+		if err := ds.removeTrafficSplits(ctx, tx, &fn); err != nil {
+			return err
 		}
 
 		query = tx.Rebind(`DELETE FROM triggers WHERE fn_id=?`)
@@ -679,6 +1102,13 @@ func (ds *SQLStore) RemoveFn(ctx context.Context, fnID string) error {
 			return err
 		}
 
+		query = tx.Rebind(`DELETE FROM fn_revisions WHERE fn_id=?`)
+		_, err = tx.ExecContext(ctx, query, fnID)
+
+		if err != nil {
+			return err
+		}
+
 		query = tx.Rebind(`DELETE FROM fns WHERE id=?`)
 		_, err = tx.ExecContext(ctx, query, fnID)
 
@@ -803,18 +1233,28 @@ func (ds *SQLStore) InsertTrigger(ctx context.Context, newTrigger *models.Trigge
 			}
 		}
 
-		query = tx.Rebind(`SELECT app_id FROM fns WHERE id=?`)
-		r = tx.QueryRowContext(ctx, query, trigger.FnID)
-		var app_id string
-		if err := r.Scan(&app_id); err != nil {
-			if err == sql.ErrNoRows {
-				return models.ErrFnsNotFound
-			} else if err != nil {
+		if trigger.Alias != "" {
+			if err := checkTriggerAlias(ctx, tx, trigger); err != nil {
 				return err
 			}
+		} else {
+			query = tx.Rebind(`SELECT app_id FROM fns WHERE id=?`)
+			r = tx.QueryRowContext(ctx, query, trigger.FnID)
+			var app_id string
+			if err := r.Scan(&app_id); err != nil {
+				if err == sql.ErrNoRows {
+					return models.ErrFnsNotFound
+				} else if err != nil {
+					return err
+				}
+			}
+			if app_id != trigger.AppID {
+				return models.ErrTriggerFnIDNotSameApp
+			}
 		}
-		if app_id != trigger.AppID {
-			return models.ErrTriggerFnIDNotSameApp
+
+		if err := checkMethodFns(ctx, tx, trigger); err != nil {
+			return err
 		}
 
 		query = tx.Rebind(`SELECT 1 FROM triggers WHERE app_id=? AND type=? and source=?`)
@@ -835,7 +1275,12 @@ func (ds *SQLStore) InsertTrigger(ctx context.Context, newTrigger *models.Trigge
 			updated_at,
 			type,
 		  	source,
-		  	annotations
+		  	annotations,
+			methods,
+			method_fns,
+			cors,
+			signature,
+			alias
 		)
 		VALUES (
 			:id,
@@ -846,7 +1291,12 @@ func (ds *SQLStore) InsertTrigger(ctx context.Context, newTrigger *models.Trigge
 			:updated_at,
 			:type,
 			:source,
-			:annotations
+			:annotations,
+			:methods,
+			:method_fns,
+			:cors,
+			:signature,
+			:alias
 		);`)
 
 		_, err = tx.NamedExecContext(ctx, query, trigger)
@@ -884,12 +1334,26 @@ func (ds *SQLStore) UpdateTrigger(ctx context.Context, trigger *models.Trigger)
 		}
 		trigger = &dst // set for query & to return
 
+		if trigger.Alias != "" {
+			if err := checkTriggerAlias(ctx, tx, trigger); err != nil {
+				return err
+			}
+		}
+		if err := checkMethodFns(ctx, tx, trigger); err != nil {
+			return err
+		}
+
 		query = tx.Rebind(`UPDATE triggers SET
 			name = :name,
 			fn_id = :fn_id,
 			updated_at = :updated_at,
 			source = :source,
-			annotations = :annotations
+			annotations = :annotations,
+			methods = :methods,
+			method_fns = :method_fns,
+			cors = :cors,
+			signature = :signature,
+			alias = :alias
 			WHERE id = :id;`)
 		_, err = tx.NamedExecContext(ctx, query, trigger)
 		return err
@@ -901,10 +1365,156 @@ func (ds *SQLStore) UpdateTrigger(ctx context.Context, trigger *models.Trigger)
 	return trigger, nil
 }
 
-func (ds *SQLStore) GetTrigger(ctx context.Context, appId, fnId, triggerName string) (*models.Trigger, error) {
-	var trigger models.Trigger
-	/* #nosec */
-	query := ds.db.Rebind(fmt.Sprintf("%s WHERE name=? AND app_id=? AND fn_id=?", fnSelector))
+// checkTriggerAlias checks that the alias trigger targets is an alias of its app
+func checkTriggerAlias(ctx context.Context, tx *sqlx.Tx, trigger *models.Trigger) error {
+	query := tx.Rebind(`SELECT 1 FROM aliases WHERE app_id=? AND name=?`)
+	err := tx.QueryRowContext(ctx, query, trigger.AppID, trigger.Alias).Scan(new(int))
+	if err == sql.ErrNoRows {
+		return models.ErrAliasNotFound
+	}
+	return err
+}
+
+// checkMethodFns checks that the fns methods of trigger are mapped to are in its app
+func checkMethodFns(ctx context.Context, tx *sqlx.Tx, trigger *models.Trigger) error {
+	query := tx.Rebind(`SELECT app_id FROM fns WHERE id=?`)
+	for _, fnID := range trigger.MethodFns {
+		var appID string
+		err := tx.QueryRowContext(ctx, query, fnID).Scan(&appID)
+		if err == sql.ErrNoRows {
+			return models.ErrFnsNotFound
+		} else if err != nil {
+			return err
+		}
+		if appID != trigger.AppID {
+			return models.ErrTriggerFnIDNotSameApp
+		}
+	}
+	return nil
+}
+
+// removeTrafficSplits takes the splits to fn out of the traffic of the other
+// fns of its app, which then get the calls fn got
+func (ds *SQLStore) removeTrafficSplits(ctx context.Context, tx *sqlx.Tx, fn *models.Fn) error {
+	/* #nosec */
+	query := tx.Rebind(fmt.Sprintf("%s WHERE app_id=? AND id<>? AND traffic IS NOT NULL", fnSelector))
+	rows, err := tx.QueryxContext(ctx, query, fn.AppID, fn.ID)
+	if err != nil {
+		return err
+	}
+	var split []*models.Fn
+	for rows.Next() {
+		var other models.Fn
+		if err := rows.StructScan(&other); err != nil {
+			rows.Close()
+			return err
+		}
+		if !other.Traffic.Equals(other.Traffic.Without(fn.ID)) {
+			split = append(split, &other)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, other := range split {
+		original := other.Clone()
+		other.Traffic = other.Traffic.Without(fn.ID)
+		other.UpdatedAt = common.DateTime(time.Now())
+		if err := ds.updateFn(ctx, tx, original, other); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fnHandlesMethods tells if fn handles methods of triggers of other fns
+func fnHandlesMethods(ctx context.Context, tx *sqlx.Tx, fn *models.Fn) (bool, error) {
+	query := tx.Rebind(`SELECT method_fns FROM triggers WHERE app_id=? AND fn_id<>? AND method_fns<>''`)
+	rows, err := tx.QueryContext(ctx, query, fn.AppID, fn.ID)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var methodFns models.MethodFns
+		if err := rows.Scan(&methodFns); err != nil {
+			return false, err
+		}
+		for _, fnID := range methodFns {
+			if fnID == fn.ID {
+				return true, nil
+			}
+		}
+	}
+	return false, rows.Err()
+}
+
+// checkTrafficFns checks that the fns the traffic of fn is newly split to,
+// those original, if any, was not split to already, are in its app
+func checkTrafficFns(ctx context.Context, tx *sqlx.Tx, original, fn *models.Fn) error {
+	if fn.Traffic == nil {
+		return nil
+	}
+	splitTo := make(map[string]bool)
+	if original != nil && original.Traffic != nil {
+		for _, fnID := range original.Traffic.FnIDs() {
+			splitTo[fnID] = true
+		}
+	}
+	query := tx.Rebind(`SELECT app_id FROM fns WHERE id=?`)
+	for _, fnID := range fn.Traffic.FnIDs() {
+		if splitTo[fnID] {
+			continue
+		}
+		var appID string
+		err := tx.QueryRowContext(ctx, query, fnID).Scan(&appID)
+		if err == sql.ErrNoRows {
+			return models.ErrFnsNotFound
+		} else if err != nil {
+			return err
+		}
+		if appID != fn.AppID {
+			return models.ErrFnsTrafficFnNotSameApp
+		}
+	}
+	return nil
+}
+
+// checkDestinationFns checks that the fns the results of detached calls of
+// fn are sent to, that were not already, are fns of its app
+func checkDestinationFns(ctx context.Context, tx *sqlx.Tx, original, fn *models.Fn) error {
+	sentTo := make(map[string]bool)
+	if original != nil {
+		for _, fnID := range original.DestinationFnIDs() {
+			sentTo[fnID] = true
+		}
+	}
+	query := tx.Rebind(`SELECT app_id FROM fns WHERE id=?`)
+	for _, fnID := range fn.DestinationFnIDs() {
+		if sentTo[fnID] {
+			continue
+		}
+		var appID string
+		err := tx.QueryRowContext(ctx, query, fnID).Scan(&appID)
+		if err == sql.ErrNoRows {
+			return models.ErrFnsNotFound
+		} else if err != nil {
+			return err
+		}
+		if appID != fn.AppID {
+			return models.ErrFnsDestinationNotSameApp
+		}
+	}
+	return nil
+}
+
+func (ds *SQLStore) GetTrigger(ctx context.Context, appId, fnId, triggerName string) (*models.Trigger, error) {
+	var trigger models.Trigger
+	/* #nosec */
+	query := ds.db.Rebind(fmt.Sprintf("%s WHERE name=? AND app_id=? AND fn_id=?", fnSelector))
 	row := ds.db.QueryRowxContext(ctx, query, triggerName, appId, fnId)
 
 	err := row.StructScan(&trigger)
@@ -1042,7 +1652,9 @@ func (ds *SQLStore) GetTriggerBySource(ctx context.Context, appId string, trigge
 	row := ds.db.QueryRowxContext(ctx, query, appId, triggerType, source)
 
 	err := row.StructScan(&trigger)
-	if err == sql.ErrNoRows {
+	if err == sql.ErrNoRows && triggerType == models.TriggerTypeHTTP {
+		return ds.getTriggerBySourceTemplate(ctx, appId, triggerType, source)
+	} else if err == sql.ErrNoRows {
 		return nil, models.ErrTriggerNotFound
 	} else if err != nil {
 		return nil, err
@@ -1050,6 +1662,661 @@ func (ds *SQLStore) GetTriggerBySource(ctx context.Context, appId string, trigge
 	return &trigger, nil
 }
 
+// getTriggerBySourceTemplate finds the http trigger with a templated source that best matches source
+func (ds *SQLStore) getTriggerBySourceTemplate(ctx context.Context, appID string, triggerType, source string) (*models.Trigger, error) {
+	query := ds.db.Rebind(triggerTemplateSelector)
+	rows, err := ds.db.QueryxContext(ctx, query, appID, triggerType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []*models.Trigger
+	for rows.Next() {
+		var trigger models.Trigger
+		if err := rows.StructScan(&trigger); err != nil {
+			return nil, err
+		}
+		templates = append(templates, &trigger)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	trigger := models.BestTriggerSourceMatch(templates, source)
+	if trigger == nil {
+		return nil, models.ErrTriggerNotFound
+	}
+	return trigger, nil
+}
+
+func (ds *SQLStore) InsertDomain(ctx context.Context, newDomain *models.Domain) (*models.Domain, error) {
+	domain := newDomain.Clone()
+	domain.CreatedAt = common.DateTime(time.Now())
+	domain.ID = id.New().String()
+
+	if err := domain.Validate(); err != nil {
+		return nil, err
+	}
+
+	err := ds.Tx(func(tx *sqlx.Tx) error {
+		query := tx.Rebind(`SELECT 1 FROM apps WHERE id=?`)
+		r := tx.QueryRowContext(ctx, query, domain.AppID)
+		if err := r.Scan(new(int)); err == sql.ErrNoRows {
+			return models.ErrAppsNotFound
+		} else if err != nil {
+			return err
+		}
+
+		query = tx.Rebind(`INSERT INTO domains (
+			id,
+			hostname,
+			app_id,
+			created_at
+		)
+		VALUES (
+			:id,
+			:hostname,
+			:app_id,
+			:created_at
+		);`)
+		_, err := tx.NamedExecContext(ctx, query, domain)
+		return err
+	})
+
+	if err != nil {
+		if ds.helper.IsDuplicateKeyError(err) {
+			return nil, models.ErrDomainExists
+		}
+		return nil, err
+	}
+	return domain, nil
+}
+
+func (ds *SQLStore) GetDomainByID(ctx context.Context, domainID string) (*models.Domain, error) {
+	return ds.getDomain(ctx, domainIDSelector, domainID)
+}
+
+func (ds *SQLStore) GetDomainByHostname(ctx context.Context, hostname string) (*models.Domain, error) {
+	return ds.getDomain(ctx, domainHostnameSelector, hostname)
+}
+
+func (ds *SQLStore) getDomain(ctx context.Context, selector, arg string) (*models.Domain, error) {
+	var domain models.Domain
+	query := ds.db.Rebind(selector)
+	row := ds.db.QueryRowxContext(ctx, query, arg)
+
+	err := row.StructScan(&domain)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrDomainNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+func (ds *SQLStore) GetDomains(ctx context.Context, filter *models.DomainFilter) (*models.DomainList, error) {
+	res := &models.DomainList{Items: []*models.Domain{}}
+
+	var b bytes.Buffer
+	args := []interface{}{filter.AppID}
+	fmt.Fprintf(&b, `%s WHERE app_id = ?`, domainSelector)
+	if filter.Cursor != "" {
+		s, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, ` AND hostname > ?`)
+		args = append(args, string(s))
+	}
+	fmt.Fprintf(&b, ` ORDER BY hostname ASC`)
+	if filter.PerPage > 0 {
+		fmt.Fprintf(&b, ` LIMIT ?`)
+		args = append(args, filter.PerPage)
+	}
+
+	query := ds.db.Rebind(b.String())
+	rows, err := ds.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var domain models.Domain
+		if err := rows.StructScan(&domain); err != nil {
+			return nil, err
+		}
+		res.Items = append(res.Items, &domain)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(res.Items) > 0 && len(res.Items) == filter.PerPage {
+		last := []byte(res.Items[len(res.Items)-1].Hostname)
+		res.NextCursor = base64.RawURLEncoding.EncodeToString(last)
+	}
+	return res, nil
+}
+
+func (ds *SQLStore) RemoveDomain(ctx context.Context, domainID string) error {
+	query := ds.db.Rebind(`DELETE FROM domains WHERE id = ?;`)
+	res, err := ds.db.ExecContext(ctx, query, domainID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return models.ErrDomainNotFound
+	}
+	return nil
+}
+
+func (ds *SQLStore) InsertAlias(ctx context.Context, newAlias *models.Alias) (*models.Alias, error) {
+	alias := newAlias.Clone()
+	alias.CreatedAt = common.DateTime(time.Now())
+	alias.UpdatedAt = alias.CreatedAt
+	alias.ID = id.New().String()
+
+	if err := alias.Validate(); err != nil {
+		return nil, err
+	}
+
+	err := ds.Tx(func(tx *sqlx.Tx) error {
+		query := tx.Rebind(`SELECT 1 FROM apps WHERE id=?`)
+		r := tx.QueryRowContext(ctx, query, alias.AppID)
+		if err := r.Scan(new(int)); err == sql.ErrNoRows {
+			return models.ErrAppsNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if err := checkAliasFn(ctx, tx, alias); err != nil {
+			return err
+		}
+
+		query = tx.Rebind(`INSERT INTO aliases (
+			id,
+			name,
+			app_id,
+			fn_id,
+			created_at,
+			updated_at
+		)
+		VALUES (
+			:id,
+			:name,
+			:app_id,
+			:fn_id,
+			:created_at,
+			:updated_at
+		);`)
+		_, err := tx.NamedExecContext(ctx, query, alias)
+		return err
+	})
+
+	if err != nil {
+		if ds.helper.IsDuplicateKeyError(err) {
+			return nil, models.ErrAliasExists
+		}
+		return nil, err
+	}
+	return alias, nil
+}
+
+func (ds *SQLStore) UpdateAlias(ctx context.Context, alias *models.Alias) (*models.Alias, error) {
+	err := ds.Tx(func(tx *sqlx.Tx) error {
+		var dst models.Alias
+		query := tx.Rebind(aliasIDSelector)
+		err := tx.QueryRowxContext(ctx, query, alias.ID).StructScan(&dst)
+		if err == sql.ErrNoRows {
+			return models.ErrAliasNotFound
+		} else if err != nil {
+			return err
+		}
+
+		dst.Update(alias)
+		if err := dst.Validate(); err != nil {
+			return err
+		}
+		alias = &dst // set for query & to return
+
+		if err := checkAliasFn(ctx, tx, alias); err != nil {
+			return err
+		}
+
+		query = tx.Rebind(`UPDATE aliases SET
+			fn_id = :fn_id,
+			updated_at = :updated_at
+			WHERE id = :id;`)
+		_, err = tx.NamedExecContext(ctx, query, alias)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return alias, nil
+}
+
+// checkAliasFn checks that the fn alias points to is in its app
+func checkAliasFn(ctx context.Context, tx *sqlx.Tx, alias *models.Alias) error {
+	query := tx.Rebind(`SELECT app_id FROM fns WHERE id=?`)
+	var appID string
+	err := tx.QueryRowContext(ctx, query, alias.FnID).Scan(&appID)
+	if err == sql.ErrNoRows {
+		return models.ErrFnsNotFound
+	} else if err != nil {
+		return err
+	}
+	if appID != alias.AppID {
+		return models.ErrAliasFnIDNotSameApp
+	}
+	return nil
+}
+
+func (ds *SQLStore) GetAliasByID(ctx context.Context, aliasID string) (*models.Alias, error) {
+	return ds.getAlias(ctx, aliasIDSelector, aliasID)
+}
+
+func (ds *SQLStore) GetAliasByName(ctx context.Context, appID, name string) (*models.Alias, error) {
+	return ds.getAlias(ctx, aliasNameSelector, appID, name)
+}
+
+func (ds *SQLStore) getAlias(ctx context.Context, selector string, args ...interface{}) (*models.Alias, error) {
+	var alias models.Alias
+	query := ds.db.Rebind(selector)
+	row := ds.db.QueryRowxContext(ctx, query, args...)
+
+	err := row.StructScan(&alias)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrAliasNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &alias, nil
+}
+
+func (ds *SQLStore) GetAliases(ctx context.Context, filter *models.AliasFilter) (*models.AliasList, error) {
+	res := &models.AliasList{Items: []*models.Alias{}}
+
+	var b bytes.Buffer
+	args := []interface{}{filter.AppID}
+	fmt.Fprintf(&b, `%s WHERE app_id = ?`, aliasSelector)
+	if filter.FnID != "" {
+		fmt.Fprintf(&b, ` AND fn_id = ?`)
+		args = append(args, filter.FnID)
+	}
+	if filter.Cursor != "" {
+		s, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, ` AND name > ?`)
+		args = append(args, string(s))
+	}
+	fmt.Fprintf(&b, ` ORDER BY name ASC`)
+	if filter.PerPage > 0 {
+		fmt.Fprintf(&b, ` LIMIT ?`)
+		args = append(args, filter.PerPage)
+	}
+
+	query := ds.db.Rebind(b.String())
+	rows, err := ds.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var alias models.Alias
+		if err := rows.StructScan(&alias); err != nil {
+			return nil, err
+		}
+		res.Items = append(res.Items, &alias)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(res.Items) > 0 && len(res.Items) == filter.PerPage {
+		last := []byte(res.Items[len(res.Items)-1].Name)
+		res.NextCursor = base64.RawURLEncoding.EncodeToString(last)
+	}
+	return res, nil
+}
+
+func (ds *SQLStore) RemoveAlias(ctx context.Context, aliasID string) error {
+	return ds.Tx(func(tx *sqlx.Tx) error {
+		var alias models.Alias
+		query := tx.Rebind(aliasIDSelector)
+		err := tx.QueryRowxContext(ctx, query, aliasID).StructScan(&alias)
+		if err == sql.ErrNoRows {
+			return models.ErrAliasNotFound
+		} else if err != nil {
+			return err
+		}
+
+		// triggers would have nothing to call
+		query = tx.Rebind(`SELECT 1 FROM triggers WHERE app_id=? AND alias=?`)
+		err = tx.QueryRowContext(ctx, query, alias.AppID, alias.Name).Scan(new(int))
+		if err == nil {
+			return models.ErrAliasInUse
+		} else if err != sql.ErrNoRows {
+			return err
+		}
+
+		query = tx.Rebind(`DELETE FROM aliases WHERE id = ?;`)
+		_, err = tx.ExecContext(ctx, query, aliasID)
+		return err
+	})
+}
+
+func (ds *SQLStore) InsertCall(ctx context.Context, call *models.Call) error {
+	c := *call
+	// stored as strings, keep them in UTC so that range queries compare correctly
+	c.CreatedAt = common.DateTime(time.Time(c.CreatedAt).UTC())
+	c.StartedAt = common.DateTime(time.Time(c.StartedAt).UTC())
+	c.CompletedAt = common.DateTime(time.Time(c.CompletedAt).UTC())
+
+	return ds.Tx(func(tx *sqlx.Tx) error {
+		// calls are inserted more than once as they change state, last write wins
+		query := tx.Rebind(`DELETE FROM calls WHERE id=?`)
+		_, err := tx.ExecContext(ctx, query, c.ID)
+		if err != nil {
+			return err
+		}
+
+		query = tx.Rebind(`INSERT INTO calls (
+		id,
+		fn_id,
+		app_id,
+		app_name,
+		trigger_id,
+		status,
+		created_at,
+		started_at,
+		completed_at,
+		execution_duration,
+		stats,
+		error
+	)
+	VALUES (
+		:id,
+		:fn_id,
+		:app_id,
+		:app_name,
+		:trigger_id,
+		:status,
+		:created_at,
+		:started_at,
+		:completed_at,
+		:execution_duration,
+		:stats,
+		:error
+	);`)
+
+		_, err = tx.NamedExecContext(ctx, query, &c)
+		return err
+	})
+}
+
+func (ds *SQLStore) GetCall(ctx context.Context, fnID, callID string) (*models.Call, error) {
+	query := ds.db.Rebind(callIDSelector)
+	row := ds.db.QueryRowxContext(ctx, query, fnID, callID)
+
+	var call models.Call
+	err := row.StructScan(&call)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrCallNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &call, nil
+}
+
+func (ds *SQLStore) GetCalls(ctx context.Context, filter *models.CallFilter) (*models.CallList, error) {
+	res := &models.CallList{Items: []*models.Call{}}
+
+	filterQuery, args, err := buildFilterCallQuery(filter)
+	if err != nil {
+		return res, err
+	}
+
+	/* #nosec */
+	query := fmt.Sprintf("%s %s", callSelector, filterQuery)
+	query = ds.db.Rebind(query)
+	rows, err := ds.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return res, nil // no error for empty list
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var call models.Call
+		if err := rows.StructScan(&call); err != nil {
+			return nil, err
+		}
+		res.Items = append(res.Items, &call)
+	}
+
+	if len(res.Items) > 0 && len(res.Items) == filter.PerPage {
+		last := []byte(res.Items[len(res.Items)-1].ID)
+		res.NextCursor = base64.RawURLEncoding.EncodeToString(last)
+	}
+
+	if err := rows.Err(); err != nil {
+		if err == sql.ErrNoRows {
+			return res, nil // no error for empty list
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+func buildFilterCallQuery(filter *models.CallFilter) (string, []interface{}, error) {
+	var b bytes.Buffer
+	var args []interface{}
+
+	args = where(&b, args, "fn_id=?", filter.FnID)
+
+	if filter.Cursor != "" {
+		s, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+		if err != nil {
+			return "", args, err
+		}
+		args = where(&b, args, "id<?", string(s))
+	}
+	if !time.Time(filter.FromTime).IsZero() {
+		args = where(&b, args, "created_at>?", common.DateTime(time.Time(filter.FromTime).UTC()).String())
+	}
+	if !time.Time(filter.ToTime).IsZero() {
+		args = where(&b, args, "created_at<?", common.DateTime(time.Time(filter.ToTime).UTC()).String())
+	}
+	args = where(&b, args, "status=?", filter.Status)
+
+	fmt.Fprintf(&b, ` ORDER BY id DESC`) // ids are time ordered, newest first
+	if filter.PerPage > 0 {
+		fmt.Fprintf(&b, ` LIMIT ?`)
+		args = append(args, filter.PerPage)
+	}
+	return b.String(), args, nil
+}
+
+func (ds *SQLStore) InsertLog(ctx context.Context, call *models.Call, callLog io.Reader) error {
+	// coerce this into a string for sql
+	var log string
+	if stringer, ok := callLog.(fmt.Stringer); ok {
+		log = stringer.String()
+	} else {
+		b, err := ioutil.ReadAll(callLog)
+		if err != nil {
+			return err
+		}
+		log = string(b)
+	}
+
+	return ds.Tx(func(tx *sqlx.Tx) error {
+		query := tx.Rebind(`DELETE FROM logs WHERE id=?`)
+		_, err := tx.ExecContext(ctx, query, call.ID)
+		if err != nil {
+			return err
+		}
+
+		query = tx.Rebind(`INSERT INTO logs (id, app_id, fn_id, log) VALUES (?, ?, ?, ?);`)
+		_, err = tx.ExecContext(ctx, query, call.ID, call.AppID, call.FnID, log)
+		return err
+	})
+}
+
+func (ds *SQLStore) GetLog(ctx context.Context, fnID, callID string) (io.Reader, error) {
+	query := ds.db.Rebind(`SELECT log FROM logs WHERE fn_id=? AND id=?`)
+	row := ds.db.QueryRowContext(ctx, query, fnID, callID)
+
+	var log string
+	err := row.Scan(&log)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrCallLogNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return strings.NewReader(log), nil
+}
+
+// AcquireLease takes or renews a lease. Each step is a single statement that
+// either succeeds or conflicts, so no two holders can both win a race for it.
+func (ds *SQLStore) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	expires := now.Add(ttl).UnixNano()
+
+	query := ds.db.Rebind(`UPDATE leases SET holder=?, expires_at=? WHERE name=? AND (holder=? OR expires_at<?)`)
+	res, err := ds.db.ExecContext(ctx, query, holder, expires, name, holder, now.UnixNano())
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		return true, nil
+	}
+
+	query = ds.db.Rebind(`INSERT INTO leases (name, holder, expires_at) VALUES (?, ?, ?)`)
+	_, err = ds.db.ExecContext(ctx, query, name, holder, expires)
+	if err == nil {
+		return true, nil
+	} else if !ds.helper.IsDuplicateKeyError(err) {
+		return false, err
+	}
+
+	// some databases report no rows affected when an update changes nothing,
+	// so see who has it rather than assume it is taken
+	var current string
+	var currentExpiry int64
+	query = ds.db.Rebind(`SELECT holder, expires_at FROM leases WHERE name=?`)
+	err = ds.db.QueryRowContext(ctx, query, name).Scan(&current, &currentExpiry)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return current == holder && currentExpiry >= now.UnixNano(), nil
+}
+
+// ReleaseLease removes a lease if it is held by holder
+func (ds *SQLStore) ReleaseLease(ctx context.Context, name, holder string) error {
+	query := ds.db.Rebind(`DELETE FROM leases WHERE name=? AND holder=?`)
+	_, err := ds.db.ExecContext(ctx, query, name, holder)
+	return err
+}
+
+// how many times Reserve goes after another call when the one it picked is
+// reserved by someone else first
+const maxReserveAttempts = 5
+
+// Push stores a call on the queue. Payloads may be binary, so they are kept
+// base64 encoded apart from the rest of the call.
+func (ds *SQLStore) Push(ctx context.Context, call *models.Call) (*models.Call, error) {
+	c := *call
+	c.Payload = ""
+	b, err := json.Marshal(&c)
+	if err != nil {
+		return nil, err
+	}
+	payload := base64.StdEncoding.EncodeToString([]byte(call.Payload))
+	due := time.Now().Add(time.Duration(call.Delay) * time.Second).UnixNano()
+
+	err = ds.Tx(func(tx *sqlx.Tx) error {
+		query := tx.Rebind(`DELETE FROM queued_calls WHERE id=?`)
+		_, err := tx.ExecContext(ctx, query, call.ID)
+		if err != nil {
+			return err
+		}
+
+		query = tx.Rebind(`INSERT INTO queued_calls (id, app_id, fn_id, call, payload, due_at, reserved_until) VALUES (?, ?, ?, ?, ?, ?, 0)`)
+		_, err = tx.ExecContext(ctx, query, call.ID, call.AppID, call.FnID, string(b), payload, due)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return call, nil
+}
+
+// Reserve picks the oldest due call and reserves it, only if nobody else
+// reserved it in the meantime.
+func (ds *SQLStore) Reserve(ctx context.Context, timeout time.Duration) (*models.Call, error) {
+	for i := 0; i < maxReserveAttempts; i++ {
+		now := time.Now().UnixNano()
+
+		var callID, callJSON, payload string
+		var reservedUntil int64
+		query := ds.db.Rebind(`SELECT id, call, payload, reserved_until FROM queued_calls WHERE due_at<=? AND reserved_until<=? ORDER BY due_at, id LIMIT 1`)
+		err := ds.db.QueryRowContext(ctx, query, now, now).Scan(&callID, &callJSON, &payload, &reservedUntil)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		query = ds.db.Rebind(`UPDATE queued_calls SET reserved_until=? WHERE id=? AND reserved_until=?`)
+		res, err := ds.db.ExecContext(ctx, query, now+int64(timeout), callID, reservedUntil)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return nil, err
+		} else if n == 0 {
+			// someone beat us to it, go after the next one
+			continue
+		}
+
+		var call models.Call
+		if err := json.Unmarshal([]byte(callJSON), &call); err != nil {
+			return nil, err
+		}
+		b, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return nil, err
+		}
+		call.Payload = string(b)
+		return &call, nil
+	}
+	return nil, nil
+}
+
+// Delete removes a call from the queue
+func (ds *SQLStore) Delete(ctx context.Context, call *models.Call) error {
+	query := ds.db.Rebind(`DELETE FROM queued_calls WHERE id=?`)
+	_, err := ds.db.ExecContext(ctx, query, call.ID)
+	return err
+}
+
 // Close closes the database, releasing any open resources.
 func (ds *SQLStore) Close() error {
 	return ds.db.Close()
@@ -1057,4 +2324,7 @@ func (ds *SQLStore) Close() error {
 
 func init() {
 	datastore.Register(sqlDsProvider(0))
+	callstore.Register(sqlCsProvider(0))
+	logs.Register(sqlLsProvider(0))
+	mqs.Register(sqlMqProvider(0))
 }
@@ -5,7 +5,10 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/fnproject/fn/api/callstore"
+	"github.com/fnproject/fn/api/callstore/callstoretest"
 	"github.com/fnproject/fn/api/datastore/datastoretest"
 	"github.com/fnproject/fn/api/datastore/internal/datastoreutil"
 	"github.com/fnproject/fn/api/datastore/sql/migratex"
@@ -13,7 +16,11 @@ import (
 	_ "github.com/fnproject/fn/api/datastore/sql/mysql"
 	_ "github.com/fnproject/fn/api/datastore/sql/postgres"
 	_ "github.com/fnproject/fn/api/datastore/sql/sqlite"
+	"github.com/fnproject/fn/api/logs"
+	"github.com/fnproject/fn/api/logs/logstest"
 	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/api/mqs"
+	"github.com/fnproject/fn/api/mqs/mqstest"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -129,6 +136,95 @@ func TestDatastore(t *testing.T) {
 
 }
 
+func TestCallStore(t *testing.T) {
+	ctx := context.Background()
+	defer os.RemoveAll("sqlite_test_dir")
+	u := "sqlite3://sqlite_test_dir"
+	f := func(t *testing.T) models.CallStore {
+		os.RemoveAll("sqlite_test_dir")
+		ds, err := newDS(ctx, u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return callstore.Wrap(ds)
+	}
+	callstoretest.RunAllTests(t, f)
+}
+
+func TestLogStore(t *testing.T) {
+	ctx := context.Background()
+	defer os.RemoveAll("sqlite_test_dir")
+	u := "sqlite3://sqlite_test_dir"
+	f := func(t *testing.T) models.LogStore {
+		os.RemoveAll("sqlite_test_dir")
+		ds, err := newDS(ctx, u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return logs.Wrap(ds)
+	}
+	logstest.RunAllTests(t, f)
+}
+
+func TestMQ(t *testing.T) {
+	ctx := context.Background()
+	defer os.RemoveAll("sqlite_test_dir")
+	u := "sqlite3://sqlite_test_dir"
+	f := func(t *testing.T) models.MQ {
+		os.RemoveAll("sqlite_test_dir")
+		ds, err := newDS(ctx, u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return mqs.Wrap(ds)
+	}
+	mqstest.RunAllTests(t, f)
+}
+
+func TestLeaser(t *testing.T) {
+	ctx := context.Background()
+	defer os.RemoveAll("sqlite_test_dir")
+	u := "sqlite3://sqlite_test_dir"
+	os.RemoveAll("sqlite_test_dir")
+	ds, err := newDS(ctx, u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ds.Close()
+
+	acquire := func(holder string, ttl time.Duration, expected bool) {
+		t.Helper()
+		ok, err := ds.AcquireLease(ctx, "test", holder, ttl)
+		if err != nil {
+			t.Fatalf("unexpected error acquiring lease for %s: %v", holder, err)
+		}
+		if ok != expected {
+			t.Fatalf("expected lease acquisition by %s to be %v, got %v", holder, expected, ok)
+		}
+	}
+
+	acquire("a", time.Minute, true)
+	acquire("b", time.Minute, false)
+	// renewals by the holder succeed
+	acquire("a", time.Minute, true)
+
+	// releasing by anyone but the holder does nothing
+	if err := ds.ReleaseLease(ctx, "test", "b"); err != nil {
+		t.Fatal(err)
+	}
+	acquire("b", time.Minute, false)
+
+	if err := ds.ReleaseLease(ctx, "test", "a"); err != nil {
+		t.Fatal(err)
+	}
+	acquire("b", time.Millisecond, true)
+
+	// lapsed leases may be taken over
+	time.Sleep(5 * time.Millisecond)
+	acquire("a", time.Minute, true)
+	acquire("b", time.Minute, false)
+}
+
 func TestClose(t *testing.T) {
 	ctx := context.Background()
 	defer os.RemoveAll("sqlite_test_dir")
@@ -0,0 +1,304 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+)
+
+// Key is a key that tokens may be signed with, a public key or, for HS*
+// algorithms, a shared secret
+type Key struct {
+	// ID is the kid of the key, tokens naming a kid are only checked against keys with that kid or none
+	ID string
+	// Algorithm is the only algorithm the key may be used with, any that fits the key if empty
+	Algorithm string
+
+	key interface{}
+}
+
+// NewKey makes a key of a public key, an *rsa.PublicKey, *ecdsa.PublicKey or
+// ed25519.PublicKey, or of the secret of HS* algorithms as a []byte
+func NewKey(id, alg string, key interface{}) (*Key, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+	case []byte:
+		if len(k) == 0 {
+			return nil, errors.New("empty secret")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	if alg != "" {
+		if _, ok := algorithms[alg]; !ok {
+			return nil, fmt.Errorf("unsupported algorithm %q", alg)
+		}
+	}
+	return &Key{ID: id, Algorithm: alg, key: key}, nil
+}
+
+type algorithm struct {
+	hash crypto.Hash
+	// family is the kind of key the algorithm is used with, RS, PS, ES, EdDSA or HS
+	family string
+	// curve is the curve ES algorithms are used with
+	curve elliptic.Curve
+}
+
+var algorithms = map[string]algorithm{
+	"RS256": {crypto.SHA256, "RS", nil},
+	"RS384": {crypto.SHA384, "RS", nil},
+	"RS512": {crypto.SHA512, "RS", nil},
+	"PS256": {crypto.SHA256, "PS", nil},
+	"PS384": {crypto.SHA384, "PS", nil},
+	"PS512": {crypto.SHA512, "PS", nil},
+	"ES256": {crypto.SHA256, "ES", elliptic.P256()},
+	"ES384": {crypto.SHA384, "ES", elliptic.P384()},
+	"ES512": {crypto.SHA512, "ES", elliptic.P521()},
+	"EdDSA": {0, "EdDSA", nil},
+	"HS256": {crypto.SHA256, "HS", nil},
+	"HS384": {crypto.SHA384, "HS", nil},
+	"HS512": {crypto.SHA512, "HS", nil},
+}
+
+// fits tells if the key can verify tokens signed with alg
+func (k *Key) fits(alg string) bool {
+	a, ok := algorithms[alg]
+	if !ok || (k.Algorithm != "" && k.Algorithm != alg) {
+		return false
+	}
+	switch key := k.key.(type) {
+	case *rsa.PublicKey:
+		return a.family == "RS" || a.family == "PS"
+	case *ecdsa.PublicKey:
+		return a.family == "ES" && key.Curve == a.curve
+	case ed25519.PublicKey:
+		return a.family == "EdDSA"
+	case []byte:
+		return a.family == "HS"
+	}
+	return false
+}
+
+// verify checks that sig is the signature of signed with alg, which must fit the key
+func (k *Key) verify(alg string, signed, sig []byte) bool {
+	a := algorithms[alg]
+	var digest []byte
+	if a.hash != 0 {
+		h := a.hash.New()
+		h.Write(signed)
+		digest = h.Sum(nil)
+	}
+
+	switch key := k.key.(type) {
+	case *rsa.PublicKey:
+		if a.family == "PS" {
+			return rsa.VerifyPSS(key, a.hash, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		}
+		return rsa.VerifyPKCS1v15(key, a.hash, digest, sig) == nil
+	case *ecdsa.PublicKey:
+		// the signature is r and s, each the size of the curve
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(key, digest, r, s)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, signed, sig)
+	case []byte:
+		mac := hmac.New(a.hash.New, key)
+		mac.Write(signed)
+		return hmac.Equal(sig, mac.Sum(nil))
+	}
+	return false
+}
+
+// LoadKeyFiles loads the keys in files, each either a JWKS document or PEM
+// encoded public keys or certificates
+func LoadKeyFiles(paths ...string) ([]*Key, error) {
+	var keys []*Key
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var fileKeys []*Key
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+			fileKeys, err = ParseJWKS(data)
+		} else {
+			fileKeys, err = ParsePEM(data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid keys in %s: %v", path, err)
+		}
+		keys = append(keys, fileKeys...)
+	}
+	return keys, nil
+}
+
+// ParsePEM parses PEM encoded public keys and certificates, the keys have no
+// id so they are checked against any token signed with an algorithm that fits
+func ParsePEM(data []byte) ([]*Key, error) {
+	var keys []*Key
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		var pub interface{}
+		var err error
+		switch block.Type {
+		case "PUBLIC KEY":
+			pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			pub, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			cert, err = x509.ParseCertificate(block.Bytes)
+			if err == nil {
+				pub = cert.PublicKey
+			}
+		default:
+			err = fmt.Errorf("unsupported PEM block %q", block.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+		key, err := NewKey("", "", pub)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM encoded keys")
+	}
+	return keys, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+var curves = map[string]elliptic.Curve{
+	"P-256": elliptic.P256(),
+	"P-384": elliptic.P384(),
+	"P-521": elliptic.P521(),
+}
+
+// ParseJWKS parses a JWKS document, skipping keys that are not for signatures
+// or are of types tokens can't be signed with
+func ParseJWKS(data []byte) ([]*Key, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	var keys []*Key
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var pub interface{}
+		switch k.Kty {
+		case "RSA":
+			n, err := decodeBigInt(k.N)
+			if err != nil {
+				return nil, err
+			}
+			e, err := decodeBigInt(k.E)
+			if err != nil {
+				return nil, err
+			}
+			if !e.IsInt64() || e.Int64() > 1<<31-1 {
+				return nil, fmt.Errorf("invalid exponent of key %q", k.Kid)
+			}
+			pub = &rsa.PublicKey{N: n, E: int(e.Int64())}
+		case "EC":
+			curve, ok := curves[k.Crv]
+			if !ok {
+				return nil, fmt.Errorf("unsupported curve %q of key %q", k.Crv, k.Kid)
+			}
+			x, err := decodeBigInt(k.X)
+			if err != nil {
+				return nil, err
+			}
+			y, err := decodeBigInt(k.Y)
+			if err != nil {
+				return nil, err
+			}
+			if !curve.IsOnCurve(x, y) {
+				return nil, fmt.Errorf("invalid point of key %q", k.Kid)
+			}
+			pub = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		case "OKP":
+			if k.Crv != "Ed25519" {
+				return nil, fmt.Errorf("unsupported curve %q of key %q", k.Crv, k.Kid)
+			}
+			x, err := decodeSegment(k.X)
+			if err != nil {
+				return nil, err
+			}
+			if len(x) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("invalid size of key %q", k.Kid)
+			}
+			pub = ed25519.PublicKey(x)
+		case "oct":
+			secret, err := decodeSegment(k.K)
+			if err != nil {
+				return nil, err
+			}
+			pub = secret
+		default:
+			continue
+		}
+		key, err := NewKey(k.Kid, k.Alg, pub)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := decodeSegment(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,182 @@
+// Package jwt verifies JSON Web Tokens offline, against keys loaded up front
+// from JWKS documents or PEM files, so that calls can be authenticated
+// without a round trip to an identity provider.
+package jwt
+
+import (
+	// hashes of the algorithms tokens may be signed with
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Leeway is how far the clocks of the issuers of tokens may be off from ours
+const Leeway = time.Minute
+
+var (
+	// ErrMalformed is returned for anything that is not a signed token
+	ErrMalformed = errors.New("malformed token")
+	// ErrUnsupportedAlgorithm is returned for tokens signed with an algorithm that is not supported, or none
+	ErrUnsupportedAlgorithm = errors.New("unsupported token signing algorithm")
+	// ErrUnknownKey is returned for tokens that no key can verify
+	ErrUnknownKey = errors.New("no key for token")
+	// ErrInvalidSignature is returned for tokens whose signature doesn't match
+	ErrInvalidSignature = errors.New("invalid token signature")
+	// ErrExpired is returned for tokens past their exp
+	ErrExpired = errors.New("token expired")
+	// ErrNotYetValid is returned for tokens before their nbf
+	ErrNotYetValid = errors.New("token not valid yet")
+	// ErrInvalidIssuer is returned for tokens not issued by the issuer tokens are required to be from
+	ErrInvalidIssuer = errors.New("invalid token issuer")
+)
+
+// Verifier verifies tokens against a set of keys
+type Verifier struct {
+	keys   []*Key
+	issuer string
+	now    func() time.Time
+}
+
+// NewVerifier makes a verifier of tokens signed with any of keys. If issuer
+// is not empty, tokens must have it as their iss.
+func NewVerifier(keys []*Key, issuer string) *Verifier {
+	return &Verifier{keys: keys, issuer: issuer, now: time.Now}
+}
+
+// Claims are the claims of a verified token
+type Claims map[string]interface{}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verify checks the signature of token, and that it is in its validity
+// period, returning its claims
+func (v *Verifier) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+	var h header
+	if err := decodeJSON(parts[0], &h); err != nil {
+		return nil, ErrMalformed
+	}
+	var claims Claims
+	if err := decodeJSON(parts[1], &claims); err != nil || claims == nil {
+		return nil, ErrMalformed
+	}
+	sig, err := decodeSegment(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	if _, ok := algorithms[h.Alg]; !ok {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	found := false
+	verified := false
+	for _, k := range v.keys {
+		if (h.Kid != "" && k.ID != "" && k.ID != h.Kid) || !k.fits(h.Alg) {
+			continue
+		}
+		found = true
+		if k.verify(h.Alg, signed, sig) {
+			verified = true
+			break
+		}
+	}
+	if !found {
+		return nil, ErrUnknownKey
+	}
+	if !verified {
+		return nil, ErrInvalidSignature
+	}
+
+	now := v.now()
+	if exp, ok := claims.time("exp"); ok && now.After(exp.Add(Leeway)) {
+		return nil, ErrExpired
+	}
+	if nbf, ok := claims.time("nbf"); ok && now.Before(nbf.Add(-Leeway)) {
+		return nil, ErrNotYetValid
+	}
+	if v.issuer != "" && claims["iss"] != v.issuer {
+		return nil, ErrInvalidIssuer
+	}
+	return claims, nil
+}
+
+func decodeJSON(segment string, v interface{}) error {
+	b, err := decodeSegment(segment)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// time gets a NumericDate claim, in seconds since the epoch
+func (c Claims) time(name string) (time.Time, bool) {
+	n, ok := c[name].(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	secs, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(secs*float64(time.Second))), true
+}
+
+// Audiences are the audiences the token is intended for, the aud claim may be
+// one or a list of them
+func (c Claims) Audiences() []string {
+	switch aud := c["aud"].(type) {
+	case string:
+		return []string{aud}
+	case []interface{}:
+		var auds []string
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				auds = append(auds, s)
+			}
+		}
+		return auds
+	}
+	return nil
+}
+
+// Has tells if the claim name has value, or is a list that contains it.
+// Claims that are not strings are compared in their JSON form.
+func (c Claims) Has(name, value string) bool {
+	claim, ok := c[name]
+	if !ok {
+		return false
+	}
+	if list, ok := claim.([]interface{}); ok {
+		for _, v := range list {
+			if ClaimString(v) == value {
+				return true
+			}
+		}
+		return false
+	}
+	return ClaimString(claim) == value
+}
+
+// ClaimString is the value of a claim as a string, strings as they are and
+// anything else in its JSON form
+func ClaimString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,154 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// sign makes a token of claims signed with alg by key, a private key or a secret
+func sign(t *testing.T, alg, kid string, key interface{}, claims map[string]interface{}) string {
+	h, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	c, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+
+	var sig []byte
+	var err error
+	a := algorithms[alg]
+	var digest []byte
+	if a.hash != 0 {
+		hash := a.hash.New()
+		hash.Write([]byte(signed))
+		digest = hash.Sum(nil)
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if a.family == "PS" {
+			sig, err = rsa.SignPSS(rand.Reader, k, a.hash, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			sig, err = rsa.SignPKCS1v15(rand.Reader, k, a.hash, digest)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, serr := ecdsa.Sign(rand.Reader, k, digest)
+		err = serr
+		size := (k.Curve.Params().BitSize + 7) / 8
+		sig = make([]byte, 2*size)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size:])
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(k, []byte(signed))
+	case []byte:
+		mac := hmac.New(a.hash.New, k)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func mustKey(t *testing.T, id, alg string, key interface{}) *Key {
+	k, err := NewKey(id, alg, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := []byte("secret")
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewVerifier([]*Key{
+		mustKey(t, "rsa", "", &rsaKey.PublicKey),
+		mustKey(t, "ec", "", &ecKey.PublicKey),
+		mustKey(t, "", "", edPub),
+		mustKey(t, "hs", "HS256", secret),
+	}, "https://issuer.example.com")
+	now := time.Unix(1600000000, 0)
+	v.now = func() time.Time { return now }
+
+	valid := map[string]interface{}{"iss": "https://issuer.example.com", "sub": "alice", "exp": now.Add(time.Hour).Unix()}
+	with := func(k string, val interface{}) map[string]interface{} {
+		claims := make(map[string]interface{})
+		for k, v := range valid {
+			claims[k] = v
+		}
+		claims[k] = val
+		return claims
+	}
+
+	for i, tc := range []struct {
+		token    string
+		expected error
+	}{
+		{sign(t, "RS256", "rsa", rsaKey, valid), nil},
+		{sign(t, "RS512", "", rsaKey, valid), nil},
+		{sign(t, "PS256", "rsa", rsaKey, valid), nil},
+		{sign(t, "ES256", "ec", ecKey, valid), nil},
+		{sign(t, "EdDSA", "any", edKey, valid), nil},
+		{sign(t, "HS256", "hs", secret, valid), nil},
+		{sign(t, "RS256", "rsa", rsaKey, with("exp", now.Add(-30*time.Second).Unix())), nil},
+
+		{sign(t, "RS256", "rsa", otherKey, valid), ErrInvalidSignature},
+		{sign(t, "RS256", "ec", rsaKey, valid), ErrUnknownKey},
+		{sign(t, "ES384", "ec", ecKey, valid), ErrUnknownKey},
+		{sign(t, "HS512", "hs", secret, valid), ErrUnknownKey},
+		{sign(t, "none", "", nil, valid), ErrUnsupportedAlgorithm},
+		{sign(t, "RS256", "rsa", rsaKey, with("exp", now.Add(-time.Hour).Unix())), ErrExpired},
+		{sign(t, "RS256", "rsa", rsaKey, with("nbf", now.Add(time.Hour).Unix())), ErrNotYetValid},
+		{sign(t, "RS256", "rsa", rsaKey, with("iss", "https://other.example.com")), ErrInvalidIssuer},
+		{"not.a.token", ErrMalformed},
+		{"token", ErrMalformed},
+	} {
+		claims, err := v.Verify(tc.token)
+		if err != tc.expected {
+			t.Errorf("Test %d: expected %v, got %v", i, tc.expected, err)
+			continue
+		}
+		if err == nil && claims["sub"] != "alice" {
+			t.Errorf("Test %d: expected the claims of the token, got %v", i, claims)
+		}
+	}
+}
+
+func TestClaims(t *testing.T) {
+	var claims Claims
+	if err := json.Unmarshal([]byte(`{"aud":["a","b"],"groups":["admin","dev"],"level":3,"admin":true}`), &claims); err != nil {
+		t.Fatal(err)
+	}
+	if auds := claims.Audiences(); len(auds) != 2 || auds[0] != "a" || auds[1] != "b" {
+		t.Errorf("expected the list of audiences, got %v", auds)
+	}
+	if !claims.Has("groups", "dev") || claims.Has("groups", "ops") || !claims.Has("level", "3") || !claims.Has("admin", "true") || claims.Has("missing", "") {
+		t.Errorf("expected claims to be matched by value")
+	}
+
+	single := Claims{"aud": "a"}
+	if auds := single.Audiences(); len(auds) != 1 || auds[0] != "a" {
+		t.Errorf("expected a single audience, got %v", auds)
+	}
+}
@@ -0,0 +1,121 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/models"
+	"github.com/sirupsen/logrus"
+)
+
+// fileStore keeps each call's log in its own file, under a directory per fn:
+// <dir>/<fn_id>/<call_id>.log
+type fileStore struct {
+	dir string
+}
+
+type fileProvider int
+
+func (fileProvider) Supports(u string) bool {
+	uri, err := url.Parse(u)
+	return err == nil && uri.Scheme == "file"
+}
+
+func (fileProvider) New(ctx context.Context, u string) (models.LogStore, error) {
+	return NewFileStore(ctx, u)
+}
+
+func (fileProvider) String() string {
+	return "file"
+}
+
+// NewFileStore creates a log store which writes logs to files in the directory
+// given by a url of the form file:///var/lib/fn/logs
+func NewFileStore(ctx context.Context, u string) (models.LogStore, error) {
+	uri, err := url.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+	// allow relative paths, ie file://data/logs
+	dir := filepath.FromSlash(uri.Host + uri.Path)
+	if dir == "" {
+		return nil, fmt.Errorf("no directory given in log store url %s", u)
+	}
+
+	err = os.MkdirAll(dir, 0750)
+	if err != nil {
+		return nil, err
+	}
+
+	common.Logger(ctx).WithFields(logrus.Fields{"dir": dir}).Info("using file system log store")
+	return &fileStore{dir: dir}, nil
+}
+
+var errInvalidID = errors.New("invalid fn or call id for file system log store")
+
+// logPath returns the file for a call's log, refusing ids which could escape
+// the store's directory
+func (fs *fileStore) logPath(fnID, callID string) (string, error) {
+	for _, p := range []string{fnID, callID} {
+		if p == "." || p == ".." || filepath.Base(p) != p {
+			return "", errInvalidID
+		}
+	}
+	return filepath.Join(fs.dir, fnID, callID+".log"), nil
+}
+
+func (fs *fileStore) InsertLog(ctx context.Context, call *models.Call, callLog io.Reader) error {
+	path, err := fs.logPath(call.FnID, call.ID)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	err = os.MkdirAll(dir, 0750)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so that readers never see a partial log
+	f, err := ioutil.TempFile(dir, call.ID+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, callLog)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (fs *fileStore) GetLog(ctx context.Context, fnID, callID string) (io.Reader, error) {
+	path, err := fs.logPath(fnID, callID)
+	if err != nil {
+		// no such log could have been stored
+		return nil, models.ErrCallLogNotFound
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, models.ErrCallLogNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+func init() {
+	Register(fileProvider(0))
+}
@@ -0,0 +1,40 @@
+package logs
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fnproject/fn/api/logs/logstest"
+	"github.com/fnproject/fn/api/models"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fn-logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := func(t *testing.T) models.LogStore {
+		ls, err := New(context.Background(), "file://"+dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return Wrap(ls)
+	}
+	logstest.RunAllTests(t, f)
+
+	t.Run("ids may not escape the store", func(t *testing.T) {
+		ls := f(t)
+		call := &models.Call{ID: "../../escaped", FnID: "fn"}
+		if err := ls.InsertLog(context.Background(), call, strings.NewReader("nope")); err != errInvalidID {
+			t.Fatalf("expected %v, got %v", errInvalidID, err)
+		}
+		if _, err := ls.GetLog(context.Background(), "..", "call"); err != models.ErrCallLogNotFound {
+			t.Fatalf("expected %v, got %v", models.ErrCallLogNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/models"
+	"github.com/sirupsen/logrus"
+)
+
+// New creates a LogStore from the specified URL
+func New(ctx context.Context, storeURL string) (models.LogStore, error) {
+	log := common.Logger(ctx)
+	log.WithFields(logrus.Fields{"log_store": common.MaskPassword(storeURL)}).Debug("creating new log store")
+
+	for _, provider := range providers {
+		if provider.Supports(storeURL) {
+			return provider.New(ctx, storeURL)
+		}
+	}
+	return nil, fmt.Errorf("no log store provider found for storage url %s", storeURL)
+}
+
+// Wrap adds argument validation and tracing to a LogStore
+func Wrap(ls models.LogStore) models.LogStore {
+	return metricLS(newValidator(ls))
+}
+
+// Provider is a log store provider
+type Provider interface {
+	fmt.Stringer
+	// Supports indicates if this provider can handle a given log store.
+	Supports(url string) bool
+	// New creates a new log store from the specified URL
+	New(ctx context.Context, url string) (models.LogStore, error)
+}
+
+var providers []Provider
+
+// Register globally registers a log store provider
+func Register(provider Provider) {
+	logrus.Infof("Registering log store provider '%s'", provider)
+	providers = append(providers, provider)
+}
@@ -0,0 +1,120 @@
+package logstest
+
+// Log store correctness tests -
+// These tests run validation tests on an underlying log store implementation and can be re-used for new log stores.
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/fnproject/fn/api/id"
+	"github.com/fnproject/fn/api/models"
+)
+
+// LogStoreFunc provides an instance of a log store
+type LogStoreFunc func(*testing.T) models.LogStore
+
+func newCall() *models.Call {
+	return &models.Call{
+		ID:    id.New().String(),
+		FnID:  id.New().String(),
+		AppID: id.New().String(),
+	}
+}
+
+// RunAllTests runs the log store test suite against the log store provided by lsf
+func RunAllTests(t *testing.T, lsf LogStoreFunc) {
+	ctx := context.Background()
+
+	t.Run("insert and get log", func(t *testing.T) {
+		ls := lsf(t)
+		call := newCall()
+		logText := "hello\nworld\n"
+
+		if err := ls.InsertLog(ctx, call, strings.NewReader(logText)); err != nil {
+			t.Fatalf("failed to insert log: %v", err)
+		}
+
+		r, err := ls.GetLog(ctx, call.FnID, call.ID)
+		if err != nil {
+			t.Fatalf("failed to get log: %v", err)
+		}
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatalf("failed to read log: %v", err)
+		}
+		if string(b) != logText {
+			t.Fatalf("expected log %q, got %q", logText, b)
+		}
+	})
+
+	t.Run("insert replaces existing log", func(t *testing.T) {
+		ls := lsf(t)
+		call := newCall()
+		if err := ls.InsertLog(ctx, call, strings.NewReader("first")); err != nil {
+			t.Fatalf("failed to insert log: %v", err)
+		}
+		if err := ls.InsertLog(ctx, call, strings.NewReader("second")); err != nil {
+			t.Fatalf("failed to re-insert log: %v", err)
+		}
+		r, err := ls.GetLog(ctx, call.FnID, call.ID)
+		if err != nil {
+			t.Fatalf("failed to get log: %v", err)
+		}
+		b, _ := ioutil.ReadAll(r)
+		if string(b) != "second" {
+			t.Fatalf("expected log %q, got %q", "second", b)
+		}
+	})
+
+	t.Run("insert empty log", func(t *testing.T) {
+		ls := lsf(t)
+		call := newCall()
+		if err := ls.InsertLog(ctx, call, new(bytes.Buffer)); err != nil {
+			t.Fatalf("failed to insert log: %v", err)
+		}
+		r, err := ls.GetLog(ctx, call.FnID, call.ID)
+		if err != nil {
+			t.Fatalf("failed to get log: %v", err)
+		}
+		b, _ := ioutil.ReadAll(r)
+		if len(b) != 0 {
+			t.Fatalf("expected empty log, got %q", b)
+		}
+	})
+
+	t.Run("get missing log", func(t *testing.T) {
+		ls := lsf(t)
+		call := newCall()
+		_, err := ls.GetLog(ctx, call.FnID, call.ID)
+		if err != models.ErrCallLogNotFound {
+			t.Fatalf("expected %v, got %v", models.ErrCallLogNotFound, err)
+		}
+
+		if err := ls.InsertLog(ctx, call, strings.NewReader("log")); err != nil {
+			t.Fatalf("failed to insert log: %v", err)
+		}
+		_, err = ls.GetLog(ctx, id.New().String(), call.ID)
+		if err != models.ErrCallLogNotFound {
+			t.Fatalf("expected %v for log of another fn, got %v", models.ErrCallLogNotFound, err)
+		}
+	})
+
+	t.Run("empty arguments", func(t *testing.T) {
+		ls := lsf(t)
+		if err := ls.InsertLog(ctx, &models.Call{FnID: "fn"}, strings.NewReader("")); err != models.ErrDatastoreEmptyCallID {
+			t.Fatalf("expected %v, got %v", models.ErrDatastoreEmptyCallID, err)
+		}
+		if err := ls.InsertLog(ctx, &models.Call{ID: "call"}, strings.NewReader("")); err != models.ErrDatastoreEmptyFnID {
+			t.Fatalf("expected %v, got %v", models.ErrDatastoreEmptyFnID, err)
+		}
+		if _, err := ls.GetLog(ctx, "", "call"); err != models.ErrDatastoreEmptyFnID {
+			t.Fatalf("expected %v, got %v", models.ErrDatastoreEmptyFnID, err)
+		}
+		if _, err := ls.GetLog(ctx, "fn", ""); err != models.ErrDatastoreEmptyCallID {
+			t.Fatalf("expected %v, got %v", models.ErrDatastoreEmptyCallID, err)
+		}
+	})
+}
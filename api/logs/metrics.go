@@ -0,0 +1,29 @@
+package logs
+
+import (
+	"context"
+	"io"
+
+	"github.com/fnproject/fn/api/models"
+	"go.opencensus.io/trace"
+)
+
+func metricLS(ls models.LogStore) models.LogStore {
+	return &metricls{ls}
+}
+
+type metricls struct {
+	ls models.LogStore
+}
+
+func (m *metricls) InsertLog(ctx context.Context, call *models.Call, callLog io.Reader) error {
+	ctx, span := trace.StartSpan(ctx, "ls_insert_log")
+	defer span.End()
+	return m.ls.InsertLog(ctx, call, callLog)
+}
+
+func (m *metricls) GetLog(ctx context.Context, fnID, callID string) (io.Reader, error) {
+	ctx, span := trace.StartSpan(ctx, "ls_get_log")
+	defer span.End()
+	return m.ls.GetLog(ctx, fnID, callID)
+}
@@ -0,0 +1,42 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/fnproject/fn/api/models"
+)
+
+type mock struct {
+	mu   sync.RWMutex
+	Logs map[string][]byte
+}
+
+// NewMock creates a new in-memory log store, suitable for tests
+func NewMock() models.LogStore {
+	return newValidator(&mock{Logs: make(map[string][]byte)})
+}
+
+func (m *mock) InsertLog(ctx context.Context, call *models.Call, callLog io.Reader) error {
+	b, err := ioutil.ReadAll(callLog)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.Logs[call.FnID+"/"+call.ID] = b
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *mock) GetLog(ctx context.Context, fnID, callID string) (io.Reader, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	b, ok := m.Logs[fnID+"/"+callID]
+	if !ok {
+		return nil, models.ErrCallLogNotFound
+	}
+	return bytes.NewReader(b), nil
+}
@@ -0,0 +1,15 @@
+package logs
+
+import (
+	"testing"
+
+	"github.com/fnproject/fn/api/logs/logstest"
+	"github.com/fnproject/fn/api/models"
+)
+
+func TestLogStore(t *testing.T) {
+	f := func(t *testing.T) models.LogStore {
+		return NewMock()
+	}
+	logstest.RunAllTests(t, f)
+}
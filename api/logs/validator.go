@@ -0,0 +1,37 @@
+package logs
+
+import (
+	"context"
+	"io"
+
+	"github.com/fnproject/fn/api/models"
+)
+
+// newValidator returns a models.LogStore which validates certain arguments before delegating to ls.
+func newValidator(ls models.LogStore) models.LogStore {
+	return &validator{ls}
+}
+
+type validator struct {
+	models.LogStore
+}
+
+func (v *validator) InsertLog(ctx context.Context, call *models.Call, callLog io.Reader) error {
+	if call.ID == "" {
+		return models.ErrDatastoreEmptyCallID
+	}
+	if call.FnID == "" {
+		return models.ErrDatastoreEmptyFnID
+	}
+	return v.LogStore.InsertLog(ctx, call, callLog)
+}
+
+func (v *validator) GetLog(ctx context.Context, fnID, callID string) (io.Reader, error) {
+	if fnID == "" {
+		return nil, models.ErrDatastoreEmptyFnID
+	}
+	if callID == "" {
+		return nil, models.ErrDatastoreEmptyCallID
+	}
+	return v.LogStore.GetLog(ctx, fnID, callID)
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"unicode"
+
+	"github.com/fnproject/fn/api/common"
+)
+
+// MaxLengthAliasName is the longest name an alias can have
+const MaxLengthAliasName = 255
+
+// Alias is a named pointer of an app to one of its fns, such as prod or
+// staging. Triggers and the alias invoke endpoint that target an alias call
+// whichever fn it points to, so that deploying a new fn behind them is a
+// single update of the alias.
+type Alias struct {
+	ID    string `json:"id" db:"id"`
+	Name  string `json:"name" db:"name"`
+	AppID string `json:"app_id" db:"app_id"`
+	// FnID is the id of the fn of the app the alias points to, it is the only field of an alias that can be updated
+	FnID      string          `json:"fn_id" db:"fn_id"`
+	CreatedAt common.DateTime `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt common.DateTime `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+var (
+	//ErrAliasIDProvided indicates that an alias ID was specified when it shouldn't have been
+	ErrAliasIDProvided = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("ID cannot be provided for Alias creation"),
+	}
+	//ErrAliasIDMismatch - ID is not the same as in the path
+	ErrAliasIDMismatch = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("ID in path does not match ID in body"),
+	}
+	//ErrAliasMissingName - no name specified on alias creation
+	ErrAliasMissingName = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Missing name on Alias")}
+	//ErrAliasTooLongName - name exceeds maximum permitted name
+	ErrAliasTooLongName = err{
+		code:  http.StatusBadRequest,
+		error: fmt.Errorf("Alias name must be %v characters or less", MaxLengthAliasName)}
+	//ErrAliasInvalidName - name does not comply with naming spec
+	ErrAliasInvalidName = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid name for Alias")}
+	//ErrAliasMissingAppID - no app id specified on alias creation
+	ErrAliasMissingAppID = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Missing App ID on Alias")}
+	//ErrAliasMissingFnID - no fn id specified on alias creation
+	ErrAliasMissingFnID = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Missing Fn ID on Alias")}
+	//ErrAliasFnIDNotSameApp - the fn an alias points to does not belong to the app of the alias
+	ErrAliasFnIDNotSameApp = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid Fn ID - not owned by the app of the Alias")}
+	//ErrAliasNotFound - alias not found
+	ErrAliasNotFound = err{
+		code:  http.StatusNotFound,
+		error: errors.New("Alias not found")}
+	//ErrAliasExists - the app has an alias with the same name already
+	ErrAliasExists = err{
+		code:  http.StatusConflict,
+		error: errors.New("Alias with the same name already exists")}
+	//ErrAliasInUse - triggers of the app target the alias
+	ErrAliasInUse = err{
+		code:  http.StatusConflict,
+		error: errors.New("Alias is the target of triggers, which must be removed or pointed elsewhere first")}
+)
+
+// Validate checks that alias has valid data for inserting into a store
+func (a *Alias) Validate() error {
+	if a.AppID == "" {
+		return ErrAliasMissingAppID
+	}
+	if err := ValidateAliasName(a.Name); err != nil {
+		return err
+	}
+	if a.FnID == "" {
+		return ErrAliasMissingFnID
+	}
+	return nil
+}
+
+// ValidateAliasName checks that name can be the name of an alias, and so be
+// part of the path of its invoke endpoint
+func ValidateAliasName(name string) error {
+	if name == "" {
+		return ErrAliasMissingName
+	}
+	if len(name) > MaxLengthAliasName {
+		return ErrAliasTooLongName
+	}
+	for _, c := range name {
+		if !(unicode.IsLetter(c) || unicode.IsNumber(c) || c == '_' || c == '-') {
+			return ErrAliasInvalidName
+		}
+	}
+	return nil
+}
+
+// Clone creates a copy of an alias
+func (a *Alias) Clone() *Alias {
+	clone := new(Alias)
+	*clone = *a
+	return clone
+}
+
+// Equals compares two aliases, ignoring timestamp fields
+func (a *Alias) Equals(a2 *Alias) bool {
+	return a.ID == a2.ID && a.Name == a2.Name && a.AppID == a2.AppID && a.FnID == a2.FnID
+}
+
+// Update points the alias to the fn of patch, if it has one, and sets
+// updated_at if that changes it
+func (a *Alias) Update(patch *Alias) {
+	original := a.Clone()
+	if patch.FnID != "" {
+		a.FnID = patch.FnID
+	}
+	if !a.Equals(original) {
+		a.UpdatedAt = common.DateTime(time.Now())
+	}
+}
+
+// AliasFilter is a search criteria on aliases
+type AliasFilter struct {
+	//AppID searches for aliases of an app - mandatory
+	AppID string // this is exact match mandatory
+	//FnID searches for the aliases that point to a fn
+	FnID string // this is exact match
+
+	Cursor  string
+	PerPage int
+}
+
+// AliasList is a container of aliases returned by search, optionally indicating the next page cursor
+type AliasList struct {
+	NextCursor string   `json:"next_cursor,omitempty"`
+	Items      []*Alias `json:"items"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAliasValidate(t *testing.T) {
+	for _, tc := range []struct {
+		alias    *Alias
+		expected error
+	}{
+		{&Alias{AppID: "app", Name: "prod", FnID: "fn"}, nil},
+		{&Alias{AppID: "app", Name: "canary_2-b", FnID: "fn"}, nil},
+		{&Alias{Name: "prod", FnID: "fn"}, ErrAliasMissingAppID},
+		{&Alias{AppID: "app", FnID: "fn"}, ErrAliasMissingName},
+		{&Alias{AppID: "app", Name: "pr/od", FnID: "fn"}, ErrAliasInvalidName},
+		{&Alias{AppID: "app", Name: "pr od", FnID: "fn"}, ErrAliasInvalidName},
+		{&Alias{AppID: "app", Name: strings.Repeat("a", MaxLengthAliasName+1), FnID: "fn"}, ErrAliasTooLongName},
+		{&Alias{AppID: "app", Name: "prod"}, ErrAliasMissingFnID},
+	} {
+		if err := tc.alias.Validate(); err != tc.expected {
+			t.Errorf("expected %v for %#v, got %v", tc.expected, tc.alias, err)
+		}
+	}
+}
+
+func TestAliasUpdate(t *testing.T) {
+	alias := &Alias{ID: "id", AppID: "app", Name: "prod", FnID: "blue"}
+
+	alias.Update(&Alias{})
+	if alias.FnID != "blue" || !time.Time(alias.UpdatedAt).IsZero() {
+		t.Errorf("expected an empty patch to leave the alias alone, got %#v", alias)
+	}
+
+	alias.Update(&Alias{ID: "other", AppID: "other", Name: "staging", FnID: "green"})
+	if !alias.Equals(&Alias{ID: "id", AppID: "app", Name: "prod", FnID: "green"}) {
+		t.Errorf("expected only the fn of the alias to be updated, got %#v", alias)
+	}
+	if time.Time(alias.UpdatedAt).IsZero() {
+		t.Error("expected updated_at to be set")
+	}
+}
+
+func TestTriggerAliasTarget(t *testing.T) {
+	for _, tc := range []struct {
+		fnID     string
+		alias    string
+		expected error
+	}{
+		{"fn", "", nil},
+		{"", "prod", nil},
+		{"", "", ErrTriggerMissingFnID},
+		{"fn", "prod", ErrTriggerFnIDAndAlias},
+		{"", "pr/od", ErrAliasInvalidName},
+	} {
+		trigger := &Trigger{AppID: "app", Name: "trigger", FnID: tc.fnID, Alias: tc.alias, Type: "http", Source: "/trigger"}
+		if err := trigger.Validate(); err != tc.expected {
+			t.Errorf("expected %v for fn %q and alias %q, got %v", tc.expected, tc.fnID, tc.alias, err)
+		}
+	}
+
+	trigger := &Trigger{AppID: "app", Name: "trigger", FnID: "fn", Type: "http", Source: "/trigger"}
+	trigger.Update(&Trigger{Alias: "prod"})
+	if trigger.FnID != "" || trigger.Alias != "prod" {
+		t.Errorf("expected pointing a trigger to an alias to clear its fn, got %q and %q", trigger.FnID, trigger.Alias)
+	}
+	trigger.Update(&Trigger{FnID: "fn"})
+	if trigger.FnID != "fn" || trigger.Alias != "" {
+		t.Errorf("expected pointing a trigger to a fn to clear its alias, got %q and %q", trigger.FnID, trigger.Alias)
+	}
+}
@@ -172,6 +172,18 @@ func (m Annotations) GetString(key string) (string, error) {
 	return "", errors.New("Annotation not found")
 }
 
+// GetBool returns a bool value if the annotation value is a bool, otherwise an error
+func (m Annotations) GetBool(key string) (bool, error) {
+	if v, ok := m[key]; ok {
+		var b bool
+		if err := json.Unmarshal([]byte(*v), &b); err != nil {
+			return false, err
+		}
+		return b, nil
+	}
+	return false, errors.New("Annotation not found")
+}
+
 // Without returns a new annotations object with a value excluded
 func (m Annotations) Without(key string) Annotations {
 	nuVal := m.clone()
@@ -300,4 +300,18 @@ func TestGetAnnotations(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error trying to retrieve a string value for array annotation")
 	}
+
+	annotations, err = annotations.With("bool-annotation", true)
+	if err != nil {
+		t.Fatal("Cannot add bool annotation")
+	}
+	boolAnnotation, err := annotations.GetBool("bool-annotation")
+	if err != nil || !boolAnnotation {
+		t.Errorf("Got unexpected decoded value for bool annotation. Got: %v %v Expected true", boolAnnotation, err)
+	}
+
+	_, err = annotations.GetBool("string-annotation")
+	if err == nil {
+		t.Error("Expected error trying to retrieve a bool value for string annotation")
+	}
 }
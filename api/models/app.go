@@ -68,14 +68,15 @@ const (
 )
 
 type App struct {
-	ID          string          `json:"id" db:"id"`
-	Name        string          `json:"name" db:"name"`
-	Config      Config          `json:"config,omitempty" db:"config"`
-	Annotations Annotations     `json:"annotations,omitempty" db:"annotations"`
-	SyslogURL   *string         `json:"syslog_url,omitempty" db:"syslog_url"`
-	Shape       string          `json:"shape,omitempty" db:"shape"`
-	CreatedAt   common.DateTime `json:"created_at,omitempty" db:"created_at"`
-	UpdatedAt   common.DateTime `json:"updated_at,omitempty" db:"updated_at"`
+	ID           string          `json:"id" db:"id"`
+	Name         string          `json:"name" db:"name"`
+	Config       Config          `json:"config,omitempty" db:"config"`
+	SecretConfig Config          `json:"secret_config,omitempty" db:"secret_config"`
+	Annotations  Annotations     `json:"annotations,omitempty" db:"annotations"`
+	SyslogURL    *string         `json:"syslog_url,omitempty" db:"syslog_url"`
+	Shape        string          `json:"shape,omitempty" db:"shape"`
+	CreatedAt    common.DateTime `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt    common.DateTime `json:"updated_at,omitempty" db:"updated_at"`
 }
 
 func (a *App) Validate() error {
@@ -88,6 +89,10 @@ func (a *App) Validate() error {
 		return err
 	}
 
+	if _, err := SecretFiles(a.Annotations); err != nil {
+		return err
+	}
+
 	if a.SyslogURL != nil && *a.SyslogURL != "" {
 		url, err := url.Parse(strings.TrimSpace(*a.SyslogURL))
 		if err == nil {
@@ -151,13 +156,19 @@ func (a *App) Clone() *App {
 	clone := new(App)
 	*clone = *a // shallow copy
 
-	// now deep copy the map
+	// now deep copy the maps
 	if a.Config != nil {
 		clone.Config = make(Config, len(a.Config))
 		for k, v := range a.Config {
 			clone.Config[k] = v
 		}
 	}
+	if a.SecretConfig != nil {
+		clone.SecretConfig = make(Config, len(a.SecretConfig))
+		for k, v := range a.SecretConfig {
+			clone.SecretConfig[k] = v
+		}
+	}
 
 	return clone
 }
@@ -170,6 +181,7 @@ func (a1 *App) Equals(a2 *App) bool {
 	eq = eq && a1.ID == a2.ID
 	eq = eq && a1.Name == a2.Name
 	eq = eq && a1.Config.Equals(a2.Config)
+	eq = eq && a1.SecretConfig.Equals(a2.SecretConfig)
 	eq = eq && a1.SyslogURL == a2.SyslogURL
 	eq = eq && a1.Annotations.Equals(a2.Annotations)
 	eq = eq && a1.Shape == a2.Shape
@@ -188,6 +200,7 @@ func (a1 *App) EqualsWithAnnotationSubset(a2 *App) bool {
 	eq = eq && a1.ID == a2.ID
 	eq = eq && a1.Name == a2.Name
 	eq = eq && a1.Config.Equals(a2.Config)
+	eq = eq && a1.SecretConfig.Equals(a2.SecretConfig)
 	eq = eq && a1.SyslogURL == a2.SyslogURL
 	eq = eq && a1.Annotations.Subset(a2.Annotations)
 	eq = eq && a1.Shape == a2.Shape
@@ -198,7 +211,7 @@ func (a1 *App) EqualsWithAnnotationSubset(a2 *App) bool {
 	return eq
 }
 
-// Update adds entries from patch to a.Config and a.Annotations, and removes entries with empty values.
+// Update adds entries from patch to a.Config, a.SecretConfig and a.Annotations, and removes entries with empty values.
 func (a *App) Update(patch *App) {
 	original := a.Clone()
 
@@ -215,6 +228,19 @@ func (a *App) Update(patch *App) {
 		}
 	}
 
+	if patch.SecretConfig != nil {
+		if a.SecretConfig == nil {
+			a.SecretConfig = make(Config)
+		}
+		for k, v := range patch.SecretConfig {
+			if v == "" {
+				delete(a.SecretConfig, k)
+			} else {
+				a.SecretConfig[k] = v
+			}
+		}
+	}
+
 	if patch.SyslogURL != nil {
 		if *patch.SyslogURL == "" {
 			a.SyslogURL = nil // hides it from jason
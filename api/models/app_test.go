@@ -43,6 +43,7 @@ func appFieldGenerators(t *testing.T) map[string]gopter.Gen {
 	fieldGens["ID"] = gen.AlphaString()
 	fieldGens["Name"] = gen.AlphaString()
 	fieldGens["Config"] = configGenerator()
+	fieldGens["SecretConfig"] = configGenerator()
 	fieldGens["Annotations"] = annotationGenerator()
 	fieldGens["Shape"] = gen.Const("")
 	fieldGens["SyslogURL"] = gen.AlphaString().Map(func(s string) *string {
@@ -78,10 +79,30 @@ func novelValue(t *testing.T, originalInstance reflect.Value, fieldName string,
 			if !newValue.(Annotations).Equals(currentValue.(Annotations)) {
 				break
 			}
-		} else if fieldName == "Config" {
+		} else if fieldName == "Config" || fieldName == "SecretConfig" {
 			if !newValue.(Config).Equals(currentValue.(Config)) {
 				break
 			}
+		} else if fieldName == "Methods" {
+			if !newValue.(HTTPMethods).Equals(currentValue.(HTTPMethods)) {
+				break
+			}
+		} else if fieldName == "MethodFns" {
+			if !newValue.(MethodFns).Equals(currentValue.(MethodFns)) {
+				break
+			}
+		} else if fieldName == "CORS" {
+			if !newValue.(*TriggerCORS).Equals(currentValue.(*TriggerCORS)) {
+				break
+			}
+		} else if fieldName == "Signature" {
+			if !newValue.(*TriggerSignature).Equals(currentValue.(*TriggerSignature)) {
+				break
+			}
+		} else if fieldName == "Traffic" {
+			if !newValue.(*FnTraffic).Equals(currentValue.(*FnTraffic)) {
+				break
+			}
 		} else {
 			if newValue != currentValue {
 				break
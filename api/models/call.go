@@ -17,7 +17,7 @@ const (
 	TypeDetached = "detached"
 )
 
-var possibleStatuses = [...]string{"delayed", "queued", "running", "success", "error", "cancelled"}
+var possibleStatuses = [...]string{"delayed", "queued", "running", "success", "error", "timeout", "cancelled"}
 
 // Call is a representation of a specific invocation of a fn.
 type Call struct {
@@ -102,6 +102,10 @@ type Call struct {
 	// Config is the set of configuration variables for the call
 	Config Config `json:"config,omitempty" db:"-"`
 
+	// SecretFiles are the secret config entries of the call that are mounted
+	// in its container as files, rather than set in its environment
+	SecretFiles Config `json:"secret_files,omitempty" db:"-"`
+
 	// Annotations is the set of annotations for the app/fn of the call.
 	Annotations Annotations `json:"annotations,omitempty" db:"-"`
 
@@ -147,10 +151,21 @@ type CallFilter struct {
 	FnID     string //match
 	FromTime common.DateTime
 	ToTime   common.DateTime
+	Status   string //match
 	Cursor   string
 	PerPage  int
 }
 
+// ValidCallStatus checks whether status is one of the states a call may be in.
+func ValidCallStatus(status string) bool {
+	for _, s := range possibleStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
 type CallList struct {
 	NextCursor string  `json:"next_cursor,omitempty"`
 	Items      []*Call `json:"items"`
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"context"
+)
+
+// CallStore records the history of calls made against fns, so that they can be
+// inspected after the fact via the API.
+type CallStore interface {
+	// InsertCall stores the state of a call, replacing any previous record
+	// with the same ID. Returns ErrDatastoreEmptyCallID when call.ID is empty.
+	InsertCall(ctx context.Context, call *Call) error
+
+	// GetCall gets a call by fn ID and call ID.
+	// Returns ErrDatastoreEmptyFnID or ErrDatastoreEmptyCallID for empty arguments.
+	// Returns ErrCallNotFound if no call is found.
+	GetCall(ctx context.Context, fnID, callID string) (*Call, error)
+
+	// GetCalls returns a list of calls, newest first, and a cursor, applying
+	// the filters provided. Returns ErrDatastoreEmptyFnID if filter.FnID is empty.
+	GetCalls(ctx context.Context, filter *CallFilter) (*CallList, error)
+}
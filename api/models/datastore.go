@@ -48,9 +48,21 @@ type Datastore interface {
 	GetFnByID(ctx context.Context, fnID string) (*Fn, error)
 
 	// RemoveFn removes a function. Returns ErrDatastoreEmptyFnID if fnID is empty.
-	// Returns ErrFnsNotFound if a func is not found.
+	// Returns ErrFnsNotFound if a func is not found, ErrFnsAliased if aliases
+	// point to it and ErrFnsHandlesMethods if triggers of other fns send
+	// methods to it. The splits of traffic to it are removed with it.
 	RemoveFn(ctx context.Context, fnID string) error
 
+	// GetFnRevisions gets a list of the revisions of a fn, newest first.
+	// Returns ErrDatastoreEmptyFnID if no FnID is set in the filter
+	GetFnRevisions(ctx context.Context, filter *FnRevisionFilter) (*FnRevisionList, error)
+
+	// RollbackFn makes a fn run as it did at one of its revisions, the latest if revisionID is empty,
+	// recording a revision of how it ran until then. That revision is the latest, so rolling back
+	// again without a revisionID undoes the rollback rather than going further back.
+	// Returns ErrFnsNotFound if the fn does not exist, and ErrFnRevisionNotFound if the revision does not.
+	RollbackFn(ctx context.Context, fnID, revisionID string) (*Fn, error)
+
 	// InsertTrigger inserts a trigger. Returns ErrDatastoreEmptyTrigger when trigger is nil, and specific errors for each field
 	// Returns ErrTriggerAlreadyExists if the exact apiID, fnID, source, type combination already exists
 	InsertTrigger(ctx context.Context, trigger *Trigger) (*Trigger, error)
@@ -73,6 +85,52 @@ type Datastore interface {
 	// GetTriggerBySource loads a trigger by type and source ID - this is only needed when the data store is also used for agent read access
 	GetTriggerBySource(ctx context.Context, appId string, triggerType, source string) (*Trigger, error)
 
+	// InsertDomain inserts a domain for an app.
+	// Returns ErrAppsNotFound if the app does not exist, and ErrDomainExists if any app has a domain with the same hostname
+	InsertDomain(ctx context.Context, domain *Domain) (*Domain, error)
+
+	// GetDomainByID gets a domain by its id.
+	// Returns ErrDomainNotFound when no matching domain is found
+	GetDomainByID(ctx context.Context, domainID string) (*Domain, error)
+
+	// GetDomainByHostname gets the domain with a hostname, to route requests to it - this is only needed when the data store is also used for agent read access
+	// Returns ErrDomainNotFound when no matching domain is found
+	GetDomainByHostname(ctx context.Context, hostname string) (*Domain, error)
+
+	// GetDomains gets a list of the domains of an app, ordered by hostname.
+	// Return ErrDomainMissingAppID if no AppID set in the filter
+	GetDomains(ctx context.Context, filter *DomainFilter) (*DomainList, error)
+
+	// RemoveDomain removes a domain.
+	// Returns ErrDomainNotFound if the domain is not found.
+	RemoveDomain(ctx context.Context, domainID string) error
+
+	// InsertAlias inserts an alias for an app.
+	// Returns ErrAppsNotFound if the app does not exist, ErrFnsNotFound if the fn does not exist,
+	// ErrAliasFnIDNotSameApp if the fn is of another app, and ErrAliasExists if the app has an alias with the same name
+	InsertAlias(ctx context.Context, alias *Alias) (*Alias, error)
+
+	// UpdateAlias points an alias to another fn of its app, in one step.
+	// Returns ErrAliasNotFound if the alias does not exist, ErrFnsNotFound if the fn does not exist,
+	// and ErrAliasFnIDNotSameApp if the fn is of another app
+	UpdateAlias(ctx context.Context, alias *Alias) (*Alias, error)
+
+	// GetAliasByID gets an alias by its id.
+	// Returns ErrAliasNotFound when no matching alias is found
+	GetAliasByID(ctx context.Context, aliasID string) (*Alias, error)
+
+	// GetAliasByName gets the alias of an app with a name, to resolve calls to it - this is only needed when the data store is also used for agent read access
+	// Returns ErrAliasNotFound when no matching alias is found
+	GetAliasByName(ctx context.Context, appID, name string) (*Alias, error)
+
+	// GetAliases gets a list of the aliases of an app, ordered by name.
+	// Return ErrAliasMissingAppID if no AppID set in the filter
+	GetAliases(ctx context.Context, filter *AliasFilter) (*AliasList, error)
+
+	// RemoveAlias removes an alias.
+	// Returns ErrAliasNotFound if the alias is not found, and ErrAliasInUse if triggers target it.
+	RemoveAlias(ctx context.Context, aliasID string) error
+
 	// implements io.Closer to shutdown
 	io.Closer
 }
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/fnproject/fn/api/common"
+)
+
+// TriggerHTTPDomainEndpointsAnnotation is the annotation that exposes the URLs of an HTTP trigger on the custom domains of its app
+const TriggerHTTPDomainEndpointsAnnotation = "fnproject.io/trigger/httpDomainEndpoints"
+
+// MaxLengthDomainHostname is the longest hostname a domain can have
+const MaxLengthDomainHostname = 253
+
+// Domain is a custom hostname of an app. Requests to the hostname are routed
+// to the http triggers of the app, with the path of the request as their
+// source, so https://api.example.com/orders calls the trigger of the app with
+// the source /orders. A hostname belongs to one app at most.
+type Domain struct {
+	ID        string          `json:"id" db:"id"`
+	Hostname  string          `json:"hostname" db:"hostname"`
+	AppID     string          `json:"app_id" db:"app_id"`
+	CreatedAt common.DateTime `json:"created_at,omitempty" db:"created_at"`
+}
+
+var (
+	//ErrDomainIDProvided indicates that a domain ID was specified when it shouldn't have been
+	ErrDomainIDProvided = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("ID cannot be provided for Domain creation"),
+	}
+	//ErrDomainMissingAppID - no app id specified on domain creation
+	ErrDomainMissingAppID = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Missing App ID on Domain")}
+	//ErrDomainMissingHostname - no hostname specified on domain creation
+	ErrDomainMissingHostname = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Missing hostname on Domain")}
+	//ErrDomainInvalidHostname - hostname is not a fully qualified domain name
+	ErrDomainInvalidHostname = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid hostname for Domain, hostnames must be lower case fully qualified domain names such as api.example.com")}
+	//ErrDomainServerHostname - hostname is one fn itself answers on
+	ErrDomainServerHostname = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid hostname for Domain, the server answers on it itself")}
+	//ErrDomainNotFound - domain not found
+	ErrDomainNotFound = err{
+		code:  http.StatusNotFound,
+		error: errors.New("Domain not found")}
+	//ErrDomainExists - the hostname belongs to a domain already, of this or another app
+	ErrDomainExists = err{
+		code:  http.StatusConflict,
+		error: errors.New("Domain with the same hostname already exists")}
+)
+
+// Validate checks that domain has valid data for inserting into a store
+func (d *Domain) Validate() error {
+	if d.AppID == "" {
+		return ErrDomainMissingAppID
+	}
+	if d.Hostname == "" {
+		return ErrDomainMissingHostname
+	}
+	if !IsDomainHostname(d.Hostname) {
+		return ErrDomainInvalidHostname
+	}
+	return nil
+}
+
+// Clone creates a copy of a domain
+func (d *Domain) Clone() *Domain {
+	clone := new(Domain)
+	*clone = *d
+	return clone
+}
+
+// Equals compares two domains, ignoring timestamp fields
+func (d *Domain) Equals(d2 *Domain) bool {
+	return d.ID == d2.ID && d.Hostname == d2.Hostname && d.AppID == d2.AppID
+}
+
+// NormalizeHostname turns the host of a request into the hostname of a
+// domain, dropping any port and trailing dot, in lower case
+func NormalizeHostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// IsDomainHostname tells if hostname is a lower case fully qualified domain
+// name, which are the only hostnames domains may have. IP addresses and single
+// label names, such as localhost, are not.
+func IsDomainHostname(hostname string) bool {
+	if len(hostname) > MaxLengthDomainHostname || net.ParseIP(hostname) != nil {
+		return false
+	}
+	labels := strings.Split(hostname, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, l := range labels {
+		if l == "" || len(l) > 63 || l[0] == '-' || l[len(l)-1] == '-' {
+			return false
+		}
+		for _, c := range l {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	// top level domains are never all digits, which also rules out things that look like addresses
+	return strings.Trim(labels[len(labels)-1], "0123456789") != ""
+}
+
+// DomainFilter is a search criteria on domains
+type DomainFilter struct {
+	//AppID searches for domains of an app - mandatory
+	AppID string // this is exact match mandatory
+
+	Cursor  string
+	PerPage int
+}
+
+// DomainList is a container of domains returned by search, optionally indicating the next page cursor
+type DomainList struct {
+	NextCursor string    `json:"next_cursor,omitempty"`
+	Items      []*Domain `json:"items"`
+}
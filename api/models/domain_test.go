@@ -0,0 +1,66 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDomainHostname(t *testing.T) {
+	for _, tc := range []struct {
+		hostname string
+		valid    bool
+	}{
+		{"api.example.com", true},
+		{"a-b.example.co.uk", true},
+		{"xn--bcher-kva.example", true},
+		{"1.example.com", true},
+		{"localhost", false},
+		{"10.0.0.1", false},
+		{"::1", false},
+		{"API.example.com", false},
+		{"api..example.com", false},
+		{"-api.example.com", false},
+		{"api-.example.com", false},
+		{"api_v1.example.com", false},
+		{"api.example.com.", false},
+		{"api.example.123", false},
+		{strings.Repeat("a", 64) + ".example.com", false},
+		{strings.Repeat("a.", 127) + "com", false},
+	} {
+		if valid := IsDomainHostname(tc.hostname); valid != tc.valid {
+			t.Errorf("expected %q to be a valid hostname %v, got %v", tc.hostname, tc.valid, valid)
+		}
+	}
+}
+
+func TestNormalizeHostname(t *testing.T) {
+	for host, expected := range map[string]string{
+		"api.example.com":      "api.example.com",
+		"API.Example.com":      "api.example.com",
+		"api.example.com:8080": "api.example.com",
+		"api.example.com.":     "api.example.com",
+		"api.example.com.:443": "api.example.com",
+		"[::1]:8080":           "::1",
+		"127.0.0.1:8080":       "127.0.0.1",
+	} {
+		if got := NormalizeHostname(host); got != expected {
+			t.Errorf("expected %q to normalize to %q, got %q", host, expected, got)
+		}
+	}
+}
+
+func TestDomainValidate(t *testing.T) {
+	for _, tc := range []struct {
+		domain   *Domain
+		expected error
+	}{
+		{&Domain{AppID: "app", Hostname: "api.example.com"}, nil},
+		{&Domain{Hostname: "api.example.com"}, ErrDomainMissingAppID},
+		{&Domain{AppID: "app"}, ErrDomainMissingHostname},
+		{&Domain{AppID: "app", Hostname: "localhost"}, ErrDomainInvalidHostname},
+	} {
+		if err := tc.domain.Validate(); err != tc.expected {
+			t.Errorf("expected %v for %#v, got %v", tc.expected, tc.domain, err)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 const (
@@ -112,10 +113,34 @@ var (
 		error: fmt.Errorf("Cpus is invalid. Value should be either between [%.3f and %.3f] or [%dm and %dm] milliCPU units",
 			float64(MinMilliCPUs)/1000.0, float64(MaxMilliCPUs)/1000.0, MinMilliCPUs, MaxMilliCPUs),
 	}
+	ErrCallCancelled = err{
+		code:  http.StatusConflict,
+		error: errors.New("Call was cancelled"),
+	}
 	ErrCallLogNotFound = err{
 		code:  http.StatusNotFound,
 		error: errors.New("Call log not found"),
 	}
+	ErrCallLogsNotRecorded = err{
+		code:  http.StatusNotImplemented,
+		error: errors.New("Call logs are not recorded for calls made through load balancers, set FN_LOG_STORE_URL to serve the logs full nodes record"),
+	}
+	ErrInvalidCallStatus = err{
+		code:  http.StatusBadRequest,
+		error: fmt.Errorf("status is invalid. Valid values are %s", strings.Join(possibleStatuses[:], ", ")),
+	}
+	ErrInvalidInvokeDelay = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Fn-Invoke-Delay is invalid. It should be a whole number of seconds, and is only allowed on detached calls"),
+	}
+	ErrInvokeDelayNotSupported = err{
+		code:  http.StatusNotImplemented,
+		error: errors.New("Delayed calls are not supported without a queue to hold them"),
+	}
+	ErrInvalidWebSocketUpgrade = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid websocket upgrade request, only version 13 of the websocket protocol is supported"),
+	}
 	ErrPathNotFound = err{
 		code:  http.StatusNotFound,
 		error: errors.New("Path not found"),
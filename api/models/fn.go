@@ -1,10 +1,12 @@
 package models
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/fnproject/fn/api/common"
@@ -66,6 +68,34 @@ var (
 		code:  http.StatusBadRequest,
 		error: fmt.Errorf("idle_timeout value is out of range, must be between 0 and %d", MaxIdleTimeout),
 	}
+	ErrFnsInvalidTmpFsSize = err{
+		code:  http.StatusBadRequest,
+		error: fmt.Errorf("tmpfs_size value is out of range, must be between 0 and %d", MaxMemory),
+	}
+	ErrFnsInvalidDeadLetter = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("dead_letter must be a fn id or an http(s) url"),
+	}
+	ErrFnsInvalidOnSuccess = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("on_success must be a fn id or an http(s) url"),
+	}
+	ErrFnsInvalidOnFailure = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("on_failure must be a fn id or an http(s) url"),
+	}
+	ErrFnsDestinationSelf = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("dead_letter, on_success and on_failure can't be the fn itself"),
+	}
+	ErrFnsDestinationNotSameApp = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("dead_letter, on_success and on_failure fns must be fns of the same app"),
+	}
+	ErrFnsDestinationsNotSupported = err{
+		code:  http.StatusNotImplemented,
+		error: errors.New("dead_letter, on_success and on_failure are not supported without a queue for detached calls"),
+	}
 	ErrFnsNotFound = err{
 		code:  http.StatusNotFound,
 		error: errors.New("Fn not found"),
@@ -74,11 +104,22 @@ var (
 		code:  http.StatusConflict,
 		error: errors.New("Fn with specified name already exists"),
 	}
+	ErrFnsHandlesMethods = err{
+		code:  http.StatusConflict,
+		error: errors.New("Fn handles methods of triggers, remove it from their method_fns before deleting it"),
+	}
+	ErrFnsAliased = err{
+		code:  http.StatusConflict,
+		error: errors.New("Fn is the target of aliases, which must be removed or pointed elsewhere first"),
+	}
 )
 
 // FnInvokeEndpointAnnotation is the annotation that exposes the fn invoke endpoint For want of a better place to put this it's here
 const FnInvokeEndpointAnnotation = "fnproject.io/fn/invokeEndpoint"
 
+// FnStreamResponseAnnotation is the annotation that, set to true, streams the responses of the fn to clients as they are written
+const FnStreamResponseAnnotation = "fnproject.io/fn/streamResponse"
+
 // Fn contains information about a function configuration.
 type Fn struct {
 	// ID is the generated resource id.
@@ -94,14 +135,62 @@ type Fn struct {
 	ResourceConfig // embed (TODO or not?)
 	// Config is the configuration passed to a function at execution time.
 	Config Config `json:"config" db:"config"`
+	// SecretConfig is configuration passed to a function like Config, that
+	// is stored encrypted and redacted in the API. Like Config it is recorded,
+	// still encrypted, in revisions and restored by rollbacks.
+	SecretConfig Config `json:"secret_config,omitempty" db:"secret_config"`
 	// Annotations allow additional configuration of a function, these are not passed to the function.
 	Annotations Annotations `json:"annotations,omitempty" db:"annotations"`
 	// CreatedAt is the UTC timestamp when this function was created.
 	CreatedAt common.DateTime `json:"created_at,omitempty" db:"created_at"`
 	// Shape of the function image
 	Shape string `json:"shape,omitempty" db:"shape"`
+	// DeadLetter is where the requests of detached calls that finally failed
+	// are sent, either the id of another fn or the url of a webhook.
+	DeadLetter string `json:"dead_letter,omitempty" db:"dead_letter"`
+	// OnSuccess is where the responses of detached calls that succeeded are
+	// sent, either the id of another fn or the url of a webhook.
+	OnSuccess string `json:"on_success,omitempty" db:"on_success"`
+	// OnFailure is where the errors of detached calls that finally failed are
+	// sent, either the id of another fn or the url of a webhook.
+	OnFailure string `json:"on_failure,omitempty" db:"on_failure"`
+	// Traffic splits the calls to the fn between it and other fns of its
+	// app by weight, all calls are to the fn if nil.
+	Traffic *FnTraffic `json:"traffic,omitempty" db:"traffic"`
 	// UpdatedAt is the UTC timestamp of the last time this func was modified.
 	UpdatedAt common.DateTime `json:"updated_at,omitempty" db:"updated_at"`
+
+	// nulls are the clearable fields set to null in the json f was decoded
+	// from, which Update clears when f is a patch
+	nulls map[string]bool
+}
+
+// clearableFnFields are the fields whose zero values leave them as they are
+// in an update, so that only null clears them
+var clearableFnFields = []string{"tmpfs_size", "cpus", "dead_letter", "on_success", "on_failure"}
+
+// fnJSON decodes like Fn, without the UnmarshalJSON of Fn
+type fnJSON Fn
+
+// UnmarshalJSON decodes f, recording which of its clearable fields are null
+func (f *Fn) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*fnJSON)(f)); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	f.nulls = nil
+	for _, name := range clearableFnFields {
+		if v, ok := fields[name]; ok && string(v) == "null" {
+			if f.nulls == nil {
+				f.nulls = make(map[string]bool)
+			}
+			f.nulls[name] = true
+		}
+	}
+	return nil
 }
 
 // ResourceConfig specified resource constraints imposed on a function execution.
@@ -114,6 +203,13 @@ type ResourceConfig struct {
 	// IdleTimeout is the
 	// TODO this should probably be milliseconds
 	IdleTimeout int32 `json:"idle_timeout,omitempty" db:"idle_timeout"`
+	// TmpFsSize is the size of the writable /tmp given to each container, in MB.
+	// It is backed by memory, so it is accounted for as such. 0 is the default
+	// tmpfs behaviour of the agent.
+	TmpFsSize uint32 `json:"tmpfs_size,omitempty" db:"tmpfs_size"`
+	// CPUs is the amount of CPU reserved for, and the cap imposed on, each
+	// call, in MilliCPUs. 0 is unlimited.
+	CPUs MilliCPUs `json:"cpus,omitempty" db:"cpus"`
 }
 
 // SetCreated sets zeroed field to defaults.
@@ -172,9 +268,79 @@ func (f *Fn) Validate() error {
 		return ErrInvalidMemory
 	}
 
+	if uint64(f.TmpFsSize) > MaxMemory {
+		return ErrFnsInvalidTmpFsSize
+	}
+
+	if f.CPUs > MaxMilliCPUs {
+		return ErrInvalidCPUs
+	}
+
+	if f.DeadLetter != "" && !ValidDestination(f.DeadLetter) {
+		return ErrFnsInvalidDeadLetter
+	}
+
+	if f.OnSuccess != "" && !ValidDestination(f.OnSuccess) {
+		return ErrFnsInvalidOnSuccess
+	}
+
+	if f.OnFailure != "" && !ValidDestination(f.OnFailure) {
+		return ErrFnsInvalidOnFailure
+	}
+
+	for _, fnID := range f.DestinationFnIDs() {
+		if fnID == f.ID {
+			return ErrFnsDestinationSelf
+		}
+	}
+
+	if err := f.validateTraffic(); err != nil {
+		return err
+	}
+
+	if _, err := SecretFiles(f.Annotations); err != nil {
+		return err
+	}
+
 	return f.Annotations.Validate()
 }
 
+// HasDestinations tells if the results of detached calls of f are sent
+// anywhere
+func (f *Fn) HasDestinations() bool {
+	return f.DeadLetter != "" || f.OnSuccess != "" || f.OnFailure != ""
+}
+
+// DestinationFnIDs returns the ids of the fns the results of detached calls
+// of f are sent to, leaving out webhooks
+func (f *Fn) DestinationFnIDs() []string {
+	var fnIDs []string
+	for _, dest := range []string{f.DeadLetter, f.OnSuccess, f.OnFailure} {
+		if _, ok := DestinationURL(dest); !ok && dest != "" {
+			fnIDs = append(fnIDs, dest)
+		}
+	}
+	return fnIDs
+}
+
+// ValidDestination tells if dest names somewhere the results of calls may be
+// sent, i.e. a fn id or an http(s) url
+func ValidDestination(dest string) bool {
+	if u, ok := DestinationURL(dest); ok {
+		return u.Host != "" && (u.Scheme == "http" || u.Scheme == "https")
+	}
+	return dest != "" && url.PathEscape(dest) == dest && !strings.Contains(dest, ":")
+}
+
+// DestinationURL returns the url of dest if it is a webhook rather than a fn id
+func DestinationURL(dest string) (*url.URL, bool) {
+	u, err := url.Parse(dest)
+	if err != nil || u.Scheme == "" {
+		return nil, false
+	}
+	return u, true
+}
+
 func (f *Fn) ValidateName() error {
 	if f.Name == "" {
 		return ErrFnsMissingName
@@ -202,6 +368,12 @@ func (f *Fn) Clone() *Fn {
 			clone.Config[k] = v
 		}
 	}
+	if f.SecretConfig != nil {
+		clone.SecretConfig = make(Config, len(f.SecretConfig))
+		for k, v := range f.SecretConfig {
+			clone.SecretConfig[k] = v
+		}
+	}
 	if f.Annotations != nil {
 		clone.Annotations = make(Annotations, len(f.Annotations))
 		for k, v := range f.Annotations {
@@ -209,6 +381,7 @@ func (f *Fn) Clone() *Fn {
 			clone.Annotations[k] = v
 		}
 	}
+	clone.Traffic = f.Traffic.clone()
 	return clone
 }
 
@@ -224,7 +397,14 @@ func (f1 *Fn) Equals(f2 *Fn) bool {
 	eq = eq && f1.Memory == f2.Memory
 	eq = eq && f1.Timeout == f2.Timeout
 	eq = eq && f1.IdleTimeout == f2.IdleTimeout
+	eq = eq && f1.TmpFsSize == f2.TmpFsSize
+	eq = eq && f1.CPUs == f2.CPUs
 	eq = eq && f1.Config.Equals(f2.Config)
+	eq = eq && f1.SecretConfig.Equals(f2.SecretConfig)
+	eq = eq && f1.DeadLetter == f2.DeadLetter
+	eq = eq && f1.OnSuccess == f2.OnSuccess
+	eq = eq && f1.OnFailure == f2.OnFailure
+	eq = eq && f1.Traffic.Equals(f2.Traffic)
 	eq = eq && f1.Annotations.Equals(f2.Annotations)
 	eq = eq && f1.Shape == f2.Shape
 	// NOTE: datastore tests are not very fun to write with timestamp checks,
@@ -246,7 +426,14 @@ func (f1 *Fn) EqualsWithAnnotationSubset(f2 *Fn) bool {
 	eq = eq && f1.Memory == f2.Memory
 	eq = eq && f1.Timeout == f2.Timeout
 	eq = eq && f1.IdleTimeout == f2.IdleTimeout
+	eq = eq && f1.TmpFsSize == f2.TmpFsSize
+	eq = eq && f1.CPUs == f2.CPUs
 	eq = eq && f1.Config.Equals(f2.Config)
+	eq = eq && f1.SecretConfig.Equals(f2.SecretConfig)
+	eq = eq && f1.DeadLetter == f2.DeadLetter
+	eq = eq && f1.OnSuccess == f2.OnSuccess
+	eq = eq && f1.OnFailure == f2.OnFailure
+	eq = eq && f1.Traffic.Equals(f2.Traffic)
 	eq = eq && f1.Annotations.Subset(f2.Annotations)
 	// NOTE: datastore tests are not very fun to write with timestamp checks,
 	// and these are not values the user may set so we kind of don't care.
@@ -257,7 +444,9 @@ func (f1 *Fn) EqualsWithAnnotationSubset(f2 *Fn) bool {
 
 // Update updates fields in f with non-zero field values from new, and sets
 // updated_at if any of the fields change. 0-length slice Header values, and
-// empty-string Config values trigger removal of map entry.
+// empty-string Config and SecretConfig values trigger removal of map entry.
+// tmpfs_size, cpus and the destinations are cleared by null in the json of a
+// patch.
 func (f *Fn) Update(patch *Fn) {
 	original := f.Clone()
 
@@ -274,6 +463,29 @@ func (f *Fn) Update(patch *Fn) {
 	if patch.IdleTimeout != 0 {
 		f.IdleTimeout = patch.IdleTimeout
 	}
+	// these are optional, so unlike the fields above null clears them
+	if patch.TmpFsSize != 0 || patch.nulls["tmpfs_size"] {
+		f.TmpFsSize = patch.TmpFsSize
+	}
+	if patch.CPUs != 0 || patch.nulls["cpus"] {
+		f.CPUs = patch.CPUs
+	}
+	if patch.DeadLetter != "" || patch.nulls["dead_letter"] {
+		f.DeadLetter = patch.DeadLetter
+	}
+	if patch.OnSuccess != "" || patch.nulls["on_success"] {
+		f.OnSuccess = patch.OnSuccess
+	}
+	if patch.OnFailure != "" || patch.nulls["on_failure"] {
+		f.OnFailure = patch.OnFailure
+	}
+	// the traffic split is replaced as a whole, set it empty to remove it
+	if patch.Traffic != nil {
+		f.Traffic = patch.Traffic.clone()
+		if f.Traffic.empty() {
+			f.Traffic = nil
+		}
+	}
 	if patch.Config != nil {
 		if f.Config == nil {
 			f.Config = make(Config)
@@ -286,6 +498,18 @@ func (f *Fn) Update(patch *Fn) {
 			}
 		}
 	}
+	if patch.SecretConfig != nil {
+		if f.SecretConfig == nil {
+			f.SecretConfig = make(Config)
+		}
+		for k, v := range patch.SecretConfig {
+			if v == "" {
+				delete(f.SecretConfig, k)
+			} else {
+				f.SecretConfig[k] = v
+			}
+		}
+	}
 
 	f.Annotations = f.Annotations.MergeChange(patch.Annotations)
 
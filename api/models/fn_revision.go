@@ -0,0 +1,117 @@
+package models
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/fnproject/fn/api/common"
+)
+
+// FnRevision is an immutable record of how a fn ran before an update changed
+// it: its image, resources, config, sealed secret config and annotations. A
+// revision is recorded by every update that changes any of these, so a fn can
+// be rolled back to any of its earlier revisions that are still kept.
+type FnRevision struct {
+	// ID is the generated resource id, revisions of a fn are ordered by it.
+	ID string `json:"id" db:"id"`
+	// FnID is the id of the fn this is a revision of.
+	FnID string `json:"fn_id" db:"fn_id"`
+	// AppID is the id of the app of the fn.
+	AppID string `json:"app_id" db:"app_id"`
+	// Image is the image the fn ran.
+	Image string `json:"image" db:"image"`
+	// ResourceConfig are the resource constraints the fn ran with.
+	ResourceConfig
+	// Config is the configuration the fn was passed.
+	Config Config `json:"config" db:"config"`
+	// SecretConfig is the secret configuration the fn was passed, as stored,
+	// i.e. sealed.
+	SecretConfig Config `json:"secret_config,omitempty" db:"secret_config"`
+	// Annotations are the annotations the fn had.
+	Annotations Annotations `json:"annotations,omitempty" db:"annotations"`
+	// CreatedAt is the UTC timestamp when the revision was replaced, i.e. when it was recorded.
+	CreatedAt common.DateTime `json:"created_at,omitempty" db:"created_at"`
+}
+
+// MaxFnRevisions is how many revisions are kept of a fn, recording a revision
+// past that removes the oldest
+const MaxFnRevisions = 100
+
+var (
+	//ErrFnRevisionNotFound - the revision to roll back to is not a revision of the fn, or the fn has none
+	ErrFnRevisionNotFound = err{
+		code:  http.StatusNotFound,
+		error: errors.New("Fn revision not found")}
+)
+
+// NewFnRevision records how f runs now
+func NewFnRevision(f *Fn) *FnRevision {
+	f = f.Clone()
+	return &FnRevision{
+		FnID:           f.ID,
+		AppID:          f.AppID,
+		Image:          f.Image,
+		ResourceConfig: f.ResourceConfig,
+		Config:         f.Config,
+		SecretConfig:   f.SecretConfig,
+		Annotations:    f.Annotations,
+		CreatedAt:      common.DateTime(time.Now()),
+	}
+}
+
+// RevisionEquals tells if f1 and f2 run the same, i.e. an update from one to
+// the other would record no revision
+func (f1 *Fn) RevisionEquals(f2 *Fn) bool {
+	eq := true
+	eq = eq && f1.Image == f2.Image
+	eq = eq && f1.ResourceConfig == f2.ResourceConfig
+	eq = eq && f1.Config.Equals(f2.Config)
+	eq = eq && f1.SecretConfig.Equals(f2.SecretConfig)
+	eq = eq && f1.Annotations.Equals(f2.Annotations)
+	return eq
+}
+
+// Rollback makes f run as it did at revision r, replacing its image,
+// resources, config, secret config and annotations, and sets updated_at if
+// that changes f
+func (f *Fn) Rollback(r *FnRevision) {
+	original := f.Clone()
+
+	f.Image = r.Image
+	f.ResourceConfig = r.ResourceConfig
+	f.Config = make(Config, len(r.Config))
+	for k, v := range r.Config {
+		f.Config[k] = v
+	}
+	f.SecretConfig = nil
+	if r.SecretConfig != nil {
+		f.SecretConfig = make(Config, len(r.SecretConfig))
+		for k, v := range r.SecretConfig {
+			f.SecretConfig[k] = v
+		}
+	}
+	f.Annotations = make(Annotations, len(r.Annotations))
+	for k, v := range r.Annotations {
+		f.Annotations[k] = v
+	}
+
+	if !f.Equals(original) {
+		f.UpdatedAt = common.DateTime(time.Now())
+	}
+}
+
+// FnRevisionFilter is a search criteria on the revisions of a fn
+type FnRevisionFilter struct {
+	//FnID searches for the revisions of a fn - mandatory
+	FnID string
+
+	Cursor  string
+	PerPage int
+}
+
+// FnRevisionList is a container of revisions returned by search, newest first, optionally indicating the next page cursor
+type FnRevisionList struct {
+	NextCursor string        `json:"next_cursor,omitempty"`
+	Items      []*FnRevision `json:"items"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fnproject/fn/api/common"
+)
+
+func TestFnRevisionEquals(t *testing.T) {
+	fn := &Fn{ID: "fn_id", Name: "f", AppID: "app_id", Image: "img:1", Config: Config{"k": "v"}}
+	fn.SetDefaults()
+
+	for i, tc := range []struct {
+		patch    *Fn
+		expected bool
+	}{
+		{&Fn{Image: "img:2"}, false},
+		{&Fn{Config: Config{"k": "other"}}, false},
+		{&Fn{SecretConfig: Config{"password": "sealed"}}, false},
+		{&Fn{ResourceConfig: ResourceConfig{Memory: 256}}, false},
+		{&Fn{ResourceConfig: ResourceConfig{CPUs: 500}}, false},
+		{&Fn{Annotations: Annotations{"a": &annotationValue{'1'}}}, false},
+		{&Fn{DeadLetter: "https://example.com/dead-letters"}, true},
+		{&Fn{OnSuccess: "https://example.com/results"}, true},
+		{&Fn{Image: "img:1"}, true},
+	} {
+		updated := fn.Clone()
+		updated.Update(tc.patch)
+		if got := fn.RevisionEquals(updated); got != tc.expected {
+			t.Errorf("Test %d: expected %v, got %v", i, tc.expected, got)
+		}
+	}
+}
+
+func TestFnRollback(t *testing.T) {
+	fn := &Fn{ID: "fn_id", Name: "f", AppID: "app_id", Image: "img:1", Config: Config{"k": "v", "old": "x"},
+		SecretConfig: Config{"password": "sealed"}, DeadLetter: "https://example.com/dead-letters"}
+	fn.SetDefaults()
+	revision := NewFnRevision(fn)
+
+	updated := fn.Clone()
+	updated.Update(&Fn{Image: "img:2", Config: Config{"old": "", "new": "y"}, SecretConfig: Config{"password": "resealed"}, ResourceConfig: ResourceConfig{Memory: 512}})
+	updated.UpdatedAt = common.DateTime(time.Time{})
+
+	updated.Rollback(revision)
+	if !updated.Equals(fn) {
+		t.Errorf("expected fn to run as it did at the revision, got %#v", updated)
+	}
+	if time.Time(updated.UpdatedAt).IsZero() {
+		t.Errorf("expected updated_at to be set by a rollback that changes the fn")
+	}
+
+	// the revision is not changed by changes to the fn it was rolled back to
+	updated.Config["k"] = "changed"
+	if revision.Config["k"] != "v" {
+		t.Errorf("expected the revision to be immutable")
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -20,6 +21,8 @@ func resourceConfigGenerator(t *testing.T) gopter.Gen {
 	fieldGens["Memory"] = gen.UInt64()
 	fieldGens["Timeout"] = gen.Int32()
 	fieldGens["IdleTimeout"] = gen.Int32()
+	fieldGens["TmpFsSize"] = gen.UInt32()
+	fieldGens["CPUs"] = gen.UInt64Range(MinMilliCPUs, MaxMilliCPUs).Map(func(c uint64) MilliCPUs { return MilliCPUs(c) })
 
 	resourceConfig := ResourceConfig{}
 	resourceConfigFieldCount := reflect.TypeOf(resourceConfig).NumField()
@@ -39,13 +42,24 @@ func fnFieldGenerators(t *testing.T) map[string]gopter.Gen {
 	fieldGens["AppID"] = gen.AlphaString()
 	fieldGens["Image"] = gen.AlphaString()
 	fieldGens["Config"] = configGenerator()
+	fieldGens["SecretConfig"] = configGenerator()
 	fieldGens["ResourceConfig"] = resourceConfigGenerator(t)
 	fieldGens["Annotations"] = annotationGenerator()
 	fieldGens["CreatedAt"] = datetimeGenerator()
 	fieldGens["UpdatedAt"] = datetimeGenerator()
 	fieldGens["Shape"] = gen.OneConstOf("GENERIC_X86", "GENERIC_ARM", "GENERIC_X86_ARM")
-
-	fnFieldCount := fnReflectType().NumField()
+	fieldGens["DeadLetter"] = gen.OneConstOf("", "dead_letter_fn_id", "https://example.com/dead-letters")
+	fieldGens["OnSuccess"] = gen.OneConstOf("", "on_success_fn_id", "https://example.com/results")
+	fieldGens["OnFailure"] = gen.OneConstOf("", "on_failure_fn_id", "https://example.com/errors")
+	fieldGens["Traffic"] = gen.OneConstOf(&FnTraffic{Splits: []TrafficSplit{{FnID: "canary_fn_id", Weight: 5}}}, &FnTraffic{Splits: []TrafficSplit{{FnID: "canary_fn_id", Weight: 50}}, StickyHeader: "X-User-Id"}, (*FnTraffic)(nil))
+
+	// unexported fields are not part of fns, just how they were decoded
+	fnFieldCount := 0
+	for i := 0; i < fnReflectType().NumField(); i++ {
+		if fnReflectType().Field(i).PkgPath == "" {
+			fnFieldCount++
+		}
+	}
 
 	if fnFieldCount != len(fieldGens) {
 		t.Fatalf("Fn struct field count, %d, does not match fn generator field count, %d", fnFieldCount, len(fieldGens))
@@ -108,6 +122,45 @@ func TestFnEquality(t *testing.T) {
 	properties.TestingRun(t)
 }
 
+func TestFnUpdateClearsNulls(t *testing.T) {
+	for _, field := range []string{"tmpfs_size", "cpus", "dead_letter", "on_success", "on_failure"} {
+		fn := &Fn{
+			ResourceConfig: ResourceConfig{TmpFsSize: 64, CPUs: 500},
+			DeadLetter:     "https://example.com/dead-letters",
+			OnSuccess:      "https://example.com/results",
+			OnFailure:      "https://example.com/errors",
+		}
+
+		var patch Fn
+		if err := json.Unmarshal([]byte(`{"`+field+`": null}`), &patch); err != nil {
+			t.Fatal(err)
+		}
+		fn.Update(&patch)
+
+		var got map[string]interface{}
+		b, _ := json.Marshal(fn)
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatal(err)
+		}
+		for _, other := range clearableFnFields {
+			if _, ok := got[other]; ok == (other == field) {
+				t.Errorf("expected only %s to be cleared by null, got %v", field, got)
+			}
+		}
+	}
+
+	// fields left out of a patch, or zero, are left as they are
+	fn := &Fn{ResourceConfig: ResourceConfig{TmpFsSize: 64, CPUs: 500}, DeadLetter: "https://example.com/dead-letters"}
+	var patch Fn
+	if err := json.Unmarshal([]byte(`{"tmpfs_size": 0, "cpus": "", "dead_letter": ""}`), &patch); err != nil {
+		t.Fatal(err)
+	}
+	fn.Update(&patch)
+	if fn.TmpFsSize != 64 || fn.CPUs != 500 || fn.DeadLetter != "https://example.com/dead-letters" {
+		t.Errorf("expected zero values to leave fields as they are, got %+v", fn)
+	}
+}
+
 func TestValidateFnName(t *testing.T) {
 	tooLongName := "7"
 	for i := 0; i < MaxLengthFnName+1; i++ {
@@ -174,6 +227,43 @@ func TestValidateFn(t *testing.T) {
 	testFn.Memory = 0
 	testCases = append(testCases, test{testFn, ErrInvalidMemory})
 
+	testFn = generateValidFn()
+	testFn.TmpFsSize = uint32(MaxMemory) + 1
+	testCases = append(testCases, test{testFn, ErrFnsInvalidTmpFsSize})
+
+	testFn = generateValidFn()
+	testFn.CPUs = MaxMilliCPUs + 1
+	testCases = append(testCases, test{testFn, ErrInvalidCPUs})
+
+	testFn = generateValidFn()
+	testFn.DeadLetter = "01CQV4NEYR0000000000000000"
+	testCases = append(testCases, test{testFn, nil})
+
+	testFn = generateValidFn()
+	testFn.DeadLetter = "https://example.com/dead-letters"
+	testCases = append(testCases, test{testFn, nil})
+
+	testFn = generateValidFn()
+	testFn.DeadLetter = "ftp://example.com/dead-letters"
+	testCases = append(testCases, test{testFn, ErrFnsInvalidDeadLetter})
+
+	testFn = generateValidFn()
+	testFn.DeadLetter = "not/a/fn"
+	testCases = append(testCases, test{testFn, ErrFnsInvalidDeadLetter})
+
+	testFn = generateValidFn()
+	testFn.OnSuccess = "mailto:results@example.com"
+	testCases = append(testCases, test{testFn, ErrFnsInvalidOnSuccess})
+
+	testFn = generateValidFn()
+	testFn.OnFailure = "http://"
+	testCases = append(testCases, test{testFn, ErrFnsInvalidOnFailure})
+
+	testFn = generateValidFn()
+	testFn.ID = "01CQV4NEYR0000000000000000"
+	testFn.OnSuccess = testFn.ID
+	testCases = append(testCases, test{testFn, ErrFnsDestinationSelf})
+
 	for _, testCase := range testCases {
 		got := testCase.Fn.Validate()
 
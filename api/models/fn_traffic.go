@@ -0,0 +1,163 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// FnTraffic splits the calls to a fn between it and other fns of its app,
+// such as a canary running a candidate image, by weight. Each call is sent to
+// one of the fns it splits to, picked at random, or by a hash of a header of
+// the request if the split is sticky, so that a client keeps being served by
+// the same fn.
+type FnTraffic struct {
+	// Splits are the fns that get some of the calls, and the percentage of the calls each gets. The fn gets the rest.
+	Splits []TrafficSplit `json:"splits"`
+	// StickyHeader is the header, such as a session or user id, calls with the same value of which go to the same fn
+	StickyHeader string `json:"sticky_header,omitempty"`
+}
+
+// TrafficSplit is a fn that gets a share of the calls to another
+type TrafficSplit struct {
+	// FnID is the id of the fn, it must be in the same app as the fn whose calls it gets
+	FnID string `json:"fn_id"`
+	// Weight is the percentage of the calls the fn gets, from 1 to 100
+	Weight int `json:"weight"`
+}
+
+var (
+	//ErrFnsTrafficMissingSplits - the traffic of a fn is split to no fns
+	ErrFnsTrafficMissingSplits = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Missing splits in the traffic of Fn")}
+	//ErrFnsTrafficInvalidSplit - a split of the traffic of a fn has no fn id, names the fn itself or another fn twice
+	ErrFnsTrafficInvalidSplit = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid split in the traffic of Fn, each must name a different fn of the app than the fn itself")}
+	//ErrFnsTrafficInvalidWeight - the weights of the splits of the traffic of a fn are out of range
+	ErrFnsTrafficInvalidWeight = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid weight in the traffic of Fn, weights must be between 1 and 100 and add up to 100 at most")}
+	//ErrFnsTrafficInvalidStickyHeader - the sticky header of the traffic of a fn is not a header name
+	ErrFnsTrafficInvalidStickyHeader = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid sticky header in the traffic of Fn")}
+	//ErrFnsTrafficFnNotSameApp - the traffic of a fn is split to a fn of another app
+	ErrFnsTrafficFnNotSameApp = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid Fn ID in the traffic of Fn - not owned by the same app")}
+)
+
+// Equals checks that two traffic splits are the same, either may be nil
+func (t *FnTraffic) Equals(other *FnTraffic) bool {
+	if t == nil || other == nil {
+		return t == other
+	}
+	if t.StickyHeader != other.StickyHeader || len(t.Splits) != len(other.Splits) {
+		return false
+	}
+	for i := range t.Splits {
+		if t.Splits[i] != other.Splits[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *FnTraffic) clone() *FnTraffic {
+	if t == nil {
+		return nil
+	}
+	return &FnTraffic{
+		Splits:       append([]TrafficSplit(nil), t.Splits...),
+		StickyHeader: t.StickyHeader,
+	}
+}
+
+func (t *FnTraffic) empty() bool {
+	return len(t.Splits) == 0 && t.StickyHeader == ""
+}
+
+// FnIDs are the ids of the fns the traffic is split to
+func (t *FnTraffic) FnIDs() []string {
+	ids := make([]string, 0, len(t.Splits))
+	for _, s := range t.Splits {
+		ids = append(ids, s.FnID)
+	}
+	return ids
+}
+
+// Without returns the traffic without its split to fnID, nil if no splits are
+// left for the fn the traffic is of to share its calls with
+func (t *FnTraffic) Without(fnID string) *FnTraffic {
+	if t == nil {
+		return nil
+	}
+	without := &FnTraffic{StickyHeader: t.StickyHeader}
+	for _, s := range t.Splits {
+		if s.FnID != fnID {
+			without.Splits = append(without.Splits, s)
+		}
+	}
+	if len(without.Splits) == 0 {
+		return nil
+	}
+	return without
+}
+
+// Pick returns the id of the fn that gets the calls in bucket, from 0 to 99,
+// or "" if the fn the traffic is of gets them
+func (t *FnTraffic) Pick(bucket int) string {
+	for _, s := range t.Splits {
+		if bucket < s.Weight {
+			return s.FnID
+		}
+		bucket -= s.Weight
+	}
+	return ""
+}
+
+// Value implements sql.Valuer, returning a string, a nil traffic split is stored as NULL
+func (t FnTraffic) Value() (driver.Value, error) {
+	var b bytes.Buffer
+	err := json.NewEncoder(&b).Encode(t)
+	return driver.Value(b.String()), err
+}
+
+// Scan implements sql.Scanner
+func (t *FnTraffic) Scan(value interface{}) error {
+	*t = FnTraffic{}
+	return scanJSON(value, t, "traffic")
+}
+
+func (f *Fn) validateTraffic() error {
+	t := f.Traffic
+	if t == nil {
+		return nil
+	}
+	if len(t.Splits) == 0 {
+		return ErrFnsTrafficMissingSplits
+	}
+	total := 0
+	seen := make(map[string]bool, len(t.Splits))
+	for _, s := range t.Splits {
+		if s.FnID == "" || s.FnID == f.ID || seen[s.FnID] {
+			return ErrFnsTrafficInvalidSplit
+		}
+		seen[s.FnID] = true
+		if s.Weight < 1 || s.Weight > 100 {
+			return ErrFnsTrafficInvalidWeight
+		}
+		total += s.Weight
+	}
+	if total > 100 {
+		return ErrFnsTrafficInvalidWeight
+	}
+	if t.StickyHeader != "" && !ValidHeaderName(t.StickyHeader) {
+		return ErrFnsTrafficInvalidStickyHeader
+	}
+	return nil
+}
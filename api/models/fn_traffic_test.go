@@ -0,0 +1,73 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestValidateFnTraffic(t *testing.T) {
+	for i, tc := range []struct {
+		traffic  *FnTraffic
+		expected error
+	}{
+		{nil, nil},
+		{&FnTraffic{Splits: []TrafficSplit{{FnID: "canary", Weight: 5}}}, nil},
+		{&FnTraffic{Splits: []TrafficSplit{{FnID: "a", Weight: 50}, {FnID: "b", Weight: 50}}, StickyHeader: "X-User-Id"}, nil},
+		{&FnTraffic{}, ErrFnsTrafficMissingSplits},
+		{&FnTraffic{Splits: []TrafficSplit{{Weight: 5}}}, ErrFnsTrafficInvalidSplit},
+		{&FnTraffic{Splits: []TrafficSplit{{FnID: "fn", Weight: 5}}}, ErrFnsTrafficInvalidSplit},
+		{&FnTraffic{Splits: []TrafficSplit{{FnID: "a", Weight: 5}, {FnID: "a", Weight: 5}}}, ErrFnsTrafficInvalidSplit},
+		{&FnTraffic{Splits: []TrafficSplit{{FnID: "a", Weight: 0}}}, ErrFnsTrafficInvalidWeight},
+		{&FnTraffic{Splits: []TrafficSplit{{FnID: "a", Weight: 101}}}, ErrFnsTrafficInvalidWeight},
+		{&FnTraffic{Splits: []TrafficSplit{{FnID: "a", Weight: 60}, {FnID: "b", Weight: 50}}}, ErrFnsTrafficInvalidWeight},
+		{&FnTraffic{Splits: []TrafficSplit{{FnID: "a", Weight: 5}}, StickyHeader: "X User"}, ErrFnsTrafficInvalidStickyHeader},
+	} {
+		fn := &Fn{ID: "fn", Name: "name", AppID: "app", Image: "image", Traffic: tc.traffic}
+		fn.SetDefaults()
+		if err := fn.Validate(); err != tc.expected {
+			t.Errorf("Test %d: expected %v, got %v", i, tc.expected, err)
+		}
+	}
+}
+
+func TestFnTrafficPick(t *testing.T) {
+	traffic := &FnTraffic{Splits: []TrafficSplit{{FnID: "a", Weight: 10}, {FnID: "b", Weight: 20}}}
+	for bucket, expected := range map[int]string{0: "a", 9: "a", 10: "b", 29: "b", 30: "", 99: ""} {
+		if got := traffic.Pick(bucket); got != expected {
+			t.Errorf("Bucket %d: expected %q, got %q", bucket, expected, got)
+		}
+	}
+}
+
+func TestFnTrafficWithout(t *testing.T) {
+	traffic := &FnTraffic{Splits: []TrafficSplit{{FnID: "a", Weight: 10}, {FnID: "b", Weight: 20}}, StickyHeader: "X-User"}
+
+	without := traffic.Without("a")
+	if without == nil || len(without.Splits) != 1 || without.Splits[0].FnID != "b" || without.StickyHeader != "X-User" {
+		t.Errorf("expected only the split to b to be left, got %v", without)
+	}
+	if len(traffic.Splits) != 2 {
+		t.Errorf("expected the traffic split from to be left alone, got %v", traffic)
+	}
+	if without := without.Without("b"); without != nil {
+		t.Errorf("expected no traffic once no splits are left, got %v", without)
+	}
+}
+
+func TestFnTrafficUpdate(t *testing.T) {
+	fn := &Fn{ID: "fn", Traffic: &FnTraffic{Splits: []TrafficSplit{{FnID: "a", Weight: 5}}}}
+
+	fn.Update(&Fn{Traffic: &FnTraffic{Splits: []TrafficSplit{{FnID: "b", Weight: 50}}}})
+	if len(fn.Traffic.Splits) != 1 || fn.Traffic.Splits[0].FnID != "b" {
+		t.Errorf("expected the traffic split to be replaced, got %v", fn.Traffic)
+	}
+
+	fn.Update(&Fn{})
+	if fn.Traffic == nil {
+		t.Errorf("expected the traffic split to be kept when not patched")
+	}
+
+	fn.Update(&Fn{Traffic: &FnTraffic{}})
+	if fn.Traffic != nil {
+		t.Errorf("expected an empty traffic split to remove it, got %v", fn.Traffic)
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"errors"
+	"net/http"
+)
+
+const (
+	// JWTAudiencesAnnotation is the annotation of an app or fn that lists the
+	// audiences, one of which the bearer tokens of calls must be intended for
+	JWTAudiencesAnnotation = "fnproject.io/jwt/audiences"
+
+	// JWTClaimsAnnotation is the annotation of an app or fn that maps claims to
+	// the values the bearer tokens of calls must have for them
+	JWTClaimsAnnotation = "fnproject.io/jwt/claims"
+)
+
+var (
+	//ErrJWTMissing - a call is made without a bearer token where one is required
+	ErrJWTMissing = err{
+		code:  http.StatusUnauthorized,
+		error: errors.New("Missing bearer token")}
+	//ErrJWTInvalid - the bearer token of a call can't be verified
+	ErrJWTInvalid = err{
+		code:  http.StatusUnauthorized,
+		error: errors.New("Invalid bearer token")}
+	//ErrJWTRefused - the bearer token of a call is valid but lacks the audience or claims the app or fn requires
+	ErrJWTRefused = err{
+		code:  http.StatusForbidden,
+		error: errors.New("Bearer token lacks the audience or claims required to call this function")}
+)
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// Leaser hands out named leases which lapse unless renewed, so that one of
+// several nodes sharing a store can be elected to do some job alone, e.g.
+// firing schedule triggers.
+type Leaser interface {
+	// AcquireLease takes the lease name for holder until ttl has passed, or
+	// extends it if holder already has it. It returns false, and no error, if
+	// another holder has the lease and it has not lapsed yet.
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+
+	// ReleaseLease gives up the lease name if it is held by holder, so that
+	// others need not wait for it to lapse.
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
+// Lease is a request of a holder for a lease, and whether it got it, as
+// exchanged by nodes that reach a Leaser through the API nodes
+type Lease struct {
+	Holder   string        `json:"holder"`
+	TTL      time.Duration `json:"ttl"`
+	Acquired bool          `json:"acquired"`
+}
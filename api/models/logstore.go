@@ -0,0 +1,26 @@
+package models
+
+import (
+	"context"
+	"io"
+)
+
+// LogStore keeps the (size limited) stderr output of calls, so that it can be
+// inspected after the fact via the API.
+type LogStore interface {
+	// InsertLog stores the contents of callLog for call, replacing any previous
+	// log for the same call. Returns ErrDatastoreEmptyCallID or ErrDatastoreEmptyFnID
+	// when call.ID or call.FnID are empty.
+	InsertLog(ctx context.Context, call *Call, callLog io.Reader) error
+
+	// GetLog gets the log of a call by fn ID and call ID.
+	// Returns ErrDatastoreEmptyFnID or ErrDatastoreEmptyCallID for empty arguments.
+	// Returns ErrCallLogNotFound if no log is found.
+	GetLog(ctx context.Context, fnID, callID string) (io.Reader, error)
+}
+
+// CallLog is the log output of a call
+type CallLog struct {
+	CallID string `json:"call_id"`
+	Log    string `json:"log"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// MQ is a durable queue of detached calls waiting to be run. A call stays on
+// the queue until whoever reserved it deletes it, so that no call is lost if
+// a node dies or placement fails; calls may run more than once as a result.
+type MQ interface {
+	// Push adds a call to the queue, to be handed out once call.Delay seconds
+	// have passed. The call is safely stored once Push returns.
+	// Returns ErrDatastoreEmptyCallID when call.ID is empty.
+	Push(ctx context.Context, call *Call) (*Call, error)
+
+	// Reserve takes the oldest due call off the queue until timeout has passed,
+	// after which it is handed out again unless it has been deleted.
+	// Returns nil, and no error, when there is no call to run.
+	Reserve(ctx context.Context, timeout time.Duration) (*Call, error)
+
+	// Delete removes a call from the queue, once it needs no more attempts.
+	// Returns ErrDatastoreEmptyCallID when call.ID is empty.
+	Delete(ctx context.Context, call *Call) error
+}
@@ -0,0 +1,229 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinScheduleInterval is the shortest interval an @every schedule may have,
+// cron expressions cannot fire more often than this anyway.
+const MinScheduleInterval = time.Minute
+
+// Schedule is a parsed cron expression, as used as the source of schedule
+// triggers. All schedules are evaluated in UTC.
+//
+// A schedule is either the standard five fields, minute, hour, day of month,
+// month and day of week, each a comma separated list of `*`, a value or a
+// range with an optional `/step`; or one of the descriptors @yearly
+// (@annually), @monthly, @weekly, @daily (@midnight), @hourly or
+// `@every <duration>`. Months and days of week may be given by their three
+// letter english names.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// if either day field is a wildcard both must match, otherwise either one may
+	domStar, dowStar bool
+
+	every time.Duration
+}
+
+type scheduleField struct {
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	minuteField = scheduleField{min: 0, max: 59}
+	hourField   = scheduleField{min: 0, max: 23}
+	domField    = scheduleField{min: 1, max: 31}
+	monthField  = scheduleField{min: 1, max: 12, names: map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is accepted as sunday too, it is folded onto 0 once parsed
+	dowField = scheduleField{min: 0, max: 7, names: map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+
+	scheduleDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// ParseSchedule parses a cron expression or descriptor into a Schedule.
+func ParseSchedule(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, errors.New("empty schedule")
+	}
+
+	if strings.HasPrefix(expr, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid @every duration: %v", err)
+		}
+		if d < MinScheduleInterval {
+			return nil, fmt.Errorf("@every duration must be at least %v", MinScheduleInterval)
+		}
+		return &Schedule{every: d}, nil
+	}
+
+	if strings.HasPrefix(expr, "@") {
+		std, ok := scheduleDescriptors[strings.ToLower(expr)]
+		if !ok {
+			return nil, fmt.Errorf("unknown schedule descriptor %q", expr)
+		}
+		expr = std
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in schedule, got %d", len(fields))
+	}
+
+	var s Schedule
+	var err error
+	if s.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if s.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if s.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if s.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+
+	return &s, nil
+}
+
+// parse returns the set of values of a field as a bitmask
+func (f scheduleField) parse(field string) (uint64, error) {
+	var bits uint64
+	for _, term := range strings.Split(field, ",") {
+		b, err := f.parseTerm(term)
+		if err != nil {
+			return 0, err
+		}
+		bits |= b
+	}
+	return bits, nil
+}
+
+func (f scheduleField) parseTerm(term string) (uint64, error) {
+	rng, step := term, uint(1)
+	if i := strings.Index(term, "/"); i >= 0 {
+		s, err := strconv.ParseUint(term[i+1:], 10, 8)
+		if err != nil || s == 0 {
+			return 0, fmt.Errorf("invalid step in %q", term)
+		}
+		rng, step = term[:i], uint(s)
+	}
+
+	var lo, hi uint
+	switch {
+	case rng == "*":
+		lo, hi = f.min, f.max
+	case strings.Contains(rng, "-"):
+		parts := strings.SplitN(rng, "-", 2)
+		var err error
+		if lo, err = f.value(parts[0]); err != nil {
+			return 0, err
+		}
+		if hi, err = f.value(parts[1]); err != nil {
+			return 0, err
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range in %q", term)
+		}
+	default:
+		v, err := f.value(rng)
+		if err != nil {
+			return 0, err
+		}
+		lo, hi = v, v
+		// a/n is shorthand for a-max/n
+		if step > 1 {
+			hi = f.max
+		}
+	}
+
+	var bits uint64
+	for v := lo; v <= hi; v += step {
+		bits |= 1 << v
+	}
+	return bits, nil
+}
+
+func (f scheduleField) value(s string) (uint, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.ParseUint(s, 10, 8)
+	if err != nil || uint(v) < f.min || uint(v) > f.max {
+		return 0, fmt.Errorf("value %q out of range, must be between %d and %d", s, f.min, f.max)
+	}
+	return uint(v), nil
+}
+
+// Next returns the first time the schedule fires strictly after t, in UTC.
+// It returns the zero time if the schedule can never fire, e.g. on 30 Feb.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.UTC()
+	if s.every > 0 {
+		return t.Add(s.every).Truncate(time.Second)
+	}
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// every combination repeats within a leap year cycle
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
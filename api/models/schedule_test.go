@@ -0,0 +1,65 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"@fortnightly",
+		"@every 10s",
+		"@every soon",
+		"/foo",
+	} {
+		if _, err := ParseSchedule(expr); err == nil {
+			t.Errorf("expected schedule %q to be invalid", expr)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// a wednesday
+	from := time.Date(2018, time.August, 1, 10, 17, 30, 0, time.UTC)
+
+	for _, tc := range []struct {
+		expr string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2018, time.August, 1, 10, 18, 0, 0, time.UTC)},
+		{"*/5 * * * *", time.Date(2018, time.August, 1, 10, 20, 0, 0, time.UTC)},
+		{"15,45 * * * *", time.Date(2018, time.August, 1, 10, 45, 0, 0, time.UTC)},
+		{"0 9-17 * * *", time.Date(2018, time.August, 1, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2018, time.August, 2, 2, 30, 0, 0, time.UTC)},
+		{"0 0 * * fri", time.Date(2018, time.August, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2018, time.August, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// either day field may match when both are restricted
+		{"0 0 15 * mon", time.Date(2018, time.August, 6, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2018, time.August, 1, 11, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2018, time.September, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90m", time.Date(2018, time.August, 1, 11, 47, 30, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	} {
+		s, err := ParseSchedule(tc.expr)
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %v", tc.expr, err)
+			continue
+		}
+		if next := s.Next(from); !next.Equal(tc.next) {
+			t.Errorf("expected %q to fire next at %v, got %v", tc.expr, tc.next, next)
+		}
+	}
+}
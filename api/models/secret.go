@@ -0,0 +1,127 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// SecretStore encrypts the values of the secret config of apps and fns, so
+// that they are only ever stored, and sent between nodes, as ciphertexts.
+// The API encrypts them as they are set, and the agent alone decrypts them,
+// as it builds the config of a call.
+type SecretStore interface {
+	// Encrypt seals the value of a secret config entry.
+	Encrypt(ctx context.Context, plaintext string) (string, error)
+
+	// Decrypt opens a value sealed by Encrypt.
+	Decrypt(ctx context.Context, ciphertext string) (string, error)
+}
+
+// RedactedSecret is what the values of secret config entries are replaced
+// with in API responses. Entries set to it in updates are left as they are,
+// so that apps and fns can be read and written back as a whole.
+const RedactedSecret = "[redacted]"
+
+// SecretFilesAnnotation is the annotation of an app or fn that lists the names
+// of the secret config entries that are mounted in its containers as read only
+// files at /run/secrets/<name>, rather than set in their environment
+const SecretFilesAnnotation = "fnproject.io/secrets/files"
+
+// maxSecretFileName is the longest file name most filesystems take
+const maxSecretFileName = 255
+
+var (
+	//ErrSecretsNotSupported - there is no secret store to seal or open secret config with
+	ErrSecretsNotSupported = err{
+		code:  http.StatusNotImplemented,
+		error: errors.New("Secret config is not supported on this server, no secret store is configured"),
+	}
+	//ErrInvalidSecretFiles - the secret files annotation isn't a list of names that can be files
+	ErrInvalidSecretFiles = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("The " + SecretFilesAnnotation + " annotation must list the names of secret config entries, made of letters, digits, '_', '-' and '.' and not starting with '.'"),
+	}
+)
+
+// SecretFiles is the set of the names of the secret config entries that
+// annotations mount as files, which is empty if they mount none
+func SecretFiles(annotations Annotations) (map[string]bool, error) {
+	raw, ok := annotations.Get(SecretFilesAnnotation)
+	if !ok {
+		return nil, nil
+	}
+	var names []string
+	if err := json.Unmarshal(raw, &names); err != nil {
+		return nil, ErrInvalidSecretFiles
+	}
+	files := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !validSecretFileName(name) {
+			return nil, ErrInvalidSecretFiles
+		}
+		files[name] = true
+	}
+	return files, nil
+}
+
+// validSecretFileName tells if name can be the name of a file in the secrets
+// directory of a container, and of that directory only
+func validSecretFileName(name string) bool {
+	if name == "" || name[0] == '.' || len(name) > maxSecretFileName {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// Redacted is a copy of c with every value replaced by RedactedSecret
+func (c Config) Redacted() Config {
+	if c == nil {
+		return nil
+	}
+	redacted := make(Config, len(c))
+	for k := range c {
+		redacted[k] = RedactedSecret
+	}
+	return redacted
+}
+
+// Redacted is a copy of a that can be returned by the API, with the values
+// of its secret config redacted
+func (a *App) Redacted() *App {
+	a = a.Clone()
+	a.SecretConfig = a.SecretConfig.Redacted()
+	return a
+}
+
+// Redacted is a copy of f that can be returned by the API, with the values
+// of its secret config redacted
+func (f *Fn) Redacted() *Fn {
+	f = f.Clone()
+	f.SecretConfig = f.SecretConfig.Redacted()
+	return f
+}
+
+// Redacted is a copy of r that can be returned by the API, with the values
+// of its secret config redacted
+func (r *FnRevision) Redacted() *FnRevision {
+	redacted := *r
+	redacted.SecretConfig = r.SecretConfig.Redacted()
+	return &redacted
+}
+
+// Redacted is a copy of t that can be returned by the API, with the secret
+// of its signature redacted
+func (t *Trigger) Redacted() *Trigger {
+	t = t.Clone()
+	if t.Signature != nil {
+		t.Signature.Secret = RedactedSecret
+	}
+	return t
+}
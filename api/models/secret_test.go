@@ -0,0 +1,36 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSecretFilesValidate(t *testing.T) {
+	for _, tc := range []struct {
+		annotation string
+		expected   error
+	}{
+		{`["DB_PASSWORD", "tls.key", "api-key-2"]`, nil},
+		{`[]`, nil},
+		{`"DB_PASSWORD"`, ErrInvalidSecretFiles},
+		{`true`, ErrInvalidSecretFiles},
+		{`["../etc/passwd"]`, ErrInvalidSecretFiles},
+		{`["a/b"]`, ErrInvalidSecretFiles},
+		{`[".hidden"]`, ErrInvalidSecretFiles},
+		{`[""]`, ErrInvalidSecretFiles},
+		{`["` + strings.Repeat("a", maxSecretFileName+1) + `"]`, ErrInvalidSecretFiles},
+	} {
+		raw := annotationValue(tc.annotation)
+		annotations := Annotations{SecretFilesAnnotation: &raw}
+
+		app := &App{Name: "app", Annotations: annotations}
+		if err := app.Validate(); err != tc.expected {
+			t.Errorf("expected %v for the app annotation %s, got %v", tc.expected, tc.annotation, err)
+		}
+		fn := &Fn{AppID: "app", Name: "fn", Image: "fnproject/hello", Annotations: annotations,
+			ResourceConfig: ResourceConfig{Memory: DefaultMemory, Timeout: DefaultTimeout, IdleTimeout: DefaultIdleTimeout}}
+		if err := fn.Validate(); err != tc.expected {
+			t.Errorf("expected %v for the fn annotation %s, got %v", tc.expected, tc.annotation, err)
+		}
+	}
+}
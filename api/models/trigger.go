@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
@@ -14,6 +16,9 @@ import (
 // TriggerHTTPEndpointAnnotation is the annotation that exposes the HTTP trigger endpoint For want of a better place to put this it's here
 const TriggerHTTPEndpointAnnotation = "fnproject.io/trigger/httpEndpoint"
 
+// TriggerWebSocketAnnotation is the annotation that, set to true, lets clients upgrade requests to an HTTP trigger to websockets
+const TriggerWebSocketAnnotation = "fnproject.io/trigger/webSocket"
+
 // Trigger represents a binding between a Function and an external event source
 type Trigger struct {
 	ID          string          `json:"id" db:"id"`
@@ -25,6 +30,16 @@ type Trigger struct {
 	Type        string          `json:"type" db:"type"`
 	Source      string          `json:"source" db:"source"`
 	Annotations Annotations     `json:"annotations,omitempty" db:"annotations"`
+	// Methods are the HTTP methods an http trigger allows, along with those in MethodFns. Any method is allowed if both are empty.
+	Methods HTTPMethods `json:"methods,omitempty" db:"methods"`
+	// MethodFns maps HTTP methods to the ids of fns other than FnID that handle requests to an http trigger with them
+	MethodFns MethodFns `json:"method_fns,omitempty" db:"method_fns"`
+	// CORS is the CORS policy of an http trigger, requests from other origins are left to fns if nil
+	CORS *TriggerCORS `json:"cors,omitempty" db:"cors"`
+	// Signature is how requests to an http trigger are signed, any request is let through if nil
+	Signature *TriggerSignature `json:"signature,omitempty" db:"signature"`
+	// Alias is the name of an alias of the app the trigger calls the fn of, in place of FnID, which must be empty then
+	Alias string `json:"alias,omitempty" db:"alias"`
 }
 
 // Equals compares two triggers for semantic equality  it ignores timestamp fields but includes annotations
@@ -34,10 +49,15 @@ func (t *Trigger) Equals(t2 *Trigger) bool {
 	eq = eq && t.Name == t2.Name
 	eq = eq && t.AppID == t2.AppID
 	eq = eq && t.FnID == t2.FnID
+	eq = eq && t.Alias == t2.Alias
 
 	eq = eq && t.Type == t2.Type
 	eq = eq && t.Source == t2.Source
 	eq = eq && t.Annotations.Equals(t2.Annotations)
+	eq = eq && t.Methods.Equals(t2.Methods)
+	eq = eq && t.MethodFns.Equals(t2.MethodFns)
+	eq = eq && t.CORS.Equals(t2.CORS)
+	eq = eq && t.Signature.Equals(t2.Signature)
 
 	return eq
 }
@@ -49,10 +69,15 @@ func (t *Trigger) EqualsWithAnnotationSubset(t2 *Trigger) bool {
 	eq = eq && t.Name == t2.Name
 	eq = eq && t.AppID == t2.AppID
 	eq = eq && t.FnID == t2.FnID
+	eq = eq && t.Alias == t2.Alias
 
 	eq = eq && t.Type == t2.Type
 	eq = eq && t.Source == t2.Source
 	eq = eq && t.Annotations.Subset(t2.Annotations)
+	eq = eq && t.Methods.Equals(t2.Methods)
+	eq = eq && t.MethodFns.Equals(t2.MethodFns)
+	eq = eq && t.CORS.Equals(t2.CORS)
+	eq = eq && t.Signature.Equals(t2.Signature)
 
 	return eq
 }
@@ -60,21 +85,80 @@ func (t *Trigger) EqualsWithAnnotationSubset(t2 *Trigger) bool {
 //TriggerTypeHTTP represents an HTTP trigger
 const TriggerTypeHTTP = "http"
 
-var triggerTypes = []string{TriggerTypeHTTP}
+//TriggerTypeSchedule represents a trigger fired on a cron schedule, its source is the schedule
+const TriggerTypeSchedule = "schedule"
+
+//TriggerSourceValidator checks that a source is valid for triggers of some type, returning an APIError if not
+type TriggerSourceValidator func(source string) error
+
+var (
+	triggerTypesLock sync.RWMutex
+	triggerTypes     = map[string]TriggerSourceValidator{
+		TriggerTypeHTTP:     validateHTTPTriggerSource,
+		TriggerTypeSchedule: validateScheduleTriggerSource,
+	}
+)
+
+//RegisterTriggerType adds a trigger type to this service, along with the validator for the sources
+//of triggers of that type. A nil validator accepts any non empty source. Trigger types are
+//shared by all the servers of a process, they may be registered from any goroutine.
+func RegisterTriggerType(triggerType string, validate TriggerSourceValidator) error {
+	if triggerType == "" {
+		return ErrTriggerTypeMissingName
+	}
+	if validate == nil {
+		validate = func(string) error { return nil }
+	}
+
+	triggerTypesLock.Lock()
+	defer triggerTypesLock.Unlock()
+	if _, ok := triggerTypes[triggerType]; ok {
+		return ErrTriggerTypeExists
+	}
+	triggerTypes[triggerType] = validate
+	return nil
+}
 
 //ValidTriggerTypes lists the supported trigger types in this service
 func ValidTriggerTypes() []string {
-	return triggerTypes
+	triggerTypesLock.RLock()
+	defer triggerTypesLock.RUnlock()
+	types := make([]string, 0, len(triggerTypes))
+	for t := range triggerTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
 }
 
 //ValidTriggerType checks that a given trigger type is valid on this service
 func ValidTriggerType(a string) bool {
-	for _, b := range triggerTypes {
-		if b == a {
-			return true
+	return triggerSourceValidator(a) != nil
+}
+
+func triggerSourceValidator(triggerType string) TriggerSourceValidator {
+	triggerTypesLock.RLock()
+	defer triggerTypesLock.RUnlock()
+	return triggerTypes[triggerType]
+}
+
+func validateHTTPTriggerSource(source string) error {
+	if !strings.HasPrefix(source, "/") {
+		return ErrTriggerMissingSourcePrefix
+	}
+	if IsTriggerSourceTemplate(source) {
+		if _, err := parseSourceTemplate(source); err != nil {
+			return err
 		}
 	}
-	return false
+	return nil
+}
+
+func validateScheduleTriggerSource(source string) error {
+	if _, err := ParseSchedule(source); err != nil {
+		return ErrTriggerInvalidSchedule
+	}
+	return nil
 }
 
 var (
@@ -108,6 +192,10 @@ var (
 	ErrTriggerMissingFnID = err{
 		code:  http.StatusBadRequest,
 		error: errors.New("Missing Fn ID on Trigger")}
+	//ErrTriggerFnIDAndAlias - both a fn id and an alias are specified on a trigger
+	ErrTriggerFnIDAndAlias = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Trigger can have either a Fn ID or an alias, not both")}
 	//ErrTriggerFnIDNotSameApp - specified Fn does not belong to the same app as the provided AppID
 	ErrTriggerFnIDNotSameApp = err{
 		code:  http.StatusBadRequest,
@@ -116,6 +204,14 @@ var (
 	ErrTriggerTypeUnknown = err{
 		code:  http.StatusBadRequest,
 		error: errors.New("Trigger Type Not Supported")}
+	//ErrTriggerTypeMissingName - a trigger type is registered without a name
+	ErrTriggerTypeMissingName = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Missing name for Trigger Type")}
+	//ErrTriggerTypeExists - a trigger type with the same name is already registered
+	ErrTriggerTypeExists = err{
+		code:  http.StatusConflict,
+		error: errors.New("Trigger Type already exists")}
 	//ErrTriggerMissingSource - no source spceified for trigger
 	ErrTriggerMissingSource = err{
 		code:  http.StatusBadRequest,
@@ -124,6 +220,14 @@ var (
 	ErrTriggerMissingSourcePrefix = err{
 		code:  http.StatusBadRequest,
 		error: errors.New("Missing Trigger Source Prefix '/'")}
+	//ErrTriggerInvalidSourceTemplate - source of an http trigger is not a valid template
+	ErrTriggerInvalidSourceTemplate = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid Trigger Source template, parameters must be named whole segments such as /users/{id}, and only the last segment may be a wildcard such as /files/*rest")}
+	//ErrTriggerInvalidSchedule - source of a schedule trigger is not a valid cron expression
+	ErrTriggerInvalidSchedule = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid Trigger Source, schedule triggers require a cron expression such as '*/5 * * * *' or '@hourly'")}
 	//ErrTriggerNotFound - trigger not found
 	ErrTriggerNotFound = err{
 		code:  http.StatusNotFound,
@@ -136,6 +240,70 @@ var (
 	ErrTriggerSourceExists = err{
 		code:  http.StatusConflict,
 		error: errors.New("Trigger with the same type and source exists on this app")}
+	//ErrTriggerMethodsNotHTTP - methods are set on a trigger that is not an http trigger
+	ErrTriggerMethodsNotHTTP = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Only http triggers can have methods")}
+	//ErrTriggerInvalidMethod - a method of a trigger is not an HTTP method
+	ErrTriggerInvalidMethod = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid method on Trigger, methods must be upper case HTTP methods such as GET")}
+	//ErrTriggerMissingMethodFnID - a method of a trigger is mapped to no fn
+	ErrTriggerMissingMethodFnID = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Missing Fn ID for method on Trigger")}
+	//ErrTriggerCORSNotHTTP - a CORS policy is set on a trigger that is not an http trigger
+	ErrTriggerCORSNotHTTP = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Only http triggers can have a CORS policy")}
+	//ErrTriggerCORSMissingOrigins - the CORS policy of a trigger allows no origins
+	ErrTriggerCORSMissingOrigins = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Missing allowed origins in the CORS policy of Trigger")}
+	//ErrTriggerInvalidCORSOrigin - an origin in the CORS policy of a trigger is not an origin
+	ErrTriggerInvalidCORSOrigin = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid origin in the CORS policy of Trigger, origins must be lower case schemes and hosts such as https://example.com, or *")}
+	//ErrTriggerInvalidCORSHeader - a header in the CORS policy of a trigger is not a header name
+	ErrTriggerInvalidCORSHeader = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid header in the CORS policy of Trigger")}
+	//ErrTriggerInvalidCORSMaxAge - the max age in the CORS policy of a trigger is negative
+	ErrTriggerInvalidCORSMaxAge = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid max age in the CORS policy of Trigger, it must not be negative")}
+	//ErrTriggerCORSRefused - a request from another origin is not allowed by the CORS policy of a trigger
+	ErrTriggerCORSRefused = err{
+		code:  http.StatusForbidden,
+		error: errors.New("Request not allowed by the CORS policy of Trigger")}
+	//ErrTriggerSignatureNotHTTP - a signature is set on a trigger that is not an http trigger
+	ErrTriggerSignatureNotHTTP = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Only http triggers can have a signature")}
+	//ErrTriggerMissingSignatureSecret - the signature of a trigger has no secret
+	ErrTriggerMissingSignatureSecret = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Missing secret in the signature of Trigger")}
+	//ErrTriggerInvalidSignatureHeader - the header of the signature of a trigger is not a header name
+	ErrTriggerInvalidSignatureHeader = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid header in the signature of Trigger")}
+	//ErrTriggerInvalidSignatureAlgorithm - the algorithm of the signature of a trigger is not supported
+	ErrTriggerInvalidSignatureAlgorithm = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid algorithm in the signature of Trigger, it must be one of sha1, sha256 or sha512")}
+	//ErrTriggerInvalidSignatureEncoding - the encoding of the signature of a trigger is not supported
+	ErrTriggerInvalidSignatureEncoding = err{
+		code:  http.StatusBadRequest,
+		error: errors.New("Invalid encoding in the signature of Trigger, it must be hex or base64")}
+	//ErrTriggerSignatureMismatch - a request to a trigger is not signed as the trigger requires
+	ErrTriggerSignatureMismatch = err{
+		code:  http.StatusUnauthorized,
+		error: errors.New("Missing or invalid signature on request to Trigger")}
+	//ErrTriggerSignedBodyTooBig - the body of a request to a signed trigger is too large to be read whole to verify it
+	ErrTriggerSignedBodyTooBig = err{
+		code:  http.StatusRequestEntityTooLarge,
+		error: errors.New("Request to Trigger is too large to verify its signature")}
 )
 
 //Validate checks that trigger has valid data for inserting into a store
@@ -148,11 +316,22 @@ func (t *Trigger) Validate() error {
 		return err
 	}
 
-	if t.FnID == "" {
+	if t.FnID == "" && t.Alias == "" {
 		return ErrTriggerMissingFnID
 	}
 
-	if !ValidTriggerType(t.Type) {
+	if t.FnID != "" && t.Alias != "" {
+		return ErrTriggerFnIDAndAlias
+	}
+
+	if t.Alias != "" {
+		if err := ValidateAliasName(t.Alias); err != nil {
+			return err
+		}
+	}
+
+	validateSource := triggerSourceValidator(t.Type)
+	if validateSource == nil {
 		return ErrTriggerTypeUnknown
 	}
 
@@ -160,8 +339,8 @@ func (t *Trigger) Validate() error {
 		return ErrTriggerMissingSource
 	}
 
-	if !strings.HasPrefix(t.Source, "/") {
-		return ErrTriggerMissingSourcePrefix
+	if err := validateSource(t.Source); err != nil {
+		return err
 	}
 
 	err := t.Annotations.Validate()
@@ -169,7 +348,15 @@ func (t *Trigger) Validate() error {
 		return err
 	}
 
-	return nil
+	if err := t.validateMethods(); err != nil {
+		return err
+	}
+
+	if err := t.validateCORS(); err != nil {
+		return err
+	}
+
+	return t.validateSignature()
 }
 
 func (t *Trigger) ValidateName() error {
@@ -195,6 +382,10 @@ func (t *Trigger) Clone() *Trigger {
 	clone := new(Trigger)
 	*clone = *t // shallow copy
 	// annotations are immutable via their interface so can be shallow copied
+	clone.Methods = t.Methods.clone()
+	clone.MethodFns = t.MethodFns.clone()
+	clone.CORS = t.CORS.clone()
+	clone.Signature = t.Signature.clone()
 	return clone
 }
 
@@ -206,8 +397,15 @@ func (t *Trigger) Update(patch *Trigger) {
 		t.AppID = patch.AppID
 	}
 
+	// a trigger targets either a fn or an alias, so setting one clears the other
 	if patch.FnID != "" {
 		t.FnID = patch.FnID
+		t.Alias = ""
+	}
+
+	if patch.Alias != "" {
+		t.Alias = patch.Alias
+		t.FnID = ""
 	}
 
 	if patch.Name != "" {
@@ -220,6 +418,30 @@ func (t *Trigger) Update(patch *Trigger) {
 
 	t.Annotations = t.Annotations.MergeChange(patch.Annotations)
 
+	// methods are replaced as a whole, set them empty to allow any method
+	if patch.Methods != nil {
+		t.Methods = patch.Methods.clone()
+	}
+
+	if patch.MethodFns != nil {
+		t.MethodFns = patch.MethodFns.clone()
+	}
+
+	// the policy is replaced as a whole too, set it empty to remove it
+	if patch.CORS != nil {
+		t.CORS = patch.CORS.clone()
+		if t.CORS.empty() {
+			t.CORS = nil
+		}
+	}
+
+	if patch.Signature != nil {
+		t.Signature = patch.Signature.clone()
+		if t.Signature.empty() {
+			t.Signature = nil
+		}
+	}
+
 	if !t.Equals(original) {
 		t.UpdatedAt = common.DateTime(time.Now())
 	}
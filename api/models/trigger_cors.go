@@ -0,0 +1,164 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// TriggerCORS is the CORS policy of an http trigger. Preflight requests to
+// the trigger are answered by the front end by the policy, without calling
+// any fn, and responses to requests from allowed origins carry the headers
+// that let browsers read them.
+type TriggerCORS struct {
+	// AllowOrigins are the origins, such as https://example.com, that may make requests to the trigger, "*" allows any
+	AllowOrigins []string `json:"allow_origins,omitempty"`
+	// AllowMethods are the methods allowed in requests from other origins, the methods the trigger allows if empty
+	AllowMethods []string `json:"allow_methods,omitempty"`
+	// AllowHeaders are the headers allowed in requests from other origins besides the CORS safelisted ones, "*" allows any
+	AllowHeaders []string `json:"allow_headers,omitempty"`
+	// MaxAge is how long, in seconds, browsers may cache the answer to a preflight request
+	MaxAge int `json:"max_age,omitempty"`
+}
+
+// Equals checks that two policies are the same, either may be nil
+func (c *TriggerCORS) Equals(other *TriggerCORS) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return stringsEqual(c.AllowOrigins, other.AllowOrigins) &&
+		stringsEqual(c.AllowMethods, other.AllowMethods) &&
+		stringsEqual(c.AllowHeaders, other.AllowHeaders) &&
+		c.MaxAge == other.MaxAge
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *TriggerCORS) clone() *TriggerCORS {
+	if c == nil {
+		return nil
+	}
+	return &TriggerCORS{
+		AllowOrigins: append([]string(nil), c.AllowOrigins...),
+		AllowMethods: append([]string(nil), c.AllowMethods...),
+		AllowHeaders: append([]string(nil), c.AllowHeaders...),
+		MaxAge:       c.MaxAge,
+	}
+}
+
+func (c *TriggerCORS) empty() bool {
+	return len(c.AllowOrigins) == 0 && len(c.AllowMethods) == 0 && len(c.AllowHeaders) == 0 && c.MaxAge == 0
+}
+
+// Value implements sql.Valuer, returning a string, a nil policy is stored as NULL
+func (c TriggerCORS) Value() (driver.Value, error) {
+	var b bytes.Buffer
+	err := json.NewEncoder(&b).Encode(c)
+	return driver.Value(b.String()), err
+}
+
+// Scan implements sql.Scanner
+func (c *TriggerCORS) Scan(value interface{}) error {
+	*c = TriggerCORS{}
+	return scanJSON(value, c, "cors")
+}
+
+func (t *Trigger) validateCORS() error {
+	if t.CORS == nil {
+		return nil
+	}
+	if t.Type != TriggerTypeHTTP {
+		return ErrTriggerCORSNotHTTP
+	}
+	if len(t.CORS.AllowOrigins) == 0 {
+		return ErrTriggerCORSMissingOrigins
+	}
+	for _, o := range t.CORS.AllowOrigins {
+		if o != "*" && !validCORSOrigin(o) {
+			return ErrTriggerInvalidCORSOrigin
+		}
+	}
+	for _, m := range t.CORS.AllowMethods {
+		if !validHTTPMethod(m) {
+			return ErrTriggerInvalidMethod
+		}
+	}
+	for _, h := range t.CORS.AllowHeaders {
+		if h != "*" && !ValidHeaderName(h) {
+			return ErrTriggerInvalidCORSHeader
+		}
+	}
+	if t.CORS.MaxAge < 0 {
+		return ErrTriggerInvalidCORSMaxAge
+	}
+	return nil
+}
+
+// validCORSOrigin checks that o is an origin as browsers send them, a scheme and host with no path
+func validCORSOrigin(o string) bool {
+	u, err := url.Parse(o)
+	if err != nil {
+		return false
+	}
+	return u.Scheme != "" && u.Host != "" && u.Path == "" && u.RawQuery == "" && u.Fragment == "" && u.User == nil && o == strings.ToLower(o)
+}
+
+// ValidHeaderName tells if h is a valid HTTP header name, a token of RFC 7230
+func ValidHeaderName(h string) bool {
+	if h == "" {
+		return false
+	}
+	for _, c := range h {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("!#$%&'*+-.^_`|~", c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowsOrigin tells if requests from origin may be made to the trigger
+func (c *TriggerCORS) AllowsOrigin(origin string) bool {
+	for _, o := range c.AllowOrigins {
+		if o == "*" || o == strings.ToLower(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsAnyOrigin tells if requests from any origin may be made to the trigger
+func (c *TriggerCORS) AllowsAnyOrigin() bool {
+	for _, o := range c.AllowOrigins {
+		if o == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsHeader tells if requests from other origins may have the header h
+func (c *TriggerCORS) AllowsHeader(h string) bool {
+	for _, a := range c.AllowHeaders {
+		if a == "*" || strings.EqualFold(a, h) {
+			return true
+		}
+	}
+	// the safelisted headers are always allowed
+	switch strings.ToLower(h) {
+	case "accept", "accept-language", "content-language", "content-type":
+		return true
+	}
+	return false
+}
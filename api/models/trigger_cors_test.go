@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestValidateTriggerCORS(t *testing.T) {
+	for i, tc := range []struct {
+		triggerType string
+		cors        *TriggerCORS
+		expected    error
+	}{
+		{TriggerTypeHTTP, nil, nil},
+		{TriggerTypeHTTP, &TriggerCORS{AllowOrigins: []string{"*"}}, nil},
+		{TriggerTypeHTTP, &TriggerCORS{AllowOrigins: []string{"https://example.com", "http://localhost:8080"}, AllowMethods: []string{"GET"}, AllowHeaders: []string{"X-Token", "*"}, MaxAge: 60}, nil},
+		{TriggerTypeSchedule, &TriggerCORS{AllowOrigins: []string{"*"}}, ErrTriggerCORSNotHTTP},
+		{TriggerTypeHTTP, &TriggerCORS{MaxAge: 60}, ErrTriggerCORSMissingOrigins},
+		{TriggerTypeHTTP, &TriggerCORS{AllowOrigins: []string{"example.com"}}, ErrTriggerInvalidCORSOrigin},
+		{TriggerTypeHTTP, &TriggerCORS{AllowOrigins: []string{"https://example.com/"}}, ErrTriggerInvalidCORSOrigin},
+		{TriggerTypeHTTP, &TriggerCORS{AllowOrigins: []string{"https://Example.com"}}, ErrTriggerInvalidCORSOrigin},
+		{TriggerTypeHTTP, &TriggerCORS{AllowOrigins: []string{"*"}, AllowMethods: []string{"get"}}, ErrTriggerInvalidMethod},
+		{TriggerTypeHTTP, &TriggerCORS{AllowOrigins: []string{"*"}, AllowHeaders: []string{"X Token"}}, ErrTriggerInvalidCORSHeader},
+		{TriggerTypeHTTP, &TriggerCORS{AllowOrigins: []string{"*"}, MaxAge: -1}, ErrTriggerInvalidCORSMaxAge},
+	} {
+		source := "/source"
+		if tc.triggerType == TriggerTypeSchedule {
+			source = "@hourly"
+		}
+		trigger := &Trigger{Name: "name", AppID: "app", FnID: "fn", Type: tc.triggerType, Source: source, CORS: tc.cors}
+		if err := trigger.Validate(); err != tc.expected {
+			t.Errorf("Test %d: expected %v, got %v", i, tc.expected, err)
+		}
+	}
+}
+
+func TestTriggerCORSAllows(t *testing.T) {
+	policy := &TriggerCORS{AllowOrigins: []string{"https://example.com"}, AllowHeaders: []string{"X-Token"}}
+	if !policy.AllowsOrigin("https://EXAMPLE.com") || policy.AllowsOrigin("https://other.com") || policy.AllowsAnyOrigin() {
+		t.Errorf("expected only the listed origin to be allowed")
+	}
+	if !policy.AllowsHeader("x-token") || !policy.AllowsHeader("Content-Type") || policy.AllowsHeader("X-Other") {
+		t.Errorf("expected only the listed and safelisted headers to be allowed")
+	}
+
+	open := &TriggerCORS{AllowOrigins: []string{"*"}, AllowHeaders: []string{"*"}}
+	if !open.AllowsOrigin("https://other.com") || !open.AllowsAnyOrigin() || !open.AllowsHeader("X-Other") {
+		t.Errorf("expected wildcards to allow anything")
+	}
+}
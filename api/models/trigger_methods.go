@@ -0,0 +1,186 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// HTTPMethods is a list of HTTP methods
+type HTTPMethods []string
+
+// Equals checks that two lists hold the same methods in the same order
+func (m HTTPMethods) Equals(other HTTPMethods) bool {
+	if len(m) != len(other) {
+		return false
+	}
+	for i := range m {
+		if m[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (m HTTPMethods) clone() HTTPMethods {
+	if m == nil {
+		return nil
+	}
+	return append(HTTPMethods{}, m...)
+}
+
+// Value implements sql.Valuer, returning a string
+func (m HTTPMethods) Value() (driver.Value, error) {
+	if len(m) < 1 {
+		return driver.Value(string("")), nil
+	}
+	var b bytes.Buffer
+	err := json.NewEncoder(&b).Encode(m)
+	return driver.Value(b.String()), err
+}
+
+// Scan implements sql.Scanner
+func (m *HTTPMethods) Scan(value interface{}) error {
+	*m = nil
+	return scanJSON(value, m, "methods")
+}
+
+// MethodFns maps HTTP methods to the ids of the fns that handle them
+type MethodFns map[string]string
+
+// Equals checks that two maps send the same methods to the same fns
+func (m MethodFns) Equals(other MethodFns) bool {
+	if len(m) != len(other) {
+		return false
+	}
+	for k, v := range m {
+		if ov, ok := other[k]; !ok || ov != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (m MethodFns) clone() MethodFns {
+	if m == nil {
+		return nil
+	}
+	clone := make(MethodFns, len(m))
+	for k, v := range m {
+		clone[k] = v
+	}
+	return clone
+}
+
+// Value implements sql.Valuer, returning a string
+func (m MethodFns) Value() (driver.Value, error) {
+	if len(m) < 1 {
+		return driver.Value(string("")), nil
+	}
+	var b bytes.Buffer
+	err := json.NewEncoder(&b).Encode(m)
+	return driver.Value(b.String()), err
+}
+
+// Scan implements sql.Scanner
+func (m *MethodFns) Scan(value interface{}) error {
+	*m = nil
+	return scanJSON(value, m, "method fns")
+}
+
+// scanJSON decodes a json column into dst, which is left as is for empty columns
+func scanJSON(value interface{}, dst interface{}, what string) error {
+	if value == nil || value == "" {
+		return nil
+	}
+	bv, err := driver.String.ConvertValue(value)
+	if err == nil {
+		var b []byte
+		switch x := bv.(type) {
+		case []byte:
+			b = x
+		case string:
+			b = []byte(x)
+		}
+
+		if len(b) > 0 {
+			return json.Unmarshal(b, dst)
+		}
+		return nil
+	}
+
+	// otherwise, return an error
+	return fmt.Errorf("%s invalid db format: %T %T value, err: %v", what, value, bv, err)
+}
+
+func (t *Trigger) validateMethods() error {
+	if len(t.Methods) == 0 && len(t.MethodFns) == 0 {
+		return nil
+	}
+	if t.Type != TriggerTypeHTTP {
+		return ErrTriggerMethodsNotHTTP
+	}
+	for _, m := range t.Methods {
+		if !validHTTPMethod(m) {
+			return ErrTriggerInvalidMethod
+		}
+	}
+	for m, fnID := range t.MethodFns {
+		if !validHTTPMethod(m) {
+			return ErrTriggerInvalidMethod
+		}
+		if fnID == "" {
+			return ErrTriggerMissingMethodFnID
+		}
+	}
+	return nil
+}
+
+// validHTTPMethod checks that m is a token, as methods are, in upper case as the standard ones are
+func validHTTPMethod(m string) bool {
+	if m == "" {
+		return false
+	}
+	for _, c := range m {
+		if !(c >= 'A' && c <= 'Z' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// FnIDForMethod returns the id of the fn that handles requests to an http
+// trigger with method, or false if the trigger does not allow method
+func (t *Trigger) FnIDForMethod(method string) (string, bool) {
+	if fnID, ok := t.MethodFns[method]; ok {
+		return fnID, true
+	}
+	if len(t.Methods) == 0 && len(t.MethodFns) == 0 {
+		return t.FnID, true
+	}
+	for _, m := range t.Methods {
+		if m == method {
+			return t.FnID, true
+		}
+	}
+	return "", false
+}
+
+// AllowedMethods lists the methods an http trigger allows in order, or none if it allows any
+func (t *Trigger) AllowedMethods() []string {
+	allowed := make(map[string]bool, len(t.Methods)+len(t.MethodFns))
+	for _, m := range t.Methods {
+		allowed[m] = true
+	}
+	for m := range t.MethodFns {
+		allowed[m] = true
+	}
+	methods := make([]string, 0, len(allowed))
+	for m := range allowed {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	return methods
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTriggerMethods(t *testing.T) {
+	open := &Trigger{FnID: "reader"}
+	restricted := &Trigger{FnID: "reader", Methods: HTTPMethods{"GET", "HEAD"}}
+	mapped := &Trigger{FnID: "reader", Methods: HTTPMethods{"GET"}, MethodFns: MethodFns{"POST": "writer", "PUT": "writer"}}
+	onlyMapped := &Trigger{FnID: "reader", MethodFns: MethodFns{"POST": "writer"}}
+
+	for i, tc := range []struct {
+		trigger *Trigger
+		method  string
+		fnID    string
+		allowed bool
+	}{
+		{open, "DELETE", "reader", true},
+		{restricted, "HEAD", "reader", true},
+		{restricted, "POST", "", false},
+		{mapped, "GET", "reader", true},
+		{mapped, "POST", "writer", true},
+		{mapped, "DELETE", "", false},
+		{onlyMapped, "POST", "writer", true},
+		{onlyMapped, "GET", "", false},
+	} {
+		fnID, allowed := tc.trigger.FnIDForMethod(tc.method)
+		if fnID != tc.fnID || allowed != tc.allowed {
+			t.Errorf("Test %d: expected %s to go to %q (%v), got %q (%v)", i, tc.method, tc.fnID, tc.allowed, fnID, allowed)
+		}
+	}
+
+	if allowed := mapped.AllowedMethods(); !reflect.DeepEqual(allowed, []string{"GET", "POST", "PUT"}) {
+		t.Errorf("expected allowed methods in order, got %v", allowed)
+	}
+	if allowed := open.AllowedMethods(); len(allowed) != 0 {
+		t.Errorf("expected no methods listed for triggers allowing any, got %v", allowed)
+	}
+}
+
+func TestValidateTriggerMethods(t *testing.T) {
+	for i, tc := range []struct {
+		trigger Trigger
+		want    error
+	}{
+		{Trigger{Type: "http", Methods: HTTPMethods{"GET", "PATCH"}}, nil},
+		{Trigger{Type: "http", MethodFns: MethodFns{"POST": "writer"}}, nil},
+		{Trigger{Type: "schedule", Methods: HTTPMethods{"GET"}}, ErrTriggerMethodsNotHTTP},
+		{Trigger{Type: "http", Methods: HTTPMethods{"get"}}, ErrTriggerInvalidMethod},
+		{Trigger{Type: "http", Methods: HTTPMethods{""}}, ErrTriggerInvalidMethod},
+		{Trigger{Type: "http", MethodFns: MethodFns{"GET /": "writer"}}, ErrTriggerInvalidMethod},
+		{Trigger{Type: "http", MethodFns: MethodFns{"POST": ""}}, ErrTriggerMissingMethodFnID},
+	} {
+		if err := tc.trigger.validateMethods(); err != tc.want {
+			t.Errorf("Test %d: expected %v, got %v", i, tc.want, err)
+		}
+	}
+}
+
+func TestUpdateTriggerMethods(t *testing.T) {
+	trigger := &Trigger{FnID: "reader", Methods: HTTPMethods{"GET"}, MethodFns: MethodFns{"POST": "writer"}}
+
+	trigger.Update(&Trigger{Name: "renamed"})
+	if !trigger.Methods.Equals(HTTPMethods{"GET"}) || !trigger.MethodFns.Equals(MethodFns{"POST": "writer"}) {
+		t.Fatalf("expected methods to be kept when not patched, got %v %v", trigger.Methods, trigger.MethodFns)
+	}
+
+	trigger.Update(&Trigger{Methods: HTTPMethods{}, MethodFns: MethodFns{"PUT": "writer"}})
+	if len(trigger.Methods) != 0 || !trigger.MethodFns.Equals(MethodFns{"PUT": "writer"}) {
+		t.Fatalf("expected methods to be replaced, got %v %v", trigger.Methods, trigger.MethodFns)
+	}
+}
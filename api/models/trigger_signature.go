@@ -0,0 +1,124 @@
+package models
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+// TriggerSignature is how the senders of requests to an http trigger sign
+// them, as webhooks are: an HMAC of the body of the request with a shared
+// secret, in a header. Requests to the trigger that aren't signed so are
+// refused by the front end before any fn is called.
+type TriggerSignature struct {
+	// Secret is the key the HMAC is made with. Like secret config it is stored
+	// encrypted and redacted in API responses.
+	Secret string `json:"secret"`
+	// Header is the header the signature is sent in, such as X-Hub-Signature-256
+	Header string `json:"header"`
+	// Algorithm is the hash the HMAC is made with, one of sha1, sha256 or sha512
+	Algorithm string `json:"algorithm"`
+	// Prefix is sent before the signature in the header, such as sha256=
+	Prefix string `json:"prefix,omitempty"`
+	// Encoding is how the signature is encoded, hex if empty or base64
+	Encoding string `json:"encoding,omitempty"`
+}
+
+var signatureAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// Equals checks that two signatures are the same, either may be nil
+func (s *TriggerSignature) Equals(other *TriggerSignature) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return *s == *other
+}
+
+func (s *TriggerSignature) clone() *TriggerSignature {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	return &clone
+}
+
+func (s *TriggerSignature) empty() bool {
+	return *s == TriggerSignature{}
+}
+
+// Value implements sql.Valuer, returning a string, a nil signature is stored as NULL
+func (s TriggerSignature) Value() (driver.Value, error) {
+	var b bytes.Buffer
+	err := json.NewEncoder(&b).Encode(s)
+	return driver.Value(b.String()), err
+}
+
+// Scan implements sql.Scanner
+func (s *TriggerSignature) Scan(value interface{}) error {
+	*s = TriggerSignature{}
+	return scanJSON(value, s, "signature")
+}
+
+func (t *Trigger) validateSignature() error {
+	s := t.Signature
+	if s == nil {
+		return nil
+	}
+	if t.Type != TriggerTypeHTTP {
+		return ErrTriggerSignatureNotHTTP
+	}
+	if s.Secret == "" {
+		return ErrTriggerMissingSignatureSecret
+	}
+	if !ValidHeaderName(s.Header) {
+		return ErrTriggerInvalidSignatureHeader
+	}
+	if _, ok := signatureAlgorithms[s.Algorithm]; !ok {
+		return ErrTriggerInvalidSignatureAlgorithm
+	}
+	if s.Encoding != "" && s.Encoding != "hex" && s.Encoding != "base64" {
+		return ErrTriggerInvalidSignatureEncoding
+	}
+	return nil
+}
+
+// Verify checks that the signature in header is the HMAC of body
+func (s *TriggerSignature) Verify(header http.Header, body []byte) bool {
+	newHash, ok := signatureAlgorithms[s.Algorithm]
+	if !ok {
+		return false
+	}
+	sent := header.Get(s.Header)
+	if !strings.HasPrefix(sent, s.Prefix) {
+		return false
+	}
+	sent = strings.TrimPrefix(sent, s.Prefix)
+
+	var sig []byte
+	var err error
+	if s.Encoding == "base64" {
+		sig, err = base64.StdEncoding.DecodeString(sent)
+	} else {
+		sig, err = hex.DecodeString(sent)
+	}
+	if err != nil || len(sig) == 0 {
+		return false
+	}
+
+	mac := hmac.New(newHash, []byte(s.Secret))
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"testing"
+)
+
+func TestValidateTriggerSignature(t *testing.T) {
+	for i, tc := range []struct {
+		triggerType string
+		signature   *TriggerSignature
+		expected    error
+	}{
+		{TriggerTypeHTTP, nil, nil},
+		{TriggerTypeHTTP, &TriggerSignature{Secret: "s", Header: "X-Hub-Signature-256", Algorithm: "sha256", Prefix: "sha256="}, nil},
+		{TriggerTypeHTTP, &TriggerSignature{Secret: "s", Header: "X-Signature", Algorithm: "sha512", Encoding: "base64"}, nil},
+		{TriggerTypeSchedule, &TriggerSignature{Secret: "s", Header: "X-Signature", Algorithm: "sha256"}, ErrTriggerSignatureNotHTTP},
+		{TriggerTypeHTTP, &TriggerSignature{Header: "X-Signature", Algorithm: "sha256"}, ErrTriggerMissingSignatureSecret},
+		{TriggerTypeHTTP, &TriggerSignature{Secret: "s", Algorithm: "sha256"}, ErrTriggerInvalidSignatureHeader},
+		{TriggerTypeHTTP, &TriggerSignature{Secret: "s", Header: "X Signature", Algorithm: "sha256"}, ErrTriggerInvalidSignatureHeader},
+		{TriggerTypeHTTP, &TriggerSignature{Secret: "s", Header: "X-Signature", Algorithm: "md5"}, ErrTriggerInvalidSignatureAlgorithm},
+		{TriggerTypeHTTP, &TriggerSignature{Secret: "s", Header: "X-Signature", Algorithm: "sha256", Encoding: "base32"}, ErrTriggerInvalidSignatureEncoding},
+	} {
+		source := "/source"
+		if tc.triggerType == TriggerTypeSchedule {
+			source = "@hourly"
+		}
+		trigger := &Trigger{Name: "name", AppID: "app", FnID: "fn", Type: tc.triggerType, Source: source, Signature: tc.signature}
+		if err := trigger.Validate(); err != tc.expected {
+			t.Errorf("Test %d: expected %v, got %v", i, tc.expected, err)
+		}
+	}
+}
+
+func TestTriggerSignatureVerify(t *testing.T) {
+	body := []byte(`{"action":"opened"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	sum := mac.Sum(nil)
+
+	hexSig := &TriggerSignature{Secret: "secret", Header: "X-Hub-Signature-256", Algorithm: "sha256", Prefix: "sha256="}
+	b64Sig := &TriggerSignature{Secret: "secret", Header: "X-Signature", Algorithm: "sha256", Encoding: "base64"}
+
+	for i, tc := range []struct {
+		signature *TriggerSignature
+		header    string
+		value     string
+		body      []byte
+		expected  bool
+	}{
+		{hexSig, "X-Hub-Signature-256", "sha256=" + hex.EncodeToString(sum), body, true},
+		{hexSig, "X-Hub-Signature-256", "sha256=" + hex.EncodeToString(sum), []byte(`{"action":"closed"}`), false},
+		{hexSig, "X-Hub-Signature-256", hex.EncodeToString(sum), body, false},
+		{hexSig, "X-Hub-Signature-256", "sha256=", body, false},
+		{hexSig, "X-Other", "sha256=" + hex.EncodeToString(sum), body, false},
+		{b64Sig, "X-Signature", base64.StdEncoding.EncodeToString(sum), body, true},
+		{b64Sig, "X-Signature", hex.EncodeToString(sum), body, false},
+		{&TriggerSignature{Secret: "other", Header: "X-Signature", Algorithm: "sha256"}, "X-Signature", hex.EncodeToString(sum), body, false},
+	} {
+		header := make(http.Header)
+		header.Set(tc.header, tc.value)
+		if got := tc.signature.Verify(header, tc.body); got != tc.expected {
+			t.Errorf("Test %d: expected %v, got %v", i, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"strings"
+)
+
+// The source of an HTTP trigger may be a template, matching many paths.
+// Segments such as {id} match any one non empty segment of a path, and a last
+// segment such as *rest matches the remainder of the path, however many
+// segments that is. Values matched by a template are named after its segments.
+
+type sourceSegmentKind int
+
+// ordered from least to most specific
+const (
+	wildcardSegment sourceSegmentKind = iota
+	paramSegment
+	literalSegment
+)
+
+type sourceSegment struct {
+	kind  sourceSegmentKind
+	value string // the literal text, or the name of the param or wildcard
+}
+
+// IsTriggerSourceTemplate tells if source has params or a wildcard in it
+func IsTriggerSourceTemplate(source string) bool {
+	return strings.Contains(source, "{") || strings.Contains(source, "/*")
+}
+
+func parseSourceTemplate(source string) ([]sourceSegment, error) {
+	parts := strings.Split(strings.TrimPrefix(source, "/"), "/")
+	segments := make([]sourceSegment, len(parts))
+	names := make(map[string]bool)
+	for i, p := range parts {
+		seg := sourceSegment{kind: literalSegment, value: p}
+		switch {
+		case strings.HasPrefix(p, "*"):
+			if i != len(parts)-1 {
+				return nil, ErrTriggerInvalidSourceTemplate
+			}
+			seg = sourceSegment{kind: wildcardSegment, value: p[1:]}
+		case strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}"):
+			seg = sourceSegment{kind: paramSegment, value: p[1 : len(p)-1]}
+		case strings.ContainsAny(p, "{}"):
+			return nil, ErrTriggerInvalidSourceTemplate
+		}
+
+		if seg.kind != literalSegment {
+			if !validSourceParamName(seg.value) || names[seg.value] {
+				return nil, ErrTriggerInvalidSourceTemplate
+			}
+			names[seg.value] = true
+		}
+		segments[i] = seg
+	}
+	return segments, nil
+}
+
+func validSourceParamName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchTriggerSource matches the path of a request to the source of an http
+// trigger, returning the values of the params and wildcard of the source if
+// it is a template.
+func MatchTriggerSource(source, path string) (map[string]string, bool) {
+	if !IsTriggerSourceTemplate(source) {
+		return nil, source == path
+	}
+	segments, err := parseSourceTemplate(source)
+	if err != nil {
+		return nil, false
+	}
+	return matchSegments(segments, path)
+}
+
+func matchSegments(segments []sourceSegment, path string) (map[string]string, bool) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, false
+	}
+	parts := strings.Split(path[1:], "/")
+	values := make(map[string]string)
+	for i, seg := range segments {
+		if seg.kind == wildcardSegment {
+			if len(parts) <= i {
+				return nil, false
+			}
+			values[seg.value] = strings.Join(parts[i:], "/")
+			return values, true
+		}
+		if len(parts) <= i {
+			return nil, false
+		}
+		switch seg.kind {
+		case literalSegment:
+			if parts[i] != seg.value {
+				return nil, false
+			}
+		case paramSegment:
+			if parts[i] == "" {
+				return nil, false
+			}
+			values[seg.value] = parts[i]
+		}
+	}
+	if len(parts) != len(segments) {
+		return nil, false
+	}
+	return values, true
+}
+
+// BestTriggerSourceMatch finds the trigger whose source template matches path
+// most specifically: at the first segment where templates differ, literal
+// segments win over params, and params over wildcards. Triggers whose sources
+// are not templates are ignored, as they are found by exact matches first.
+func BestTriggerSourceMatch(triggers []*Trigger, path string) *Trigger {
+	var best *Trigger
+	var bestSegments []sourceSegment
+	for _, t := range triggers {
+		if !IsTriggerSourceTemplate(t.Source) {
+			continue
+		}
+		segments, err := parseSourceTemplate(t.Source)
+		if err != nil {
+			continue
+		}
+		if _, ok := matchSegments(segments, path); !ok {
+			continue
+		}
+		if best == nil || moreSpecific(segments, bestSegments) || (!moreSpecific(bestSegments, segments) && t.Source < best.Source) {
+			best, bestSegments = t, segments
+		}
+	}
+	return best
+}
+
+func moreSpecific(a, b []sourceSegment) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i].kind != b[i].kind {
+			return a[i].kind > b[i].kind
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchTriggerSource(t *testing.T) {
+	for i, tc := range []struct {
+		source string
+		path   string
+		match  bool
+		params map[string]string
+	}{
+		{"/users", "/users", true, nil},
+		{"/users", "/users/1", false, nil},
+		{"/users/{id}", "/users/1", true, map[string]string{"id": "1"}},
+		{"/users/{id}", "/users/", false, nil},
+		{"/users/{id}", "/users/1/posts", false, nil},
+		{"/users/{id}/posts/{post}", "/users/1/posts/2", true, map[string]string{"id": "1", "post": "2"}},
+		{"/files/*rest", "/files/a/b.txt", true, map[string]string{"rest": "a/b.txt"}},
+		{"/files/*rest", "/files/", true, map[string]string{"rest": ""}},
+		{"/files/*rest", "/files", false, nil},
+		{"/*all", "/anything/at/all", true, map[string]string{"all": "anything/at/all"}},
+		// broken templates match nothing
+		{"/users/{id}/{id}", "/users/1/2", false, nil},
+	} {
+		params, match := MatchTriggerSource(tc.source, tc.path)
+		if match != tc.match {
+			t.Errorf("Test %d: expected %q matching %q to be %v", i, tc.source, tc.path, tc.match)
+			continue
+		}
+		if match && len(tc.params) > 0 && !reflect.DeepEqual(params, tc.params) {
+			t.Errorf("Test %d: expected params %v, got %v", i, tc.params, params)
+		}
+	}
+}
+
+func TestValidateTriggerSourceTemplate(t *testing.T) {
+	for _, source := range []string{"/users/{id}", "/users/{user-id}/*rest", "/*rest", "/a/{b_c}/d"} {
+		if err := validateHTTPTriggerSource(source); err != nil {
+			t.Errorf("expected %q to be valid, got %v", source, err)
+		}
+	}
+	for _, source := range []string{"/users/{}", "/users/{id", "/users/x{id}", "/*rest/more", "/files/*", "/{a}/{a}", "/{a.b}"} {
+		if err := validateHTTPTriggerSource(source); err != ErrTriggerInvalidSourceTemplate {
+			t.Errorf("expected %q to be an invalid template, got %v", source, err)
+		}
+	}
+}
+
+func TestBestTriggerSourceMatch(t *testing.T) {
+	triggers := []*Trigger{
+		{ID: "wildcard", Source: "/users/*rest"},
+		{ID: "param", Source: "/users/{id}"},
+		{ID: "literal", Source: "/users/me"},
+		{ID: "nested", Source: "/users/{id}/posts"},
+		{ID: "other", Source: "/users/{name}"},
+	}
+	for path, want := range map[string]string{
+		"/users/1":       "param", // {id} sorts before {name}
+		"/users/1/posts": "nested",
+		"/users/1/likes": "wildcard",
+		"/teams/1":       "",
+	} {
+		got := BestTriggerSourceMatch(triggers, path)
+		if (got == nil && want != "") || (got != nil && got.ID != want) {
+			t.Errorf("expected %q to be matched by %q, got %+v", path, want, got)
+		}
+	}
+
+	// literal sources are left to exact matches
+	if got := BestTriggerSourceMatch(triggers, "/users/me"); got == nil || got.ID != "param" {
+		t.Errorf("expected exact sources to be ignored, got %+v", got)
+	}
+}
@@ -2,7 +2,10 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
+	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/leanovate/gopter"
@@ -45,7 +48,11 @@ func TestTriggerListJsonMarshalling(t *testing.T) {
 }
 
 var httpTrigger = &Trigger{Name: "name", AppID: "foo", FnID: "bar", Type: "http", Source: "/baz"}
+var scheduleTrigger = &Trigger{Name: "name", AppID: "foo", FnID: "bar", Type: "schedule", Source: "*/5 * * * *"}
+var invalidScheduleTrigger = &Trigger{Name: "name", AppID: "foo", FnID: "bar", Type: "schedule", Source: "/baz"}
 var invalidTrigger = &Trigger{Name: "name", AppID: "foo", FnID: "bar", Type: "error", Source: "/baz"}
+var templateTrigger = &Trigger{Name: "name", AppID: "foo", FnID: "bar", Type: "http", Source: "/users/{id}/*rest"}
+var invalidTemplateTrigger = &Trigger{Name: "name", AppID: "foo", FnID: "bar", Type: "http", Source: "/users/*rest/{id}"}
 
 var triggerValidateCases = []struct {
 	val   *Trigger
@@ -54,6 +61,10 @@ var triggerValidateCases = []struct {
 	{val: &Trigger{}, valid: false},
 	{val: invalidTrigger, valid: false},
 	{val: httpTrigger, valid: true},
+	{val: scheduleTrigger, valid: true},
+	{val: invalidScheduleTrigger, valid: false},
+	{val: templateTrigger, valid: true},
+	{val: invalidTemplateTrigger, valid: false},
 }
 
 func TestTriggerValidate(t *testing.T) {
@@ -68,6 +79,43 @@ func TestTriggerValidate(t *testing.T) {
 	}
 }
 
+func TestRegisterTriggerType(t *testing.T) {
+	errBadQueue := NewAPIError(http.StatusBadRequest, errors.New("bad queue"))
+	err := RegisterTriggerType("test-queue", func(source string) error {
+		if !strings.HasPrefix(source, "queue:") {
+			return errBadQueue
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error registering trigger type: %v", err)
+	}
+
+	if err := RegisterTriggerType("test-queue", nil); err != ErrTriggerTypeExists {
+		t.Errorf("expected registering a trigger type twice to fail, got %v", err)
+	}
+	if err := RegisterTriggerType(TriggerTypeHTTP, nil); err != ErrTriggerTypeExists {
+		t.Errorf("expected registering a builtin trigger type to fail, got %v", err)
+	}
+
+	found := false
+	for _, tt := range ValidTriggerTypes() {
+		found = found || tt == "test-queue"
+	}
+	if !found {
+		t.Errorf("expected registered trigger type in %v", ValidTriggerTypes())
+	}
+
+	queueTrigger := &Trigger{Name: "name", AppID: "foo", FnID: "bar", Type: "test-queue", Source: "queue:orders"}
+	if err := queueTrigger.Validate(); err != nil {
+		t.Errorf("expected trigger of registered type to be valid, got %v", err)
+	}
+	queueTrigger.Source = "/orders"
+	if err := queueTrigger.Validate(); err != errBadQueue {
+		t.Errorf("expected source of registered type to be validated, got %v", err)
+	}
+}
+
 func triggerReflectType() reflect.Type {
 	trigger := Trigger{}
 	return reflect.TypeOf(trigger)
@@ -79,11 +127,16 @@ func triggerFieldGenerators(t *testing.T) map[string]gopter.Gen {
 	fieldGens["Name"] = gen.AlphaString()
 	fieldGens["AppID"] = gen.AlphaString()
 	fieldGens["FnID"] = gen.AlphaString()
+	fieldGens["Alias"] = gen.OneConstOf("", "prod", "staging")
 	fieldGens["CreatedAt"] = datetimeGenerator()
 	fieldGens["UpdatedAt"] = datetimeGenerator()
 	fieldGens["Type"] = gen.AlphaString()
 	fieldGens["Source"] = gen.AlphaString()
 	fieldGens["Annotations"] = annotationGenerator()
+	fieldGens["Methods"] = gen.OneConstOf(HTTPMethods{"GET"}, HTTPMethods{"GET", "POST"}, HTTPMethods(nil))
+	fieldGens["MethodFns"] = gen.OneConstOf(MethodFns{"POST": "fn1"}, MethodFns{"POST": "fn2"}, MethodFns(nil))
+	fieldGens["Signature"] = gen.OneConstOf(&TriggerSignature{Secret: "s1", Header: "X-Signature", Algorithm: "sha256"}, &TriggerSignature{Secret: "s2", Header: "X-Signature", Algorithm: "sha1"}, (*TriggerSignature)(nil))
+	fieldGens["CORS"] = gen.OneConstOf(&TriggerCORS{AllowOrigins: []string{"*"}}, &TriggerCORS{AllowOrigins: []string{"https://example.com"}, MaxAge: 60}, (*TriggerCORS)(nil))
 
 	triggerFieldCount := triggerReflectType().NumField()
 
@@ -0,0 +1,101 @@
+package mqs
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/fnproject/fn/api/models"
+)
+
+type memoryProvider int
+
+func init() {
+	Register(memoryProvider(0))
+}
+
+func (memoryProvider) Supports(u string) bool {
+	uri, err := url.Parse(u)
+	return err == nil && uri.Scheme == "memory"
+}
+
+func (memoryProvider) New(ctx context.Context, u string) (models.MQ, error) {
+	return NewMemoryMQ(), nil
+}
+
+func (memoryProvider) String() string {
+	return "memory"
+}
+
+type memoryItem struct {
+	call          *models.Call
+	due           time.Time
+	reservedUntil time.Time
+}
+
+type memoryMQ struct {
+	mu    sync.Mutex
+	items []*memoryItem // in the order they were pushed
+}
+
+// NewMemoryMQ creates a queue which is only as durable as the process holding
+// it, for single node deployments and tests. Like other MQs it is validated
+// once wrapped, see Wrap.
+func NewMemoryMQ() models.MQ {
+	return &memoryMQ{}
+}
+
+func (m *memoryMQ) Push(ctx context.Context, call *models.Call) (*models.Call, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *call
+	item := &memoryItem{
+		call: &stored,
+		due:  time.Now().Add(time.Duration(call.Delay) * time.Second),
+	}
+	for i, it := range m.items {
+		if it.call.ID == call.ID {
+			m.items[i] = item
+			return call, nil
+		}
+	}
+	m.items = append(m.items, item)
+	return call, nil
+}
+
+func (m *memoryMQ) Reserve(ctx context.Context, timeout time.Duration) (*models.Call, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	var next *memoryItem
+	for _, it := range m.items {
+		if it.due.After(now) || it.reservedUntil.After(now) {
+			continue
+		}
+		if next == nil || it.due.Before(next.due) {
+			next = it
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+	next.reservedUntil = now.Add(timeout)
+	call := *next.call
+	return &call, nil
+}
+
+func (m *memoryMQ) Delete(ctx context.Context, call *models.Call) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, it := range m.items {
+		if it.call.ID == call.ID {
+			m.items = append(m.items[:i], m.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package mqs
+
+import (
+	"testing"
+
+	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/api/mqs/mqstest"
+)
+
+func TestMemoryMQ(t *testing.T) {
+	mqstest.RunAllTests(t, func(t *testing.T) models.MQ {
+		return Wrap(NewMemoryMQ())
+	})
+}
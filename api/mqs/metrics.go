@@ -0,0 +1,35 @@
+package mqs
+
+import (
+	"context"
+	"time"
+
+	"github.com/fnproject/fn/api/models"
+	"go.opencensus.io/trace"
+)
+
+func metricMQ(mq models.MQ) models.MQ {
+	return &metricmq{mq}
+}
+
+type metricmq struct {
+	mq models.MQ
+}
+
+func (m *metricmq) Push(ctx context.Context, call *models.Call) (*models.Call, error) {
+	ctx, span := trace.StartSpan(ctx, "mq_push")
+	defer span.End()
+	return m.mq.Push(ctx, call)
+}
+
+func (m *metricmq) Reserve(ctx context.Context, timeout time.Duration) (*models.Call, error) {
+	ctx, span := trace.StartSpan(ctx, "mq_reserve")
+	defer span.End()
+	return m.mq.Reserve(ctx, timeout)
+}
+
+func (m *metricmq) Delete(ctx context.Context, call *models.Call) error {
+	ctx, span := trace.StartSpan(ctx, "mq_delete")
+	defer span.End()
+	return m.mq.Delete(ctx, call)
+}
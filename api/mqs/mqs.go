@@ -0,0 +1,46 @@
+// Package mqs provides the queues detached calls are put on until they run.
+package mqs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/models"
+	"github.com/sirupsen/logrus"
+)
+
+// New creates an MQ from the specified URL
+func New(ctx context.Context, mqURL string) (models.MQ, error) {
+	log := common.Logger(ctx)
+	log.WithFields(logrus.Fields{"mq": common.MaskPassword(mqURL)}).Debug("creating new message queue")
+
+	for _, provider := range providers {
+		if provider.Supports(mqURL) {
+			return provider.New(ctx, mqURL)
+		}
+	}
+	return nil, fmt.Errorf("no message queue provider found for url %s", mqURL)
+}
+
+// Wrap adds argument validation and tracing to an MQ
+func Wrap(mq models.MQ) models.MQ {
+	return metricMQ(newValidator(mq))
+}
+
+// Provider is a message queue provider
+type Provider interface {
+	fmt.Stringer
+	// Supports indicates if this provider can handle a given message queue.
+	Supports(url string) bool
+	// New creates a new message queue from the specified URL
+	New(ctx context.Context, url string) (models.MQ, error)
+}
+
+var providers []Provider
+
+// Register globally registers a message queue provider
+func Register(provider Provider) {
+	logrus.Infof("Registering message queue provider '%s'", provider)
+	providers = append(providers, provider)
+}
@@ -0,0 +1,166 @@
+package mqstest
+
+// Message queue correctness tests -
+// These tests run validation tests on an underlying message queue implementation and can be re-used for new queues.
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fnproject/fn/api/id"
+	"github.com/fnproject/fn/api/models"
+)
+
+// MQFunc provides an instance of a message queue, it should be empty
+type MQFunc func(*testing.T) models.MQ
+
+func newCall() *models.Call {
+	return &models.Call{
+		ID:        id.New().String(),
+		FnID:      id.New().String(),
+		AppID:     id.New().String(),
+		TriggerID: id.New().String(),
+		Type:      models.TypeDetached,
+		Method:    http.MethodPost,
+		URL:       "http://localhost:8080/invoke/fn",
+		Headers:   http.Header{"Content-Type": []string{"application/octet-stream"}},
+		Payload:   "\x00\xffbinary\nbody",
+	}
+}
+
+// RunAllTests runs the message queue test suite against the queue provided by mqf
+func RunAllTests(t *testing.T, mqf MQFunc) {
+	ctx := context.Background()
+
+	t.Run("push and reserve", func(t *testing.T) {
+		mq := mqf(t)
+		call := newCall()
+		if _, err := mq.Push(ctx, call); err != nil {
+			t.Fatalf("failed to push call: %v", err)
+		}
+
+		got, err := mq.Reserve(ctx, time.Minute)
+		if err != nil {
+			t.Fatalf("failed to reserve call: %v", err)
+		}
+		if got == nil {
+			t.Fatal("expected to reserve the pushed call, got nothing")
+		}
+		if got.ID != call.ID || got.FnID != call.FnID || got.AppID != call.AppID || got.TriggerID != call.TriggerID {
+			t.Fatalf("expected reserved call to match pushed call, got %+v", got)
+		}
+		if got.Payload != call.Payload || got.Method != call.Method || got.URL != call.URL || got.Headers.Get("Content-Type") != "application/octet-stream" {
+			t.Fatalf("expected the request of the call to be kept, got %+v", got)
+		}
+
+		// reserved calls aren't handed out twice
+		again, err := mq.Reserve(ctx, time.Minute)
+		if err != nil {
+			t.Fatalf("failed to reserve call: %v", err)
+		}
+		if again != nil {
+			t.Fatalf("expected no call while the only one is reserved, got %s", again.ID)
+		}
+	})
+
+	t.Run("reserve empty queue", func(t *testing.T) {
+		mq := mqf(t)
+		got, err := mq.Reserve(ctx, time.Minute)
+		if err != nil {
+			t.Fatalf("failed to reserve call: %v", err)
+		}
+		if got != nil {
+			t.Fatalf("expected nothing from an empty queue, got %s", got.ID)
+		}
+	})
+
+	t.Run("reserve oldest first", func(t *testing.T) {
+		mq := mqf(t)
+		first, second := newCall(), newCall()
+		if _, err := mq.Push(ctx, first); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(2 * time.Millisecond)
+		if _, err := mq.Push(ctx, second); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := mq.Reserve(ctx, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got == nil || got.ID != first.ID {
+			t.Fatalf("expected the first call pushed to be reserved first, got %v", got)
+		}
+	})
+
+	t.Run("delayed calls wait", func(t *testing.T) {
+		mq := mqf(t)
+		call := newCall()
+		call.Delay = 60
+		if _, err := mq.Push(ctx, call); err != nil {
+			t.Fatal(err)
+		}
+		got, err := mq.Reserve(ctx, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != nil {
+			t.Fatalf("expected delayed call not to be due yet, got %s", got.ID)
+		}
+	})
+
+	t.Run("lapsed reservations are handed out again", func(t *testing.T) {
+		mq := mqf(t)
+		call := newCall()
+		if _, err := mq.Push(ctx, call); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := mq.Reserve(ctx, time.Millisecond); err != nil || got == nil {
+			t.Fatalf("expected to reserve call, got %v %v", got, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		got, err := mq.Reserve(ctx, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got == nil || got.ID != call.ID {
+			t.Fatalf("expected call to be handed out again, got %v", got)
+		}
+	})
+
+	t.Run("deleted calls are gone", func(t *testing.T) {
+		mq := mqf(t)
+		call := newCall()
+		if _, err := mq.Push(ctx, call); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := mq.Reserve(ctx, time.Millisecond); err != nil || got == nil {
+			t.Fatalf("expected to reserve call, got %v %v", got, err)
+		}
+		if err := mq.Delete(ctx, call); err != nil {
+			t.Fatalf("failed to delete call: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		got, err := mq.Reserve(ctx, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != nil {
+			t.Fatalf("expected deleted call to be gone, got %s", got.ID)
+		}
+	})
+
+	t.Run("missing call id", func(t *testing.T) {
+		mq := mqf(t)
+		if _, err := mq.Push(ctx, &models.Call{FnID: "fn"}); err != models.ErrDatastoreEmptyCallID {
+			t.Fatalf("expected empty call id error, got %v", err)
+		}
+		if err := mq.Delete(ctx, &models.Call{}); err != models.ErrDatastoreEmptyCallID {
+			t.Fatalf("expected empty call id error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package mqs
+
+import (
+	"context"
+
+	"github.com/fnproject/fn/api/models"
+)
+
+// newValidator returns a models.MQ which validates certain arguments before delegating to mq.
+func newValidator(mq models.MQ) models.MQ {
+	return &validator{mq}
+}
+
+type validator struct {
+	models.MQ
+}
+
+func (v *validator) Push(ctx context.Context, call *models.Call) (*models.Call, error) {
+	if call.ID == "" {
+		return nil, models.ErrDatastoreEmptyCallID
+	}
+	if call.FnID == "" {
+		return nil, models.ErrDatastoreEmptyFnID
+	}
+	return v.MQ.Push(ctx, call)
+}
+
+func (v *validator) Delete(ctx context.Context, call *models.Call) error {
+	if call.ID == "" {
+		return models.ErrDatastoreEmptyCallID
+	}
+	return v.MQ.Delete(ctx, call)
+}
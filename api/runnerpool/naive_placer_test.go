@@ -51,6 +51,9 @@ type dummyRunner struct {
 func (o *dummyRunner) Status(ctx context.Context) (*RunnerStatus, error) { return nil, nil }
 func (o *dummyRunner) Close(ctx context.Context) error                   { return nil }
 func (o *dummyRunner) Address() string                                   { return "" }
+func (o *dummyRunner) Cancel(ctx context.Context, callID string) (bool, error) {
+	return false, nil
+}
 func (o *dummyRunner) TryExec(ctx context.Context, call RunnerCall) (bool, error) {
 	args := o.Called(ctx, call)
 	return args.Bool(0), args.Error(1)
@@ -51,6 +51,7 @@ type RunnerStatus struct {
 // Runner is the interface to invoke the execution of a function call on a specific runner
 type Runner interface {
 	TryExec(ctx context.Context, call RunnerCall) (bool, error)
+	Cancel(ctx context.Context, callID string) (bool, error)
 	Status(ctx context.Context) (*RunnerStatus, error)
 	Close(ctx context.Context) error
 	Address() string
@@ -0,0 +1,296 @@
+// Package scheduler fires schedule triggers, it is the dispatcher of the
+// builtin schedule trigger type. Any number of nodes sharing a datastore may
+// run a scheduler, only the one holding the scheduler lease fires triggers at
+// any one time.
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/id"
+	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/fnext"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// LeaseName is the name of the lease held by the node firing schedules
+	LeaseName = "scheduler"
+
+	// DefaultLeaseTTL is how long a scheduler keeps the lease without renewing
+	// it, and so how long schedules may go unfired when the leader dies.
+	DefaultLeaseTTL = 30 * time.Second
+
+	// DefaultRefreshInterval is how often the leader reloads schedule triggers
+	DefaultRefreshInterval = 30 * time.Second
+
+	// ScheduledTimeHeader carries the time a call was scheduled for, which may
+	// be a little before the time it is made
+	ScheduledTimeHeader = "Fn-Scheduled-Time"
+)
+
+// how many apps and triggers are fetched at once when loading triggers
+const pageSize = 100
+
+// discardResponseWriter drops the responses of scheduled calls, nobody is waiting for them
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header         { return w.header }
+func (w *discardResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardResponseWriter) WriteHeader(int)             {}
+
+// TriggerSource is where a scheduler reads schedule triggers from, a
+// datastore or, on LB nodes, the API nodes
+type TriggerSource interface {
+	GetApps(ctx context.Context, filter *models.AppFilter) (*models.AppList, error)
+	GetTriggers(ctx context.Context, filter *models.TriggerFilter) (*models.TriggerList, error)
+}
+
+// Option configures a Scheduler
+type Option func(*Scheduler)
+
+// WithLeaseTTL sets how long the lease is held for between renewals
+func WithLeaseTTL(ttl time.Duration) Option {
+	return func(s *Scheduler) {
+		s.leaseTTL = ttl
+	}
+}
+
+// WithRefreshInterval sets how often schedule triggers are reloaded
+func WithRefreshInterval(d time.Duration) Option {
+	return func(s *Scheduler) {
+		s.refreshInterval = d
+	}
+}
+
+// Scheduler invokes the fns of schedule triggers as detached calls, each time
+// their schedule comes round. Ticks missed while no scheduler was leading
+// are not made up for.
+type Scheduler struct {
+	ds      TriggerSource
+	leaser  models.Leaser
+	invoker fnext.Invoker
+	holder  string
+
+	leaseTTL        time.Duration
+	refreshInterval time.Duration
+
+	// only touched by the run loop
+	entries map[string]*entry
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	calls  sync.WaitGroup
+}
+
+type entry struct {
+	trigger  *models.Trigger
+	schedule *models.Schedule
+	next     time.Time
+}
+
+var _ fnext.TriggerDispatcher = new(Scheduler)
+
+// New creates a Scheduler, which does nothing until started. If leaser is
+// nil the scheduler assumes it is the only one and always leads.
+func New(ds TriggerSource, leaser models.Leaser, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		ds:              ds,
+		leaser:          leaser,
+		holder:          id.New().String(),
+		leaseTTL:        DefaultLeaseTTL,
+		refreshInterval: DefaultRefreshInterval,
+		entries:         make(map[string]*entry),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Start implements fnext.TriggerDispatcher, it runs the scheduler in the
+// background until ctx is done or Stop is called
+func (s *Scheduler) Start(ctx context.Context, invoker fnext.Invoker) error {
+	s.invoker = invoker
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	go s.run(ctx)
+	return nil
+}
+
+// Stop implements fnext.TriggerDispatcher, it gives up the lease and waits
+// for any calls the scheduler made to finish
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+	<-s.done
+	s.calls.Wait()
+	return nil
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	defer close(s.done)
+	log := common.Logger(ctx).WithField("scheduler", s.holder)
+
+	var leader bool
+	var renewAt, refreshAt time.Time
+	for {
+		now := time.Now()
+
+		if !now.Before(renewAt) {
+			wasLeader := leader
+			leader = s.acquire(ctx, log)
+			renewAt = now.Add(s.leaseTTL / 3)
+			if leader && !wasLeader {
+				log.Info("scheduler elected leader, firing schedule triggers")
+				refreshAt = now
+			} else if !leader && wasLeader {
+				log.Info("scheduler lost leadership")
+				s.entries = make(map[string]*entry)
+			}
+		}
+
+		wake := renewAt
+		if leader {
+			if !now.Before(refreshAt) {
+				if err := s.refresh(ctx, now); err != nil {
+					log.WithError(err).Error("failed to load schedule triggers")
+				}
+				refreshAt = now.Add(s.refreshInterval)
+			}
+			s.fire(ctx, now)
+
+			if refreshAt.Before(wake) {
+				wake = refreshAt
+			}
+			for _, e := range s.entries {
+				if !e.next.IsZero() && e.next.Before(wake) {
+					wake = e.next
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if leader && s.leaser != nil {
+				if err := s.leaser.ReleaseLease(common.BackgroundContext(ctx), LeaseName, s.holder); err != nil {
+					log.WithError(err).Error("failed to release scheduler lease")
+				}
+			}
+			return
+		case <-time.After(time.Until(wake)):
+		}
+	}
+}
+
+func (s *Scheduler) acquire(ctx context.Context, log logrus.FieldLogger) bool {
+	if s.leaser == nil {
+		return true
+	}
+	ok, err := s.leaser.AcquireLease(ctx, LeaseName, s.holder, s.leaseTTL)
+	if err != nil {
+		// we can't tell if someone else has it, rather miss a tick than double fire
+		log.WithError(err).Error("failed to acquire scheduler lease")
+		return false
+	}
+	return ok
+}
+
+// refresh loads all schedule triggers, keeping the next tick of those whose
+// schedule has not changed
+func (s *Scheduler) refresh(ctx context.Context, now time.Time) error {
+	triggers, err := s.scheduleTriggers(ctx)
+	if err != nil {
+		return err
+	}
+
+	entries := make(map[string]*entry, len(triggers))
+	for _, t := range triggers {
+		if e, ok := s.entries[t.ID]; ok && e.trigger.Source == t.Source {
+			e.trigger = t
+			entries[t.ID] = e
+			continue
+		}
+		sched, err := models.ParseSchedule(t.Source)
+		if err != nil {
+			// validated on the way in, this shouldn't happen
+			common.Logger(ctx).WithError(err).WithField("trigger_id", t.ID).Error("invalid schedule on trigger")
+			continue
+		}
+		entries[t.ID] = &entry{trigger: t, schedule: sched, next: sched.Next(now)}
+	}
+	s.entries = entries
+	return nil
+}
+
+func (s *Scheduler) scheduleTriggers(ctx context.Context) ([]*models.Trigger, error) {
+	var triggers []*models.Trigger
+	appFilter := &models.AppFilter{PerPage: pageSize}
+	for {
+		apps, err := s.ds.GetApps(ctx, appFilter)
+		if err != nil {
+			return nil, err
+		}
+		for _, app := range apps.Items {
+			filter := &models.TriggerFilter{AppID: app.ID, PerPage: pageSize}
+			for {
+				page, err := s.ds.GetTriggers(ctx, filter)
+				if err != nil {
+					return nil, err
+				}
+				for _, t := range page.Items {
+					if t.Type == models.TriggerTypeSchedule {
+						triggers = append(triggers, t)
+					}
+				}
+				if page.NextCursor == "" {
+					break
+				}
+				filter.Cursor = page.NextCursor
+			}
+		}
+		if apps.NextCursor == "" {
+			return triggers, nil
+		}
+		appFilter.Cursor = apps.NextCursor
+	}
+}
+
+func (s *Scheduler) fire(ctx context.Context, now time.Time) {
+	for _, e := range s.entries {
+		if e.next.IsZero() || now.Before(e.next) {
+			continue
+		}
+		tick := e.next
+		e.next = e.schedule.Next(now)
+
+		s.calls.Add(1)
+		go func(t *models.Trigger) {
+			defer s.calls.Done()
+			// calls outlive the scheduler, the agent waits for them on shutdown
+			err := s.invoke(common.BackgroundContext(ctx), t, tick)
+			if err != nil {
+				common.Logger(ctx).WithError(err).WithFields(logrus.Fields{"trigger_id": t.ID, "fn_id": t.FnID}).Error("failed to invoke schedule trigger")
+			}
+		}(e.trigger)
+	}
+}
+
+func (s *Scheduler) invoke(ctx context.Context, t *models.Trigger, tick time.Time) error {
+	req, err := http.NewRequest(http.MethodPost, "schedule://"+t.ID, http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Fn-Invoke-Type", models.TypeDetached)
+	req.Header.Set(ScheduledTimeHeader, tick.UTC().Format(time.RFC3339))
+
+	return s.invoker.InvokeTrigger(&discardResponseWriter{header: make(http.Header)}, req.WithContext(ctx), t)
+}
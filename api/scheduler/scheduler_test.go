@@ -0,0 +1,153 @@
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/id"
+	"github.com/fnproject/fn/api/models"
+)
+
+type invocation struct {
+	trigger *models.Trigger
+	req     *http.Request
+}
+
+type testInvoker struct {
+	lock  sync.Mutex
+	calls []invocation
+}
+
+func (ti *testInvoker) InvokeTrigger(w http.ResponseWriter, req *http.Request, trigger *models.Trigger) error {
+	ti.lock.Lock()
+	defer ti.lock.Unlock()
+	ti.calls = append(ti.calls, invocation{trigger, req})
+	w.WriteHeader(http.StatusAccepted)
+	return nil
+}
+
+type testLeaser struct {
+	lock    sync.Mutex
+	holder  string
+	expires time.Time
+}
+
+func (tl *testLeaser) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	tl.lock.Lock()
+	defer tl.lock.Unlock()
+	if tl.holder != "" && tl.holder != holder && time.Now().Before(tl.expires) {
+		return false, nil
+	}
+	tl.holder, tl.expires = holder, time.Now().Add(ttl)
+	return true, nil
+}
+
+func (tl *testLeaser) ReleaseLease(ctx context.Context, name, holder string) error {
+	tl.lock.Lock()
+	defer tl.lock.Unlock()
+	if tl.holder == holder {
+		tl.holder = ""
+	}
+	return nil
+}
+
+func (tl *testLeaser) current() string {
+	tl.lock.Lock()
+	defer tl.lock.Unlock()
+	return tl.holder
+}
+
+func testDatastore() (models.Datastore, *models.Trigger) {
+	app := &models.App{ID: id.New().String(), Name: "myapp"}
+	fn := &models.Fn{ID: id.New().String(), AppID: app.ID, Name: "myfn", Image: "fnproject/fn-test-utils"}
+	sched := &models.Trigger{ID: id.New().String(), Name: "every-minute", AppID: app.ID, FnID: fn.ID, Type: models.TriggerTypeSchedule, Source: "* * * * *"}
+	httpTrig := &models.Trigger{ID: id.New().String(), Name: "http", AppID: app.ID, FnID: fn.ID, Type: models.TriggerTypeHTTP, Source: "/myfn"}
+
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn}, []*models.Trigger{sched, httpTrig})
+	return ds, sched
+}
+
+func TestSchedulerFiresDueTriggers(t *testing.T) {
+	ctx := context.Background()
+	ds, trig := testDatastore()
+	invoker := &testInvoker{}
+	s := New(ds, nil)
+	s.invoker = invoker
+
+	now := time.Date(2018, time.August, 1, 10, 17, 30, 0, time.UTC)
+	if err := s.refresh(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.entries) != 1 {
+		t.Fatalf("expected only the schedule trigger to be loaded, got %d entries", len(s.entries))
+	}
+
+	s.fire(ctx, now)
+	s.calls.Wait()
+	if len(invoker.calls) != 0 {
+		t.Fatalf("expected no calls before the schedule is due, got %d", len(invoker.calls))
+	}
+
+	// several ticks late still only fires once
+	s.fire(ctx, now.Add(140*time.Second))
+	s.calls.Wait()
+	if len(invoker.calls) != 1 {
+		t.Fatalf("expected one call once the schedule is due, got %d", len(invoker.calls))
+	}
+
+	call := invoker.calls[0]
+	if call.trigger.ID != trig.ID {
+		t.Fatalf("call made for the wrong trigger: %+v", call.trigger)
+	}
+	if call.req.Header.Get("Fn-Invoke-Type") != models.TypeDetached {
+		t.Fatalf("expected a detached call, got %q", call.req.Header.Get("Fn-Invoke-Type"))
+	}
+	if tick := call.req.Header.Get(ScheduledTimeHeader); tick != "2018-08-01T10:18:00Z" {
+		t.Fatalf("expected the call to be scheduled at the first tick, got %q", tick)
+	}
+
+	next := s.entries[trig.ID].next
+	if !next.Equal(time.Date(2018, time.August, 1, 10, 20, 0, 0, time.UTC)) {
+		t.Fatalf("expected next tick after the one fired, got %v", next)
+	}
+
+	// reloading unchanged triggers keeps their ticks
+	if err := s.refresh(ctx, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if !s.entries[trig.ID].next.Equal(next) {
+		t.Fatalf("expected next tick to be kept across refresh, got %v", s.entries[trig.ID].next)
+	}
+}
+
+func TestSchedulerLeaderElection(t *testing.T) {
+	ds, _ := testDatastore()
+	leaser := &testLeaser{}
+
+	s1 := New(ds, leaser, WithLeaseTTL(time.Minute))
+	s2 := New(ds, leaser, WithLeaseTTL(time.Minute))
+
+	s1.Start(context.Background(), &testInvoker{})
+	for i := 0; leaser.current() != s1.holder; i++ {
+		if i > 100 {
+			t.Fatal("scheduler never took the lease")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx := context.Background()
+	if s2.acquire(ctx, common.Logger(ctx)) {
+		t.Fatal("expected the second scheduler not to lead while the first holds the lease")
+	}
+
+	// closing gives the lease up rather than waiting for it to lapse
+	s1.Stop(ctx)
+	if !s2.acquire(ctx, common.Logger(ctx)) {
+		t.Fatal("expected the second scheduler to lead once the first stopped")
+	}
+}
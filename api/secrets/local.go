@@ -0,0 +1,150 @@
+// Package secrets provides the stores that encrypt the secret config of apps
+// and fns.
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/fnproject/fn/api/models"
+)
+
+// KeySize is the size of the master key of a local store, which is an AES-256 key
+const KeySize = 32
+
+// sealedPrefix marks the values sealed by local stores, it is followed by the
+// id of the master key a value was sealed with
+const sealedPrefix = "fnsecret:v1:"
+
+var errMalformed = errors.New("malformed secret ciphertext")
+
+// local is a SecretStore that does envelope encryption with a master key:
+// each value is encrypted with a data key of its own, which is encrypted
+// with the master key and stored along with the value. Both are encrypted
+// with AES-256-GCM.
+type local struct {
+	// keyID tells the master key from others, so that values sealed with
+	// another one are reported as such rather than as corrupt
+	keyID  string
+	master cipher.AEAD
+}
+
+// NewLocal makes a store that encrypts with a master key of KeySize random bytes
+func NewLocal(key []byte) (models.SecretStore, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("secret keys must be %d bytes, got %d", KeySize, len(key))
+	}
+	master, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(key)
+	return &local{keyID: hex.EncodeToString(sum[:4]), master: master}, nil
+}
+
+// LoadKeyFile makes a local store of the master key in a file, which holds the
+// base64 encoding of KeySize random bytes, e.g. made with
+// `head -c 32 /dev/urandom | base64`
+func LoadKeyFile(path string) (models.SecretStore, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, fmt.Errorf("secret key file %s is not base64 encoded: %v", path, err)
+	}
+	return NewLocal(key)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext under a random nonce, which it is prefixed with
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts what seal encrypted
+func open(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errMalformed
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+}
+
+// Encrypt implements models.SecretStore
+func (s *local) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	wrappedKey, err := seal(s.master, dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(data, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return sealedPrefix + s.keyID + ":" + base64.RawURLEncoding.EncodeToString(append(wrappedKey, sealed...)), nil
+}
+
+// Decrypt implements models.SecretStore
+func (s *local) Decrypt(ctx context.Context, ciphertext string) (string, error) {
+	if !strings.HasPrefix(ciphertext, sealedPrefix) {
+		return "", errMalformed
+	}
+	parts := strings.SplitN(strings.TrimPrefix(ciphertext, sealedPrefix), ":", 2)
+	if len(parts) != 2 {
+		return "", errMalformed
+	}
+	if parts[0] != s.keyID {
+		return "", fmt.Errorf("secret was encrypted with key %s, not with the configured key %s", parts[0], s.keyID)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errMalformed
+	}
+
+	wrappedKeySize := s.master.NonceSize() + KeySize + s.master.Overhead()
+	if len(b) < wrappedKeySize {
+		return "", errMalformed
+	}
+	dataKey, err := open(s.master, b[:wrappedKeySize])
+	if err != nil {
+		return "", err
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(data, b[wrappedKeySize:])
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
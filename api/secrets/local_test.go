@@ -0,0 +1,121 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalEncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocal(bytes.Repeat([]byte{1}, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, plaintext := range []string{"hunter2", "", strings.Repeat("postgres://user:pass@db/fn ", 100)} {
+		c1, err := store.Encrypt(ctx, plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c2, err := store.Encrypt(ctx, plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c1 == c2 {
+			t.Errorf("expected every encryption to be different, got %s twice", c1)
+		}
+		if plaintext != "" && strings.Contains(c1, plaintext) {
+			t.Errorf("expected %q to be encrypted, got %s", plaintext, c1)
+		}
+		for _, c := range []string{c1, c2} {
+			got, err := store.Decrypt(ctx, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != plaintext {
+				t.Errorf("expected %q to be decrypted, got %q", plaintext, got)
+			}
+		}
+	}
+}
+
+func TestLocalDecryptFails(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocal(bytes.Repeat([]byte{1}, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewLocal(bytes.Repeat([]byte{2}, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := store.Encrypt(ctx, "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSealed, err := other.Encrypt(ctx, "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := base64.RawURLEncoding.DecodeString(sealed[strings.LastIndex(sealed, ":")+1:])
+	b[len(b)-1] ^= 1
+	tampered := sealed[:strings.LastIndex(sealed, ":")+1] + base64.RawURLEncoding.EncodeToString(b)
+
+	for _, ciphertext := range []string{
+		"hunter2",
+		sealedPrefix,
+		sealed[:len(sealed)-10],
+		tampered,
+		otherSealed,
+		strings.Replace(otherSealed, other.(*local).keyID, store.(*local).keyID, 1),
+	} {
+		if got, err := store.Decrypt(ctx, ciphertext); err == nil {
+			t.Errorf("expected %q not to be decrypted, got %q", ciphertext, got)
+		}
+	}
+}
+
+func TestLoadKeyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	key := bytes.Repeat([]byte{1}, KeySize)
+	path := filepath.Join(dir, "key")
+	if err := ioutil.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := LoadKeyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromKey, _ := NewLocal(key)
+	sealed, err := fromKey.Encrypt(context.Background(), "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.Decrypt(context.Background(), sealed); err != nil || got != "hunter2" {
+		t.Errorf("expected the store of the key file to decrypt with its key, got %q %v", got, err)
+	}
+
+	for _, content := range []string{"not base64!", base64.StdEncoding.EncodeToString(key[:16])} {
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadKeyFile(path); err == nil {
+			t.Errorf("expected a key file with %q to be invalid", content)
+		}
+	}
+	if _, err := LoadKeyFile(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected a missing key file to be invalid")
+	}
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/fnproject/fn/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleAliasCreate(c *gin.Context) {
+	ctx := c.Request.Context()
+	alias := &models.Alias{}
+
+	err := c.BindJSON(alias)
+	if err != nil {
+		if models.IsAPIError(err) {
+			handleErrorResponse(c, err)
+		} else {
+			handleErrorResponse(c, models.ErrInvalidJSON)
+		}
+		return
+	}
+
+	aliasCreated, err := s.datastore.InsertAlias(ctx, alias)
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, aliasCreated)
+}
@@ -0,0 +1,20 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/fnproject/fn/api"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleAliasDelete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	err := s.datastore.RemoveAlias(ctx, c.Param(api.AliasID))
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.String(http.StatusNoContent, "")
+}
@@ -0,0 +1,20 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/fnproject/fn/api"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleAliasGet(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	alias, err := s.datastore.GetAliasByID(ctx, c.Param(api.AliasID))
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alias)
+}
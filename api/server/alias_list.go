@@ -0,0 +1,25 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/fnproject/fn/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleAliasList(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filter := &models.AliasFilter{}
+	filter.Cursor, filter.PerPage = pageParams(c)
+	filter.AppID = c.Query("app_id")
+	filter.FnID = c.Query("fn_id")
+
+	aliases, err := s.datastore.GetAliases(ctx, filter)
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, aliases)
+}
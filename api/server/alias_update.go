@@ -0,0 +1,41 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/fnproject/fn/api"
+	"github.com/fnproject/fn/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleAliasUpdate(c *gin.Context) {
+	alias := &models.Alias{}
+
+	err := c.BindJSON(alias)
+	if err != nil {
+		if models.IsAPIError(err) {
+			handleErrorResponse(c, err)
+		} else {
+			handleErrorResponse(c, models.ErrInvalidJSON)
+		}
+		return
+	}
+
+	pathAliasID := c.Param(api.AliasID)
+
+	if alias.ID == "" {
+		alias.ID = pathAliasID
+	} else if pathAliasID != alias.ID {
+		handleErrorResponse(c, models.ErrAliasIDMismatch)
+		return
+	}
+
+	ctx := c.Request.Context()
+	aliasUpdated, err := s.datastore.UpdateAlias(ctx, alias)
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, aliasUpdated)
+}
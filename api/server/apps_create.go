@@ -22,11 +22,16 @@ func (s *Server) handleAppCreate(c *gin.Context) {
 		return
 	}
 
+	if err := s.sealSecretConfig(ctx, app.SecretConfig); err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
 	app, err = s.datastore.InsertApp(ctx, app)
 	if err != nil {
 		handleErrorResponse(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, app)
+	c.JSON(http.StatusOK, app.Redacted())
 }
@@ -17,5 +17,5 @@ func (s *Server) handleAppGet(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, app)
+	c.JSON(http.StatusOK, app.Redacted())
 }
@@ -22,5 +22,9 @@ func (s *Server) handleAppList(c *gin.Context) {
 		return
 	}
 
+	for idx, app := range apps.Items {
+		apps.Items[idx] = app.Redacted()
+	}
+
 	c.JSON(http.StatusOK, apps)
 }
@@ -32,11 +32,16 @@ func (s *Server) handleAppUpdate(c *gin.Context) {
 		handleErrorResponse(c, models.ErrAppsIDMismatch)
 		return
 	}
+
+	if err := s.sealSecretConfig(ctx, app.SecretConfig); err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
 	app, err = s.datastore.UpdateApp(ctx, app)
 	if err != nil {
 		handleErrorResponse(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, app)
+	c.JSON(http.StatusOK, app.Redacted())
 }
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/fnproject/fn/api"
+	"github.com/fnproject/fn/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleCallLogGet(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	callID := c.Param(api.CallID)
+	logReader, err := s.logStore.GetLog(ctx, c.Param(api.FnID), callID)
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	mimeTypes := c.Request.Header["Accept"]
+	if len(mimeTypes) == 0 {
+		writeCallLogJSON(c, callID, logReader)
+		return
+	}
+
+	for _, mimeType := range mimeTypes {
+		if strings.Contains(mimeType, "application/json") || strings.Contains(mimeType, "*/*") {
+			writeCallLogJSON(c, callID, logReader)
+			return
+		}
+		if strings.Contains(mimeType, "text/plain") {
+			c.Header("Content-Type", "text/plain; charset=utf-8")
+			c.Status(http.StatusOK)
+			io.Copy(c.Writer, logReader)
+			return
+		}
+	}
+
+	// none of the accepted content types can be served
+	handleErrorResponse(c, models.NewAPIError(http.StatusNotAcceptable, errors.New("unable to respond within acceptable response content types")))
+}
+
+func writeCallLogJSON(c *gin.Context, callID string, logReader io.Reader) {
+	b, err := ioutil.ReadAll(logReader)
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, &models.CallLog{CallID: callID, Log: string(b)})
+}
+
+func (s *Server) logsNotRecordedResponse(c *gin.Context) {
+	handleErrorResponse(c, models.ErrCallLogsNotRecorded)
+}
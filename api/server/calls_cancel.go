@@ -0,0 +1,22 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/fnproject/fn/api"
+	"github.com/gin-gonic/gin"
+)
+
+// handleCallCancel cancels a call that is running, the call then ends and
+// is recorded as cancelled.
+func (s *Server) handleCallCancel(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	err := s.agent.CancelCall(ctx, c.Param(api.CallID))
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.String(http.StatusAccepted, "")
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fnproject/fn/api"
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleCallList(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var filter models.CallFilter
+	filter.FnID = c.Param(api.FnID)
+	filter.Cursor, filter.PerPage = pageParams(c)
+
+	var err error
+	filter.FromTime, filter.ToTime, err = timeParams(c)
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	filter.Status = c.Query("status")
+	if filter.Status != "" && !models.ValidCallStatus(filter.Status) {
+		handleErrorResponse(c, models.ErrInvalidCallStatus)
+		return
+	}
+
+	calls, err := s.callStore.GetCalls(ctx, &filter)
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, calls)
+}
+
+// timeParams parses the optional from_time and to_time query parameters, given
+// as seconds since the epoch
+func timeParams(c *gin.Context) (fromTime, toTime common.DateTime, err error) {
+	if f := c.Query("from_time"); f != "" {
+		epoch, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return fromTime, toTime, models.ErrInvalidFromTime
+		}
+		fromTime = common.DateTime(time.Unix(epoch, 0))
+	}
+
+	if t := c.Query("to_time"); t != "" {
+		epoch, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			return fromTime, toTime, models.ErrInvalidToTime
+		}
+		toTime = common.DateTime(time.Unix(epoch, 0))
+	}
+	return fromTime, toTime, nil
+}
@@ -0,0 +1,288 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fnproject/fn/api/callstore"
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/id"
+	"github.com/fnproject/fn/api/logs"
+	"github.com/fnproject/fn/api/models"
+)
+
+func TestCallGet(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	fnID := id.New().String()
+	call := &models.Call{
+		ID:        id.New().String(),
+		FnID:      fnID,
+		AppID:     id.New().String(),
+		Status:    "success",
+		CreatedAt: common.DateTime(time.Now()),
+	}
+	cs := callstore.NewMockInit(call)
+
+	for i, test := range []struct {
+		path          string
+		expectedCode  int
+		expectedError error
+	}{
+		{fmt.Sprintf("/v2/fns/%s/calls/%s", fnID, call.ID), http.StatusOK, nil},
+		{fmt.Sprintf("/v2/fns/%s/calls/%s", fnID, id.New().String()), http.StatusNotFound, models.ErrCallNotFound},
+		{fmt.Sprintf("/v2/fns/%s/calls/%s", id.New().String(), call.ID), http.StatusNotFound, models.ErrCallNotFound},
+	} {
+		srv := testServer(datastore.NewMock(), nil, ServerTypeAPI, WithCallStore(cs))
+		_, rec := routerRequest(t, srv.Router, "GET", test.path, nil)
+
+		if rec.Code != test.expectedCode {
+			t.Fatalf("Test %d: Expected status code to be %d but was %d", i, test.expectedCode, rec.Code)
+		}
+
+		if test.expectedError != nil {
+			resp := getErrorResponse(t, rec)
+			if !strings.Contains(resp.Message, test.expectedError.Error()) {
+				t.Errorf("Test %d: Expected error message to have `%s` but was `%s`", i, test.expectedError, resp.Message)
+			}
+			continue
+		}
+
+		var got models.Call
+		if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+			t.Fatalf("Test %d: could not decode call: %v", i, err)
+		}
+		if got.ID != call.ID || got.Status != call.Status {
+			t.Errorf("Test %d: expected call %+v, got %+v", i, call, got)
+		}
+	}
+}
+
+func TestCallList(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	fnID := id.New().String()
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	var calls []*models.Call
+	for i := 0; i < 3; i++ {
+		status := "success"
+		if i == 1 {
+			status = "error"
+		}
+		calls = append(calls, &models.Call{
+			ID:        id.New().String(),
+			FnID:      fnID,
+			Status:    status,
+			CreatedAt: common.DateTime(base.Add(time.Duration(i) * time.Minute)),
+		})
+	}
+	cs := callstore.NewMockInit(calls...)
+
+	from := base.Unix()
+	to := base.Add(2 * time.Minute).Unix()
+
+	for i, test := range []struct {
+		path          string
+		expectedCode  int
+		expectedError error
+		expectedLen   int
+	}{
+		{fmt.Sprintf("/v2/fns/%s/calls", fnID), http.StatusOK, nil, 3},
+		{fmt.Sprintf("/v2/fns/%s/calls", id.New().String()), http.StatusOK, nil, 0},
+		{fmt.Sprintf("/v2/fns/%s/calls?status=error", fnID), http.StatusOK, nil, 1},
+		{fmt.Sprintf("/v2/fns/%s/calls?per_page=2", fnID), http.StatusOK, nil, 2},
+		{fmt.Sprintf("/v2/fns/%s/calls?from_time=%d&to_time=%d", fnID, from, to), http.StatusOK, nil, 1},
+		{fmt.Sprintf("/v2/fns/%s/calls?from_time=%d", fnID, from), http.StatusOK, nil, 2},
+		{fmt.Sprintf("/v2/fns/%s/calls?from_time=yesterday", fnID), http.StatusBadRequest, models.ErrInvalidFromTime, 0},
+		{fmt.Sprintf("/v2/fns/%s/calls?to_time=tomorrow", fnID), http.StatusBadRequest, models.ErrInvalidToTime, 0},
+		{fmt.Sprintf("/v2/fns/%s/calls?status=borked", fnID), http.StatusBadRequest, models.ErrInvalidCallStatus, 0},
+	} {
+		srv := testServer(datastore.NewMock(), nil, ServerTypeAPI, WithCallStore(cs))
+		_, rec := routerRequest(t, srv.Router, "GET", test.path, nil)
+
+		if rec.Code != test.expectedCode {
+			t.Fatalf("Test %d: Expected status code to be %d but was %d", i, test.expectedCode, rec.Code)
+		}
+
+		if test.expectedError != nil {
+			resp := getErrorResponse(t, rec)
+			if !strings.Contains(resp.Message, test.expectedError.Error()) {
+				t.Errorf("Test %d: Expected error message to have `%s` but was `%s`", i, test.expectedError, resp.Message)
+			}
+			continue
+		}
+
+		var got models.CallList
+		if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+			t.Fatalf("Test %d: could not decode calls: %v", i, err)
+		}
+		if len(got.Items) != test.expectedLen {
+			t.Errorf("Test %d: expected %d calls, got %d", i, test.expectedLen, len(got.Items))
+		}
+	}
+}
+
+func TestCallsGoneWithoutCallStore(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	srv := testServer(datastore.NewMock(), nil, ServerTypeAPI)
+	_, rec := routerRequest(t, srv.Router, "GET", "/v2/fns/fn/calls", nil)
+	if rec.Code != http.StatusGone {
+		t.Fatalf("Expected status code to be %d but was %d", http.StatusGone, rec.Code)
+	}
+}
+
+func TestCallLogGet(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	call := &models.Call{ID: id.New().String(), FnID: id.New().String()}
+	ls := logs.NewMock()
+	if err := ls.InsertLog(context.Background(), call, strings.NewReader("hello\n")); err != nil {
+		t.Fatal(err)
+	}
+	path := fmt.Sprintf("/v2/fns/%s/calls/%s/log", call.FnID, call.ID)
+
+	for i, test := range []struct {
+		path         string
+		accept       string
+		expectedCode int
+		expectedBody string
+	}{
+		{path, "", http.StatusOK, `{"call_id":"` + call.ID + `","log":"hello\n"}`},
+		{path, "application/json", http.StatusOK, `{"call_id":"` + call.ID + `","log":"hello\n"}`},
+		{path, "text/plain", http.StatusOK, "hello\n"},
+		{path, "image/png", http.StatusNotAcceptable, ""},
+		{fmt.Sprintf("/v2/fns/%s/calls/%s/log", call.FnID, id.New().String()), "", http.StatusNotFound, ""},
+	} {
+		srv := testServer(datastore.NewMock(), nil, ServerTypeAPI, WithLogStore(ls))
+		req := createRequest(t, "GET", test.path, nil)
+		if test.accept != "" {
+			req.Header.Set("Accept", test.accept)
+		}
+		_, rec := routerRequest2(t, srv.Router, req)
+
+		if rec.Code != test.expectedCode {
+			t.Fatalf("Test %d: Expected status code to be %d but was %d", i, test.expectedCode, rec.Code)
+		}
+		if test.expectedBody != "" && strings.TrimSpace(rec.Body.String()) != strings.TrimSpace(test.expectedBody) {
+			t.Errorf("Test %d: Expected body `%s` but was `%s`", i, test.expectedBody, rec.Body.String())
+		}
+	}
+}
+
+func TestCallLogGetNotRecorded(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	dir, err := ioutil.TempDir("", "fn-call-logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dbURL := "sqlite3://" + filepath.Join(dir, "fn.db")
+	path := fmt.Sprintf("/v2/fns/%s/calls/%s/log", id.New().String(), id.New().String())
+
+	// logs of calls made through LBs are not recorded in the datastore
+	srv := testServer(datastore.NewMock(), nil, ServerTypeAPI, WithDBURL(dbURL))
+	_, rec := routerRequest(t, srv.Router, "GET", path, nil)
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("Expected status code to be %d but was %d", http.StatusNotImplemented, rec.Code)
+	}
+	if resp := getErrorResponse(t, rec); resp.Message != models.ErrCallLogsNotRecorded.Error() {
+		t.Fatalf("Expected error %q but was %q", models.ErrCallLogsNotRecorded, resp.Message)
+	}
+
+	// unless full nodes are set to record them in a log store
+	srv = testServer(datastore.NewMock(), nil, ServerTypeAPI, WithDBURL(dbURL), WithLogStoreURL(dbURL))
+	_, rec = routerRequest(t, srv.Router, "GET", path, nil)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected status code to be %d but was %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+// cancelAgent is an agent that is running a single call
+type cancelAgent struct {
+	idleAgent
+	callID string
+}
+
+func (a *cancelAgent) CancelCall(ctx context.Context, callID string) error {
+	if callID != a.callID {
+		return models.ErrCallNotFound
+	}
+	return nil
+}
+
+func TestCallCancel(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	callID := id.New().String()
+	srv := testServer(datastore.NewMock(), &cancelAgent{callID: callID}, ServerTypeFull)
+
+	for i, test := range []struct {
+		path          string
+		expectedCode  int
+		expectedError error
+	}{
+		{"/v2/calls/" + callID, http.StatusAccepted, nil},
+		{"/v2/calls/" + id.New().String(), http.StatusNotFound, models.ErrCallNotFound},
+	} {
+		_, rec := routerRequest(t, srv.Router, "DELETE", test.path, nil)
+
+		if rec.Code != test.expectedCode {
+			t.Fatalf("Test %d: Expected status code to be %d but was %d", i, test.expectedCode, rec.Code)
+		}
+
+		if test.expectedError != nil {
+			resp := getErrorResponse(t, rec)
+			if !strings.Contains(resp.Message, test.expectedError.Error()) {
+				t.Errorf("Test %d: Expected error message to have `%s` but was `%s`", i, test.expectedError, resp.Message)
+			}
+		}
+	}
+
+	// api nodes run no calls to cancel
+	srv = testServer(datastore.NewMock(), nil, ServerTypeAPI)
+	_, rec := routerRequest(t, srv.Router, "DELETE", "/v2/calls/"+callID, nil)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected calls not to be cancellable on an api node, got %d", rec.Code)
+	}
+}
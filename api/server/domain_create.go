@@ -0,0 +1,37 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/fnproject/fn/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleDomainCreate(c *gin.Context) {
+	ctx := c.Request.Context()
+	domain := &models.Domain{}
+
+	err := c.BindJSON(domain)
+	if err != nil {
+		if models.IsAPIError(err) {
+			handleErrorResponse(c, err)
+		} else {
+			handleErrorResponse(c, models.ErrInvalidJSON)
+		}
+		return
+	}
+
+	// the server's own hostnames would hand its API and endpoints to the app
+	if s.hostnames[models.NormalizeHostname(domain.Hostname)] {
+		handleErrorResponse(c, models.ErrDomainServerHostname)
+		return
+	}
+
+	domainCreated, err := s.datastore.InsertDomain(ctx, domain)
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domainCreated)
+}
@@ -0,0 +1,20 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/fnproject/fn/api"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleDomainGet(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	domain, err := s.datastore.GetDomainByID(ctx, c.Param(api.DomainID))
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain)
+}
@@ -23,6 +23,16 @@ func (s *Server) handleFnCreate(c *gin.Context) {
 	}
 
 	fn.SetDefaults()
+	// detached calls can only be followed up once they have been queued
+	if fn.HasDestinations() && s.mq == nil {
+		handleErrorResponse(c, models.ErrFnsDestinationsNotSupported)
+		return
+	}
+	if err := s.sealSecretConfig(ctx, fn.SecretConfig); err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
 	fnCreated, err := s.datastore.InsertFn(ctx, fn)
 	if err != nil {
 		handleErrorResponse(c, err)
@@ -32,16 +42,16 @@ func (s *Server) handleFnCreate(c *gin.Context) {
 	app, err := s.datastore.GetAppByID(ctx, fnCreated.AppID)
 	if err != nil {
 		log.Debugln("Failed to lookup app.")
-		c.JSON(http.StatusOK, fnCreated)
+		c.JSON(http.StatusOK, fnCreated.Redacted())
 		return
 	}
 
 	fnAnnotated, err := s.fnAnnotator.AnnotateFn(c, app, fnCreated)
 	if err != nil {
 		log.Debugln("Failed to annotate fn")
-		c.JSON(http.StatusOK, fnCreated)
+		c.JSON(http.StatusOK, fnCreated.Redacted())
 		return
 	}
 
-	c.JSON(http.StatusOK, fnAnnotated)
+	c.JSON(http.StatusOK, fnAnnotated.Redacted())
 }
@@ -29,5 +29,5 @@ func (s *Server) handleFnGet(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, f)
+	c.JSON(http.StatusOK, f.Redacted())
 }
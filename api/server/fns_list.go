@@ -44,7 +44,7 @@ func (s *Server) handleFnList(c *gin.Context) {
 			handleErrorResponse(c, err)
 			return
 		}
-		fns.Items[idx] = newF
+		fns.Items[idx] = newF.Redacted()
 	}
 
 	c.JSON(http.StatusOK, fns)
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/fnproject/fn/api"
+	"github.com/fnproject/fn/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) handleFnRevisionList(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	fnID := c.Param(api.FnID)
+	if _, err := s.datastore.GetFnByID(ctx, fnID); err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	filter := &models.FnRevisionFilter{FnID: fnID}
+	filter.Cursor, filter.PerPage = pageParams(c)
+
+	revisions, err := s.datastore.GetFnRevisions(ctx, filter)
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+	for i, r := range revisions.Items {
+		revisions.Items[i] = r.Redacted()
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// fnRollback is the body of a rollback request, the fn is rolled back to its
+// latest revision if it has no revision id. As a rollback records a revision
+// too, two rollbacks without revision ids leave the fn as it was.
+type fnRollback struct {
+	RevisionID string `json:"revision_id"`
+}
+
+func (s *Server) handleFnRollback(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var rollback fnRollback
+	if err := json.NewDecoder(c.Request.Body).Decode(&rollback); err != nil && err != io.EOF {
+		handleErrorResponse(c, models.ErrInvalidJSON)
+		return
+	}
+
+	fn, err := s.datastore.RollbackFn(ctx, c.Param(api.FnID), rollback.RevisionID)
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fn.Redacted())
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"github.com/fnproject/fn/api/datastore"
 	"github.com/fnproject/fn/api/id"
 	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/api/mqs"
 )
 
 type funcTestCase struct {
@@ -26,7 +28,7 @@ type funcTestCase struct {
 
 func (test *funcTestCase) run(t *testing.T, i int, buf *bytes.Buffer) {
 	rnr, cancel := testRunner(t)
-	srv := testServer(test.ds, rnr, ServerTypeFull)
+	srv := testServer(test.ds, rnr, ServerTypeFull, WithMQ(mqs.NewMemoryMQ()))
 
 	body := bytes.NewBuffer([]byte(test.body))
 	_, rec := routerRequest(t, srv.Router, test.method, test.path, body)
@@ -101,6 +103,11 @@ func TestFnCreate(t *testing.T) {
 		{ds, http.MethodPost, "/v2/fns", fmt.Sprintf(`{ "app_id": "%s", "name": "a", "image": "fnproject/fn-test-utils", "timeout": 3601 }`, a.ID), http.StatusBadRequest, models.ErrFnsInvalidTimeout},
 		{ds, http.MethodPost, "/v2/fns", fmt.Sprintf(`{ "app_id": "%s", "name": "a", "image": "fnproject/fn-test-utils", "idle_timeout": 3601 }`, a.ID), http.StatusBadRequest, models.ErrFnsInvalidIdleTimeout},
 		{ds, http.MethodPost, "/v2/fns", fmt.Sprintf(`{ "app_id": "%s", "name": "a", "image": "fnproject/fn-test-utils", "memory": 100000000000000 }`, a.ID), http.StatusBadRequest, models.ErrInvalidMemory},
+		{ds, http.MethodPost, "/v2/fns", fmt.Sprintf(`{ "app_id": "%s", "name": "a", "image": "fnproject/fn-test-utils", "cpus": "2000000m" }`, a.ID), http.StatusBadRequest, models.ErrInvalidCPUs},
+		{ds, http.MethodPost, "/v2/fns", fmt.Sprintf(`{ "app_id": "%s", "name": "a", "image": "fnproject/fn-test-utils", "tmpfs_size": 4294967295 }`, a.ID), http.StatusBadRequest, models.ErrFnsInvalidTmpFsSize},
+		{ds, http.MethodPost, "/v2/fns", fmt.Sprintf(`{ "app_id": "%s", "name": "a", "image": "fnproject/fn-test-utils", "dead_letter": "ftp://example.com" }`, a.ID), http.StatusBadRequest, models.ErrFnsInvalidDeadLetter},
+		{ds, http.MethodPost, "/v2/fns", fmt.Sprintf(`{ "app_id": "%s", "name": "a", "image": "fnproject/fn-test-utils", "on_success": "results/latest" }`, a.ID), http.StatusBadRequest, models.ErrFnsInvalidOnSuccess},
+		{ds, http.MethodPost, "/v2/fns", fmt.Sprintf(`{ "app_id": "%s", "name": "a", "image": "fnproject/fn-test-utils", "on_failure": "tcp://example.com:80" }`, a.ID), http.StatusBadRequest, models.ErrFnsInvalidOnFailure},
 
 		// success create & update
 		{ds, http.MethodPost, "/v2/fns", fmt.Sprintf(`{ "app_id": "%s", "name": "myfunc", "image": "fnproject/fn-test-utils" }`, a.ID), http.StatusOK, nil},
@@ -125,6 +132,11 @@ func TestFnUpdate(t *testing.T) {
 		{ds, http.MethodPut, fmt.Sprintf("/v2/fns/%s", f.ID), `{ "memory": 1000 }`, http.StatusOK, nil},
 		{ds, http.MethodPut, fmt.Sprintf("/v2/fns/%s", f.ID), `{ "timeout": 10 }`, http.StatusOK, nil},
 		{ds, http.MethodPut, fmt.Sprintf("/v2/fns/%s", f.ID), `{ "idle_timeout": 10 }`, http.StatusOK, nil},
+		{ds, http.MethodPut, fmt.Sprintf("/v2/fns/%s", f.ID), `{ "cpus": "500m" }`, http.StatusOK, nil},
+		{ds, http.MethodPut, fmt.Sprintf("/v2/fns/%s", f.ID), `{ "cpus": "1.5" }`, http.StatusOK, nil},
+		{ds, http.MethodPut, fmt.Sprintf("/v2/fns/%s", f.ID), `{ "tmpfs_size": 64 }`, http.StatusOK, nil},
+		{ds, http.MethodPut, fmt.Sprintf("/v2/fns/%s", f.ID), `{ "dead_letter": "https://example.com/dead-letters" }`, http.StatusOK, nil},
+		{ds, http.MethodPut, fmt.Sprintf("/v2/fns/%s", f.ID), `{ "on_success": "https://example.com/results", "on_failure": "https://example.com/errors" }`, http.StatusOK, nil},
 		{ds, http.MethodPut, fmt.Sprintf("/v2/fns/%s", f.ID), `{ "config": {"k":"v"} }`, http.StatusOK, nil},
 		{ds, http.MethodPut, fmt.Sprintf("/v2/fns/%s", f.ID), `{ "annotations": {"k":"v"} }`, http.StatusOK, nil},
 
@@ -132,11 +144,45 @@ func TestFnUpdate(t *testing.T) {
 		{ds, http.MethodPut, fmt.Sprintf("/v2/fns/%s", f.ID), `{ "timeout": 3601 }`, http.StatusBadRequest, models.ErrFnsInvalidTimeout},
 		{ds, http.MethodPut, fmt.Sprintf("/v2/fns/%s", f.ID), `{ "idle_timeout": 3601 }`, http.StatusBadRequest, models.ErrFnsInvalidIdleTimeout},
 		{ds, http.MethodPut, fmt.Sprintf("/v2/fns/%s", f.ID), `{ "memory": 100000000000000 }`, http.StatusBadRequest, models.ErrInvalidMemory},
+		{ds, http.MethodPut, fmt.Sprintf("/v2/fns/%s", f.ID), `{ "cpus": "lots" }`, http.StatusBadRequest, models.ErrInvalidCPUs},
 	} {
 		test.run(t, i, buf)
 	}
 }
 
+func TestFnUpdateClearsNulls(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	a := &models.App{Name: "a", ID: "app_id"}
+	f := &models.Fn{ID: "fn_id", Name: "f", AppID: a.ID, Image: "fnproject/fn-test-utils",
+		ResourceConfig: models.ResourceConfig{TmpFsSize: 64, CPUs: 500},
+		DeadLetter:     "https://example.com/dead-letters",
+		OnSuccess:      "https://example.com/results",
+		OnFailure:      "https://example.com/errors",
+	}
+	f.SetDefaults()
+	ds := datastore.NewMockInit([]*models.App{a}, []*models.Fn{f})
+	srv := testServer(ds, shoutingAgent(nil), ServerTypeFull, WithMQ(mqs.NewMemoryMQ()))
+
+	body := `{ "tmpfs_size": null, "cpus": null, "dead_letter": null, "on_success": null, "on_failure": null }`
+	_, rec := routerRequest(t, srv.Router, http.MethodPut, "/v2/fns/fn_id", bytes.NewBufferString(body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the fn to be updated, got %d: %s", rec.Code, rec.Body.String())
+	}
+	fn, err := ds.GetFnByID(context.Background(), "fn_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fn.TmpFsSize != 0 || fn.CPUs != 0 || fn.HasDestinations() {
+		t.Fatalf("expected the fields set to null to be cleared, got %+v", fn)
+	}
+}
+
 func TestFnDelete(t *testing.T) {
 	buf := setLogBuffer()
 
@@ -424,3 +470,87 @@ func TestFnInvokeEndpointAnnotations(t *testing.T) {
 		t.Errorf("unexpected fn val %s", v)
 	}
 }
+
+func TestFnRevisions(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	a := &models.App{Name: "a", ID: "app_id"}
+	f := &models.Fn{ID: "fn_id", Name: "f", AppID: a.ID, Image: "fnproject/fn-test-utils:v1"}
+	f.SetDefaults()
+	ds := datastore.NewMockInit([]*models.App{a}, []*models.Fn{f})
+	srv := testServer(ds, shoutingAgent(nil), ServerTypeFull, WithMQ(mqs.NewMemoryMQ()))
+
+	for i, body := range []string{
+		`{ "image": "fnproject/fn-test-utils:v2" }`,
+		`{ "on_success": "https://example.com/results" }`,
+		`{ "image": "fnproject/fn-test-utils:v3", "config": {"k": "v"} }`,
+	} {
+		_, rec := routerRequest(t, srv.Router, http.MethodPut, "/v2/fns/fn_id", bytes.NewBufferString(body))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Test %d: expected status %d, got %d: %s", i, http.StatusOK, rec.Code, rec.Body.String())
+		}
+	}
+
+	listRevisions := func() []*models.FnRevision {
+		_, rec := routerRequest(t, srv.Router, http.MethodGet, "/v2/fns/fn_id/revisions", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		var revisions models.FnRevisionList
+		if err := json.NewDecoder(rec.Body).Decode(&revisions); err != nil {
+			t.Fatal(err)
+		}
+		return revisions.Items
+	}
+
+	revisions := listRevisions()
+	if len(revisions) != 2 || revisions[0].Image != "fnproject/fn-test-utils:v2" || revisions[1].Image != "fnproject/fn-test-utils:v1" {
+		t.Fatalf("expected the revisions of the image updates, newest first, got %#v", revisions)
+	}
+
+	for i, tc := range []struct {
+		path          string
+		body          string
+		expectedCode  int
+		expectedError error
+		expectedImage string
+	}{
+		{"/v2/fns/fn_id/rollback", ``, http.StatusOK, nil, "fnproject/fn-test-utils:v2"},
+		{"/v2/fns/fn_id/rollback", fmt.Sprintf(`{"revision_id": %q}`, revisions[1].ID), http.StatusOK, nil, "fnproject/fn-test-utils:v1"},
+		{"/v2/fns/fn_id/rollback", `{"revision_id": "missing"}`, http.StatusNotFound, models.ErrFnRevisionNotFound, ""},
+		{"/v2/fns/fn_id/rollback", `{"revision_id": `, http.StatusBadRequest, models.ErrInvalidJSON, ""},
+		{"/v2/fns/missing/rollback", ``, http.StatusNotFound, models.ErrFnsNotFound, ""},
+		{"/v2/fns/missing/revisions", ``, http.StatusNotFound, models.ErrFnsNotFound, ""},
+	} {
+		method := http.MethodPost
+		if strings.HasSuffix(tc.path, "/revisions") {
+			method = http.MethodGet
+		}
+		_, rec := routerRequest(t, srv.Router, method, tc.path, bytes.NewBufferString(tc.body))
+		if rec.Code != tc.expectedCode {
+			t.Fatalf("Test %d: expected status %d, got %d: %s", i, tc.expectedCode, rec.Code, rec.Body.String())
+		}
+		if tc.expectedError != nil && !strings.Contains(rec.Body.String(), tc.expectedError.Error()) {
+			t.Fatalf("Test %d: expected error %q, got %s", i, tc.expectedError, rec.Body.String())
+		}
+		if tc.expectedImage != "" {
+			var fn models.Fn
+			if err := json.NewDecoder(rec.Body).Decode(&fn); err != nil {
+				t.Fatal(err)
+			}
+			if fn.Image != tc.expectedImage || fn.OnSuccess != "https://example.com/results" {
+				t.Fatalf("Test %d: expected fn to be rolled back to image %q, got %#v", i, tc.expectedImage, fn)
+			}
+		}
+	}
+
+	// every rollback is itself a revision, so it can be undone
+	if revisions := listRevisions(); len(revisions) != 4 || revisions[0].Image != "fnproject/fn-test-utils:v2" || revisions[1].Image != "fnproject/fn-test-utils:v3" {
+		t.Fatalf("expected the rollbacks to record revisions, got %#v", revisions)
+	}
+}
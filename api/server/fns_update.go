@@ -31,11 +31,22 @@ func (s *Server) handleFnUpdate(c *gin.Context) {
 		}
 	}
 
+	// detached calls can only be followed up once they have been queued
+	if fn.HasDestinations() && s.mq == nil {
+		handleErrorResponse(c, models.ErrFnsDestinationsNotSupported)
+		return
+	}
+
+	if err := s.sealSecretConfig(ctx, fn.SecretConfig); err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
 	fnUpdated, err := s.datastore.UpdateFn(ctx, fn)
 	if err != nil {
 		handleErrorResponse(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, fnUpdated)
+	c.JSON(http.StatusOK, fnUpdated.Redacted())
 }
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/fnproject/fn/api"
+	"github.com/fnproject/fn/api/models"
 	"github.com/gin-gonic/gin"
 )
 
@@ -41,3 +42,77 @@ func (s *Server) handleRunnerGetTriggerBySource(c *gin.Context) {
 
 	c.JSON(http.StatusOK, trigger)
 }
+
+// handleRunnerGetApp gets an app for LBs, with its secret config sealed rather than redacted
+func (s *Server) handleRunnerGetApp(c *gin.Context) {
+	app, err := s.datastore.GetAppByID(c.Request.Context(), c.Param(api.AppID))
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, app)
+}
+
+// handleRunnerGetFn gets a fn for LBs, with its secret config sealed rather than redacted
+func (s *Server) handleRunnerGetFn(c *gin.Context) {
+	fn, err := s.datastore.GetFnByID(c.Request.Context(), c.Param(api.FnID))
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fn)
+}
+
+func (s *Server) handleRunnerGetDomainByHostname(c *gin.Context) {
+	domain, err := s.datastore.GetDomainByHostname(c.Request.Context(), c.Param(api.Hostname))
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain)
+}
+
+func (s *Server) handleRunnerGetAliasByName(c *gin.Context) {
+	alias, err := s.datastore.GetAliasByName(c.Request.Context(), c.Param(api.AppID), c.Param(api.AliasName))
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alias)
+}
+
+// handleRunnerAcquireLease takes or renews a lease for an LB node, which has no leaser of its own
+func (s *Server) handleRunnerAcquireLease(c *gin.Context) {
+	var lease models.Lease
+	if err := c.BindJSON(&lease); err != nil {
+		if models.IsAPIError(err) {
+			handleErrorResponse(c, err)
+		} else {
+			handleErrorResponse(c, models.ErrInvalidJSON)
+		}
+		return
+	}
+
+	acquired, err := s.leaser.AcquireLease(c.Request.Context(), c.Param(api.LeaseName), lease.Holder, lease.TTL)
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+	lease.Acquired = acquired
+
+	c.JSON(http.StatusOK, lease)
+}
+
+func (s *Server) handleRunnerReleaseLease(c *gin.Context) {
+	err := s.leaser.ReleaseLease(c.Request.Context(), c.Param(api.LeaseName), c.Query("holder"))
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
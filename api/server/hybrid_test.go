@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fnproject/fn/api/agent/hybrid"
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/id"
+	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/api/scheduler"
+)
+
+type testLeaser struct {
+	lock   sync.Mutex
+	holder string
+}
+
+func (tl *testLeaser) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	tl.lock.Lock()
+	defer tl.lock.Unlock()
+	if tl.holder != "" && tl.holder != holder {
+		return false, nil
+	}
+	tl.holder = holder
+	return true, nil
+}
+
+func (tl *testLeaser) ReleaseLease(ctx context.Context, name, holder string) error {
+	tl.lock.Lock()
+	defer tl.lock.Unlock()
+	if tl.holder == holder {
+		tl.holder = ""
+	}
+	return nil
+}
+
+// LB nodes have no datastore, they read schedule triggers and take the
+// scheduler lease through the API nodes
+func TestHybridScheduleTriggers(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	ctx := context.Background()
+	app := &models.App{ID: id.New().String(), Name: "myapp"}
+	fn := &models.Fn{ID: id.New().String(), AppID: app.ID, Name: "myfn", Image: "fnproject/fn-test-utils"}
+	sched := &models.Trigger{ID: id.New().String(), Name: "every-minute", AppID: app.ID, FnID: fn.ID, Type: models.TriggerTypeSchedule, Source: "* * * * *"}
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn}, []*models.Trigger{sched})
+
+	srv := testServer(ds, nil, ServerTypeAPI, WithLeaser(&testLeaser{}))
+	api := httptest.NewServer(srv.Router)
+	defer api.Close()
+
+	cl, err := hybrid.NewClient(api.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaser, ok := cl.(models.Leaser)
+	if !ok {
+		t.Fatal("expected the hybrid client to be a leaser")
+	}
+	source, ok := cl.(scheduler.TriggerSource)
+	if !ok {
+		t.Fatal("expected the hybrid client to be a trigger source")
+	}
+
+	for _, tc := range []struct {
+		holder   string
+		expected bool
+	}{{"lb1", true}, {"lb2", false}, {"lb1", true}} {
+		acquired, err := leaser.AcquireLease(ctx, scheduler.LeaseName, tc.holder, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if acquired != tc.expected {
+			t.Errorf("expected %s to acquire the lease %v, got %v", tc.holder, tc.expected, acquired)
+		}
+	}
+	if err := leaser.ReleaseLease(ctx, scheduler.LeaseName, "lb1"); err != nil {
+		t.Fatal(err)
+	}
+	if acquired, err := leaser.AcquireLease(ctx, scheduler.LeaseName, "lb2", time.Minute); err != nil || !acquired {
+		t.Errorf("expected the released lease to be acquired, got %v %v", acquired, err)
+	}
+
+	apps, err := source.GetApps(ctx, &models.AppFilter{PerPage: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apps.Items) != 1 || apps.Items[0].ID != app.ID {
+		t.Errorf("expected to list the app, got %v", apps.Items)
+	}
+	triggers, err := source.GetTriggers(ctx, &models.TriggerFilter{AppID: app.ID, PerPage: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triggers.Items) != 1 || triggers.Items[0].Source != sched.Source {
+		t.Errorf("expected to list the schedule trigger, got %v", triggers.Items)
+	}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+
+	"github.com/fnproject/fn/api"
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/models"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// aliasInvokePath is the first segment of the path of the invoke endpoints
+// of aliases, /invoke/alias/:app_name/:alias_name. It takes the place of the
+// fn id in the router, which can't tell it from one, and fn ids are never it.
+const aliasInvokePath = "alias"
+
+// handleAliasInvokeCall executes the fn an alias points to, for router handlers
+func (s *Server) handleAliasInvokeCall(c *gin.Context) {
+	if c.Param(api.FnID) != aliasInvokePath {
+		handleErrorResponse(c, models.ErrPathNotFound)
+		return
+	}
+	ctx, _ := common.LoggerWithFields(c.Request.Context(), logrus.Fields{"app_name": c.Param(api.AppName), "alias": c.Param(api.AliasName)})
+	c.Request = c.Request.WithContext(ctx)
+	err := s.handleAliasInvokeCall2(c)
+	if err != nil {
+		handleErrorResponse(c, err)
+	}
+}
+
+func (s *Server) handleAliasInvokeCall2(c *gin.Context) error {
+	ctx := c.Request.Context()
+	appID, err := s.lbReadAccess.GetAppID(ctx, c.Param(api.AppName))
+	if err != nil {
+		return err
+	}
+
+	app, err := s.lbReadAccess.GetAppByID(ctx, appID)
+	if err != nil {
+		return err
+	}
+
+	fnID, err := s.aliasFnID(ctx, appID, c.Param(api.AliasName))
+	if err != nil {
+		return err
+	}
+
+	fn, err := s.lbReadAccess.GetFnByID(ctx, fnID)
+	if err != nil {
+		return err
+	}
+
+	return s.serveFnInvokeCall(c, app, fn)
+}
+
+// aliasFnID resolves an alias of an app to the id of the fn it points to
+func (s *Server) aliasFnID(ctx context.Context, appID, name string) (string, error) {
+	alias, err := s.lbReadAccess.GetAliasByName(ctx, appID, name)
+	if err != nil {
+		return "", err
+	}
+	return alias.FnID, nil
+}
+
+// triggerFnID is the id of the fn that handles a request to trigger with
+// method, resolving the alias the trigger targets if it does, or false if the
+// trigger does not allow method
+func (s *Server) triggerFnID(ctx context.Context, trigger *models.Trigger, method string) (string, bool, error) {
+	fnID, ok := trigger.FnIDForMethod(method)
+	if !ok || fnID != "" || trigger.Alias == "" {
+		return fnID, ok, nil
+	}
+	fnID, err := s.aliasFnID(ctx, trigger.AppID, trigger.Alias)
+	return fnID, true, err
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/models"
+)
+
+func TestAliasInvoke(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	blue := &models.Fn{ID: "blue_id", Name: "blue", AppID: app.ID, Image: "fnproject/fn-test-utils:blue"}
+	green := &models.Fn{ID: "green_id", Name: "green", AppID: app.ID, Image: "fnproject/fn-test-utils:green"}
+	alias := &models.Alias{ID: "alias_id", Name: "prod", AppID: app.ID, FnID: blue.ID}
+	trigger := &models.Trigger{ID: "trigger_id", Name: "mytrigger", AppID: app.ID, Alias: alias.Name, Type: "http", Source: "/mytrigger"}
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{blue, green}, []*models.Trigger{trigger}, []*models.Alias{alias})
+
+	fnIDs := make(chan string, 1)
+	srv := testServer(ds, fnIDAgent(fnIDs), ServerTypeFull)
+
+	call := func(path string, expectedCode int) string {
+		_, rec := routerRequest(t, srv.Router, "POST", path, nil)
+		if rec.Code != expectedCode {
+			t.Fatalf("expected %d calling %s, got %d: %s", expectedCode, path, rec.Code, rec.Body.String())
+		}
+		if rec.Code != http.StatusOK {
+			return ""
+		}
+		return <-fnIDs
+	}
+
+	for _, path := range []string{"/invoke/alias/myapp/prod", "/t/myapp/mytrigger"} {
+		if got := call(path, http.StatusOK); got != blue.ID {
+			t.Errorf("expected %s to be served by %s, got %s", path, blue.ID, got)
+		}
+	}
+	call("/invoke/alias/myapp/staging", http.StatusNotFound)
+	call("/invoke/alias/otherapp/prod", http.StatusNotFound)
+	call("/invoke/notalias/myapp/prod", http.StatusNotFound)
+	if got := call("/invoke/green_id", http.StatusOK); got != green.ID {
+		t.Errorf("expected fns to be invoked by id still, got %s", got)
+	}
+
+	// switching the alias switches both endpoints, once the cached alias expires
+	_, rec := routerRequest(t, srv.Router, "PUT", "/v2/aliases/alias_id", bytes.NewBufferString(`{"fn_id": "green_id"}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected alias to be updated, got %d: %s", rec.Code, rec.Body.String())
+	}
+	srv = testServer(ds, fnIDAgent(fnIDs), ServerTypeFull)
+	for _, path := range []string{"/invoke/alias/myapp/prod", "/t/myapp/mytrigger"} {
+		if got := call(path, http.StatusOK); got != green.ID {
+			t.Errorf("expected %s to be served by %s, got %s", path, green.ID, got)
+		}
+	}
+}
+
+func TestAliasAPI(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	otherApp := &models.App{ID: "other_app_id", Name: "other"}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	otherFn := &models.Fn{ID: "other_fn_id", Name: "myfn", AppID: otherApp.ID, Image: "fnproject/fn-test-utils"}
+	ds := datastore.NewMockInit([]*models.App{app, otherApp}, []*models.Fn{fn, otherFn})
+	srv := testServer(ds, shoutingAgent(nil), ServerTypeFull)
+
+	for i, tc := range []struct {
+		method        string
+		path          string
+		body          string
+		expectedCode  int
+		expectedError error
+	}{
+		{"POST", "/v2/aliases", `{"app_id": "app_id", "name": "prod", "fn_id": "fn_id"}`, http.StatusOK, nil},
+		{"POST", "/v2/aliases", `{"app_id": "app_id", "name": "prod", "fn_id": "fn_id"}`, http.StatusConflict, models.ErrAliasExists},
+		{"POST", "/v2/aliases", `{"app_id": "app_id", "name": "pr/od", "fn_id": "fn_id"}`, http.StatusBadRequest, models.ErrAliasInvalidName},
+		{"POST", "/v2/aliases", `{"app_id": "app_id", "name": "staging"}`, http.StatusBadRequest, models.ErrAliasMissingFnID},
+		{"POST", "/v2/aliases", `{"app_id": "app_id", "name": "staging", "fn_id": "other_fn_id"}`, http.StatusBadRequest, models.ErrAliasFnIDNotSameApp},
+		{"POST", "/v2/aliases", `{"app_id": "notreal", "name": "staging", "fn_id": "fn_id"}`, http.StatusNotFound, models.ErrAppsNotFound},
+		{"POST", "/v2/aliases", `{"id": "alias_id", "app_id": "app_id", "name": "staging", "fn_id": "fn_id"}`, http.StatusBadRequest, models.ErrAliasIDProvided},
+		{"PUT", "/v2/aliases/notreal", `{"fn_id": "fn_id"}`, http.StatusNotFound, models.ErrAliasNotFound},
+		{"PUT", "/v2/aliases/notreal", `{"id": "other", "fn_id": "fn_id"}`, http.StatusBadRequest, models.ErrAliasIDMismatch},
+		{"POST", "/v2/triggers", `{"app_id": "app_id", "name": "t", "alias": "prod", "fn_id": "fn_id", "type": "http", "source": "/t"}`, http.StatusBadRequest, models.ErrTriggerFnIDAndAlias},
+		{"POST", "/v2/triggers", `{"app_id": "app_id", "name": "t", "alias": "staging", "type": "http", "source": "/t"}`, http.StatusNotFound, models.ErrAliasNotFound},
+		{"POST", "/v2/triggers", `{"app_id": "app_id", "name": "t", "alias": "prod", "type": "http", "source": "/t"}`, http.StatusOK, nil},
+	} {
+		_, rec := routerRequest(t, srv.Router, tc.method, tc.path, bytes.NewBufferString(tc.body))
+		if rec.Code != tc.expectedCode {
+			t.Fatalf("Test %d: expected status %d, got %d: %s", i, tc.expectedCode, rec.Code, rec.Body.String())
+		}
+		if tc.expectedError != nil && !strings.Contains(rec.Body.String(), tc.expectedError.Error()) {
+			t.Fatalf("Test %d: expected error %q, got %s", i, tc.expectedError, rec.Body.String())
+		}
+	}
+
+	_, rec := routerRequest(t, srv.Router, "GET", "/v2/aliases?app_id=app_id", nil)
+	var aliases models.AliasList
+	if err := json.NewDecoder(rec.Body).Decode(&aliases); err != nil {
+		t.Fatal(err)
+	}
+	if len(aliases.Items) != 1 || aliases.Items[0].Name != "prod" || aliases.Items[0].FnID != fn.ID {
+		t.Fatalf("expected the alias to be listed, got %v", aliases.Items)
+	}
+
+	// the trigger targeting the alias keeps it from being removed
+	_, rec = routerRequest(t, srv.Router, "DELETE", "/v2/aliases/"+aliases.Items[0].ID, nil)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected alias in use, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
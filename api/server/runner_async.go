@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fnproject/fn/api/async"
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/models"
+)
+
+// enqueueDetachedCall puts a detached call on the queue rather than running
+// it, the async runner of some node runs it later, once delay seconds have
+// passed. The call id is returned in the Fn-Call-Id header as for any other
+// detached call.
+func (s *Server) enqueueDetachedCall(resp http.ResponseWriter, req *http.Request, app *models.App, fn *models.Fn, trig *models.Trigger, delay int32) error {
+	ctx := req.Context()
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+
+	call := async.NewCall(app, fn, req.Method, requestURL(req), req.Header, body)
+	if trig != nil {
+		call.TriggerID = trig.ID
+	}
+	if delay > 0 {
+		call.Delay = delay
+		call.Status = "delayed"
+	}
+
+	if _, err := s.mq.Push(ctx, call); err != nil {
+		return err
+	}
+
+	// the call is safely queued, failing to record it only hides it from the api until it runs
+	if s.callStore != nil {
+		if err := s.callStore.InsertCall(ctx, call); err != nil {
+			common.Logger(ctx).WithError(err).Error("error inserting queued call into call store")
+		}
+	}
+
+	resp.Header().Add("Fn-Call-Id", call.ID)
+	resp.WriteHeader(http.StatusAccepted)
+	return nil
+}
+
+// invokeDelay is the number of seconds a call asks to be delayed by, in its
+// Fn-Invoke-Delay header. Only detached calls can be delayed, by up to max.
+func invokeDelay(req *http.Request, isDetached bool, max time.Duration) (int32, error) {
+	v := req.Header.Get("Fn-Invoke-Delay")
+	if v == "" {
+		return 0, nil
+	}
+	delay, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || delay < 0 || !isDetached {
+		return 0, models.ErrInvalidInvokeDelay
+	}
+	if maxSecs := int64(max / time.Second); delay > maxSecs {
+		return 0, models.NewAPIError(http.StatusBadRequest, fmt.Errorf("Fn-Invoke-Delay is too long, calls can be delayed by at most %d seconds", maxSecs))
+	}
+	return int32(delay), nil
+}
+
+// requestURL is the absolute url of req, as the agent records it
+func requestURL(req *http.Request) string {
+	u := *req.URL
+	if u.Scheme == "" {
+		if req.TLS == nil {
+			u.Scheme = "http"
+		} else {
+			u.Scheme = "https"
+		}
+	}
+	if u.Host == "" {
+		u.Host = req.Host
+	}
+	return u.String()
+}
+
+// startAsyncRunner runs queued calls, on nodes that can make calls
+func (s *Server) startAsyncRunner(ctx context.Context) {
+	if s.asyncRunner != nil {
+		s.asyncRunner.Start(ctx)
+	}
+}
+
+// stopAsyncRunner waits for the queued calls being run to finish
+func (s *Server) stopAsyncRunner() {
+	if s.asyncRunner != nil {
+		s.asyncRunner.Stop()
+	}
+}
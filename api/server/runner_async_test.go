@@ -0,0 +1,202 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fnproject/fn/api/agent"
+	"github.com/fnproject/fn/api/callstore"
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/api/mqs"
+)
+
+// idleAgent is an agent that is never asked to run anything
+type idleAgent struct {
+	agent.Agent
+}
+
+func TestFnInvokeDetachedIsQueued(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	fn.SetDefaults()
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn})
+	mq := mqs.NewMemoryMQ()
+	cs := callstore.NewMock()
+
+	srv := testServer(ds, idleAgent{}, ServerTypeFull, WithMQ(mq), WithCallStore(cs))
+	if srv.asyncRunner == nil {
+		t.Fatal("expected a full node with a queue to run queued calls")
+	}
+
+	req := createRequest(t, "POST", "/invoke/fn_id", bytes.NewBufferString("hello"))
+	req.Header.Set("Fn-Invoke-Type", models.TypeDetached)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Cookie", "session=secret")
+	_, rec := routerRequest2(t, srv.Router, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected detached call to be accepted, got %d: %s", rec.Code, rec.Body.String())
+	}
+	callID := rec.Header().Get("Fn-Call-Id")
+	if callID == "" {
+		t.Fatal("expected the id of the queued call to be returned")
+	}
+
+	ctx := context.Background()
+	call, err := mq.Reserve(ctx, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call == nil {
+		t.Fatal("expected the call to be queued")
+	}
+	if call.ID != callID || call.FnID != fn.ID || call.AppID != app.ID || call.Payload != "hello" || call.Method != "POST" {
+		t.Fatalf("queued call doesn't match the request: %+v", call)
+	}
+	if call.Headers.Get("Authorization") != "" || call.Headers.Get("Cookie") != "" {
+		t.Fatalf("expected the credentials of the request not to be queued, got %v", call.Headers)
+	}
+
+	stored, err := cs.GetCall(ctx, fn.ID, callID)
+	if err != nil {
+		t.Fatalf("expected queued call to be recorded: %v", err)
+	}
+	if stored.Status != "queued" {
+		t.Fatalf("expected call to be recorded as queued, got %q", stored.Status)
+	}
+}
+
+func TestFnInvokeDelayed(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	fn.SetDefaults()
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn})
+	mq := mqs.NewMemoryMQ()
+	cs := callstore.NewMock()
+
+	srv := testServer(ds, idleAgent{}, ServerTypeFull, WithMQ(mq), WithCallStore(cs), WithMaxInvokeDelay(time.Hour))
+
+	for i, test := range []struct {
+		invokeType    string
+		delay         string
+		expectedCode  int
+		expectedError error
+	}{
+		{models.TypeDetached, "soon", http.StatusBadRequest, models.ErrInvalidInvokeDelay},
+		{models.TypeDetached, "-1", http.StatusBadRequest, models.ErrInvalidInvokeDelay},
+		{models.TypeDetached, "1.5", http.StatusBadRequest, models.ErrInvalidInvokeDelay},
+		{models.TypeSync, "60", http.StatusBadRequest, models.ErrInvalidInvokeDelay},
+		{"", "60", http.StatusBadRequest, models.ErrInvalidInvokeDelay},
+		{models.TypeDetached, "3601", http.StatusBadRequest, errors.New("Fn-Invoke-Delay is too long, calls can be delayed by at most 3600 seconds")},
+	} {
+		req := createRequest(t, "POST", "/invoke/fn_id", bytes.NewBufferString("hello"))
+		req.Header.Set("Fn-Invoke-Type", test.invokeType)
+		req.Header.Set("Fn-Invoke-Delay", test.delay)
+		_, rec := routerRequest2(t, srv.Router, req)
+		if rec.Code != test.expectedCode {
+			t.Fatalf("Test %d: expected status %d, got %d: %s", i, test.expectedCode, rec.Code, rec.Body.String())
+		}
+		if resp := getErrorResponse(t, rec); resp.Message != test.expectedError.Error() {
+			t.Fatalf("Test %d: expected error %q, got %q", i, test.expectedError, resp.Message)
+		}
+	}
+
+	req := createRequest(t, "POST", "/invoke/fn_id", bytes.NewBufferString("hello"))
+	req.Header.Set("Fn-Invoke-Type", models.TypeDetached)
+	req.Header.Set("Fn-Invoke-Delay", "60")
+	_, rec := routerRequest2(t, srv.Router, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected delayed call to be accepted, got %d: %s", rec.Code, rec.Body.String())
+	}
+	callID := rec.Header().Get("Fn-Call-Id")
+	if callID == "" {
+		t.Fatal("expected the id of the delayed call to be returned")
+	}
+
+	ctx := context.Background()
+	call, err := mq.Reserve(ctx, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call != nil {
+		t.Fatalf("expected the call not to be run before its delay, got %+v", call)
+	}
+
+	stored, err := cs.GetCall(ctx, fn.ID, callID)
+	if err != nil {
+		t.Fatalf("expected delayed call to be recorded: %v", err)
+	}
+	if stored.Status != "delayed" || stored.Delay != 60 {
+		t.Fatalf("expected call to be recorded as delayed by 60s, got %q %d", stored.Status, stored.Delay)
+	}
+
+	// without a queue there is nowhere to hold the call
+	srv = testServer(ds, idleAgent{}, ServerTypeFull, WithCallStore(cs))
+	req = createRequest(t, "POST", "/invoke/fn_id", bytes.NewBufferString("hello"))
+	req.Header.Set("Fn-Invoke-Type", models.TypeDetached)
+	req.Header.Set("Fn-Invoke-Delay", "60")
+	_, rec = routerRequest2(t, srv.Router, req)
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("expected delayed call to be refused without a queue, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDestinationsNeedQueue(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils", DeadLetter: "https://example.com/dead-letters"}
+	fn.SetDefaults()
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn})
+
+	srv := testServer(ds, idleAgent{}, ServerTypeFull)
+
+	for i, test := range []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodPost, "/v2/fns", `{ "app_id": "app_id", "name": "other", "image": "fnproject/fn-test-utils", "dead_letter": "https://example.com/dead-letters" }`},
+		{http.MethodPut, "/v2/fns/fn_id", `{ "on_success": "https://example.com/results" }`},
+		{http.MethodPut, "/v2/fns/fn_id", `{ "on_failure": "https://example.com/errors" }`},
+	} {
+		_, rec := routerRequest(t, srv.Router, test.method, test.path, bytes.NewBufferString(test.body))
+		if rec.Code != http.StatusNotImplemented {
+			t.Fatalf("Test %d: expected status %d, got %d: %s", i, http.StatusNotImplemented, rec.Code, rec.Body.String())
+		}
+		if resp := getErrorResponse(t, rec); resp.Message != models.ErrFnsDestinationsNotSupported.Error() {
+			t.Fatalf("Test %d: expected error %q, got %q", i, models.ErrFnsDestinationsNotSupported, resp.Message)
+		}
+	}
+
+	// fns made elsewhere are not run as if they had no destinations
+	req := createRequest(t, "POST", "/invoke/fn_id", bytes.NewBufferString("hello"))
+	req.Header.Set("Fn-Invoke-Type", models.TypeDetached)
+	_, rec := routerRequest2(t, srv.Router, req)
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("expected detached call of a fn with destinations to be refused, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fnproject/fn/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// isCORSPreflight tells if req asks whether a request from another origin may be made
+func isCORSPreflight(req *http.Request) bool {
+	return req.Method == http.MethodOptions && req.Header.Get("Origin") != "" && req.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// serveCORSPreflight answers a preflight request to trigger by its CORS
+// policy, refusing requests the policy doesn't allow
+func serveCORSPreflight(c *gin.Context, trigger *models.Trigger) error {
+	req := c.Request
+	policy := trigger.CORS
+	origin := req.Header.Get("Origin")
+	if !policy.AllowsOrigin(origin) {
+		return models.ErrTriggerCORSRefused
+	}
+
+	method := req.Header.Get("Access-Control-Request-Method")
+	methods := policy.AllowMethods
+	if len(methods) == 0 {
+		methods = trigger.AllowedMethods()
+	}
+	if len(methods) == 0 {
+		// the trigger allows any method
+		methods = []string{method}
+	}
+	if !stringInSlice(method, methods) {
+		return models.ErrTriggerCORSRefused
+	}
+
+	var headers []string
+	for _, h := range strings.Split(req.Header.Get("Access-Control-Request-Headers"), ",") {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
+		if !policy.AllowsHeader(h) {
+			return models.ErrTriggerCORSRefused
+		}
+		headers = append(headers, h)
+	}
+
+	header := c.Writer.Header()
+	for k, vs := range corsHeaders(req, trigger) {
+		header[k] = vs
+	}
+	header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	if len(headers) > 0 {
+		header.Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+	}
+	if policy.MaxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.Itoa(policy.MaxAge))
+	}
+	c.Status(http.StatusNoContent)
+	c.Writer.WriteHeaderNow()
+	return nil
+}
+
+// corsHeaders are the headers that let browsers read the response to req, if
+// its origin is allowed by the CORS policy of trigger
+func corsHeaders(req *http.Request, trigger *models.Trigger) http.Header {
+	origin := req.Header.Get("Origin")
+	if trigger.CORS == nil || origin == "" || !trigger.CORS.AllowsOrigin(origin) {
+		return nil
+	}
+
+	header := make(http.Header)
+	if trigger.CORS.AllowsAnyOrigin() {
+		header.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Set("Vary", "Origin")
+	}
+	return header
+}
+
+func stringInSlice(s string, list []string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/models"
+)
+
+func TestTriggerCORS(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	strict := &models.Trigger{ID: "strict_id", Name: "strict", AppID: app.ID, FnID: fn.ID, Type: "http", Source: "/strict",
+		Methods: models.HTTPMethods{"GET", "POST"},
+		CORS:    &models.TriggerCORS{AllowOrigins: []string{"https://example.com"}, AllowHeaders: []string{"X-Token"}, MaxAge: 600}}
+	open := &models.Trigger{ID: "open_id", Name: "open", AppID: app.ID, FnID: fn.ID, Type: "http", Source: "/open",
+		CORS: &models.TriggerCORS{AllowOrigins: []string{"*"}, AllowHeaders: []string{"*"}}}
+	plain := &models.Trigger{ID: "plain_id", Name: "plain", AppID: app.ID, FnID: fn.ID, Type: "http", Source: "/plain"}
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn}, []*models.Trigger{strict, open, plain})
+
+	headers := make(chan http.Header, 10)
+	srv := testServer(ds, shoutingAgent(headers), ServerTypeFull)
+
+	for i, tc := range []struct {
+		method          string
+		path            string
+		origin          string
+		requestMethod   string
+		requestHeaders  string
+		expectedCode    int
+		expectedHeaders map[string]string
+		expectedCall    bool
+	}{
+		// preflights are answered by the policy
+		{"OPTIONS", "/t/myapp/strict", "https://example.com", "POST", "X-Token, Content-Type", http.StatusNoContent, map[string]string{
+			"Access-Control-Allow-Origin":  "https://example.com",
+			"Access-Control-Allow-Methods": "GET, POST",
+			"Access-Control-Allow-Headers": "X-Token, Content-Type",
+			"Access-Control-Max-Age":       "600",
+			"Vary":                         "Origin",
+		}, false},
+		{"OPTIONS", "/t/myapp/strict", "https://other.com", "POST", "", http.StatusForbidden, map[string]string{"Access-Control-Allow-Origin": ""}, false},
+		{"OPTIONS", "/t/myapp/strict", "https://example.com", "PUT", "", http.StatusForbidden, nil, false},
+		{"OPTIONS", "/t/myapp/strict", "https://example.com", "POST", "X-Other", http.StatusForbidden, nil, false},
+		{"OPTIONS", "/t/myapp/open", "https://other.com", "DELETE", "X-Other", http.StatusNoContent, map[string]string{
+			"Access-Control-Allow-Origin":  "*",
+			"Access-Control-Allow-Methods": "DELETE",
+			"Access-Control-Allow-Headers": "X-Other",
+			"Access-Control-Max-Age":       "",
+			"Vary":                         "",
+		}, false},
+
+		// other requests are made, and browsers may read responses to allowed origins
+		{"POST", "/t/myapp/strict", "https://example.com", "", "", http.StatusOK, map[string]string{"Access-Control-Allow-Origin": "https://example.com", "Vary": "Origin"}, true},
+		{"POST", "/t/myapp/strict", "https://other.com", "", "", http.StatusOK, map[string]string{"Access-Control-Allow-Origin": ""}, true},
+		{"DELETE", "/t/myapp/strict", "https://example.com", "", "", http.StatusMethodNotAllowed, map[string]string{"Access-Control-Allow-Origin": "https://example.com"}, false},
+		{"POST", "/t/myapp/open", "https://other.com", "", "", http.StatusOK, map[string]string{"Access-Control-Allow-Origin": "*"}, true},
+
+		// triggers without a policy leave preflights to fns
+		{"OPTIONS", "/t/myapp/plain", "https://example.com", "POST", "", http.StatusOK, map[string]string{"Access-Control-Allow-Origin": ""}, true},
+	} {
+		req := createRequest(t, tc.method, tc.path, strings.NewReader("hello"))
+		req.Header.Set("Origin", tc.origin)
+		if tc.requestMethod != "" {
+			req.Header.Set("Access-Control-Request-Method", tc.requestMethod)
+		}
+		if tc.requestHeaders != "" {
+			req.Header.Set("Access-Control-Request-Headers", tc.requestHeaders)
+		}
+		_, rec := routerRequest2(t, srv.Router, req)
+
+		if rec.Code != tc.expectedCode {
+			t.Fatalf("Test %d: expected status %d, got %d: %s", i, tc.expectedCode, rec.Code, rec.Body.String())
+		}
+		for k, v := range tc.expectedHeaders {
+			if got := rec.Header().Get(k); got != v {
+				t.Fatalf("Test %d: expected %s to be %q, got %q", i, k, v, got)
+			}
+		}
+		select {
+		case <-headers:
+			if !tc.expectedCall {
+				t.Fatalf("Test %d: expected no call to be made", i)
+			}
+		default:
+			if tc.expectedCall {
+				t.Fatalf("Test %d: expected a call to be made", i)
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/fnproject/fn/api"
+	"github.com/fnproject/fn/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// domainRouting routes requests to the custom domains of apps to their http
+// triggers, as if they had been made to /t/app_name with the same path, so a
+// request to https://api.example.com/orders calls the trigger with the source
+// /orders in the app api.example.com is a domain of. Requests to any other
+// host, or to the paths of the API, invoke endpoints and admin endpoints on
+// any host, are routed as usual.
+func (s *Server) domainRouting(c *gin.Context) {
+	if serverPath(c.Request.URL.Path) {
+		return
+	}
+	hostname := models.NormalizeHostname(c.Request.Host)
+	// hosts that can't be domains, such as addresses and localhost, needn't be looked up
+	if !models.IsDomainHostname(hostname) || s.hostnames[hostname] {
+		return
+	}
+
+	ctx := c.Request.Context()
+	domain, err := s.lbReadAccess.GetDomainByHostname(ctx, hostname)
+	if err == models.ErrDomainNotFound {
+		return
+	}
+	// from here on the request is the domain's, whatever is routed to its path
+	c.Abort()
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	app, err := s.lbReadAccess.GetAppByID(ctx, domain.AppID)
+	if err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
+	c.Params = gin.Params{
+		{Key: api.AppName, Value: app.Name},
+		{Key: api.TriggerSource, Value: c.Request.URL.Path},
+	}
+	s.handleHTTPTriggerCall(c)
+}
+
+// serverPaths are the prefixes of the paths fn serves itself on every host
+var serverPaths = []string{"/v2", "/invoke", "/version", "/metrics", "/debug"}
+
+// serverPath tells if path is one fn serves itself, which domains can't take
+func serverPath(path string) bool {
+	for _, p := range serverPaths {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// urlHostname is the hostname of u, "" if it has none
+func urlHostname(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/models"
+)
+
+func TestDomainRouting(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "orders"}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	trigger := &models.Trigger{ID: "trigger_id", Name: "orders", AppID: app.ID, FnID: fn.ID, Type: "http", Source: "/orders"}
+	domain := &models.Domain{ID: "domain_id", AppID: app.ID, Hostname: "api.example.com"}
+	// taken before the server's own hostnames were refused
+	serverDomain := &models.Domain{ID: "server_domain_id", AppID: app.ID, Hostname: "fn.example.com"}
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn}, []*models.Trigger{trigger}, []*models.Domain{domain, serverDomain})
+
+	headers := make(chan http.Header, 10)
+	srv := testServer(ds, shoutingAgent(headers), ServerTypeFull, WithHostnames("fn.example.com"))
+
+	for i, tc := range []struct {
+		host           string
+		path           string
+		expectedCode   int
+		expectedBody   string
+		expectedSource string
+	}{
+		{"api.example.com", "/orders", http.StatusOK, "HELLO", "/orders"},
+		{"API.example.com.:443", "/orders", http.StatusOK, "HELLO", "/orders"},
+		{"api.example.com", "/t/orders/orders", http.StatusNotFound, "Trigger not found", ""},
+		{"api.example.com", "/v2/apps", http.StatusBadRequest, models.ErrInvalidJSON.Error(), ""},
+		{"api.example.com", "/v2", http.StatusNotFound, "", ""},
+		{"api.example.com", "/invoke/fn_id", http.StatusOK, "HELLO", ""},
+		{"api.example.com", "/version", http.StatusMethodNotAllowed, "", ""},
+		{"api.example.com", "/v2orders", http.StatusNotFound, "Trigger not found", ""},
+		{"fn.example.com", "/orders", http.StatusNotFound, "", ""},
+		{"fn.example.com:8080", "/t/orders/orders", http.StatusOK, "HELLO", "/orders"},
+		{"other.example.com", "/orders", http.StatusNotFound, "", ""},
+		{"other.example.com", "/t/orders/orders", http.StatusOK, "HELLO", "/orders"},
+		{"127.0.0.1:8080", "/t/orders/orders", http.StatusOK, "HELLO", "/orders"},
+	} {
+		req := createRequest(t, "POST", tc.path, strings.NewReader("hello"))
+		req.Host = tc.host
+		_, rec := routerRequest2(t, srv.Router, req)
+
+		if rec.Code != tc.expectedCode {
+			t.Fatalf("Test %d: expected status %d, got %d: %s", i, tc.expectedCode, rec.Code, rec.Body.String())
+		}
+		if !strings.Contains(rec.Body.String(), tc.expectedBody) {
+			t.Fatalf("Test %d: expected body to contain %q, got %q", i, tc.expectedBody, rec.Body.String())
+		}
+		if tc.expectedCode == http.StatusOK {
+			// only trigger calls see the request, invoke calls get its body
+			if h := <-headers; tc.expectedSource != "" && h.Get("Fn-Http-Request-Url") == "" {
+				t.Fatalf("Test %d: expected the call to see the request, got %v", i, h)
+			}
+		}
+	}
+}
+
+func TestDomainAPI(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "orders"}
+	otherApp := &models.App{ID: "other_app_id", Name: "other"}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	trigger := &models.Trigger{ID: "trigger_id", Name: "orders", AppID: app.ID, FnID: fn.ID, Type: "http", Source: "/orders"}
+	ds := datastore.NewMockInit([]*models.App{app, otherApp}, []*models.Fn{fn}, []*models.Trigger{trigger})
+	srv := testServer(ds, shoutingAgent(nil), ServerTypeFull, WithHostnames("fn.example.com:8080"))
+
+	for i, tc := range []struct {
+		body          string
+		expectedCode  int
+		expectedError error
+	}{
+		{`{"app_id": "app_id", "hostname": "api.example.com"}`, http.StatusOK, nil},
+		{`{"app_id": "other_app_id", "hostname": "api.example.com"}`, http.StatusConflict, models.ErrDomainExists},
+		{`{"app_id": "app_id", "hostname": "API.example.com"}`, http.StatusBadRequest, models.ErrDomainInvalidHostname},
+		{`{"app_id": "app_id", "hostname": "10.0.0.1"}`, http.StatusBadRequest, models.ErrDomainInvalidHostname},
+		{`{"app_id": "app_id", "hostname": "fn.example.com"}`, http.StatusBadRequest, models.ErrDomainServerHostname},
+		{`{"app_id": "app_id"}`, http.StatusBadRequest, models.ErrDomainMissingHostname},
+		{`{"app_id": "notreal", "hostname": "new.example.com"}`, http.StatusNotFound, models.ErrAppsNotFound},
+		{`{"id": "domain_id", "app_id": "app_id", "hostname": "new.example.com"}`, http.StatusBadRequest, models.ErrDomainIDProvided},
+	} {
+		_, rec := routerRequest(t, srv.Router, "POST", "/v2/domains", bytes.NewBufferString(tc.body))
+		if rec.Code != tc.expectedCode {
+			t.Fatalf("Test %d: expected status %d, got %d: %s", i, tc.expectedCode, rec.Code, rec.Body.String())
+		}
+		if tc.expectedError != nil && !strings.Contains(rec.Body.String(), tc.expectedError.Error()) {
+			t.Fatalf("Test %d: expected error %q, got %s", i, tc.expectedError, rec.Body.String())
+		}
+	}
+
+	_, rec := routerRequest(t, srv.Router, "GET", "/v2/domains?app_id=app_id", nil)
+	var domains models.DomainList
+	if err := json.NewDecoder(rec.Body).Decode(&domains); err != nil {
+		t.Fatal(err)
+	}
+	if len(domains.Items) != 1 || domains.Items[0].Hostname != "api.example.com" {
+		t.Fatalf("expected the domain to be listed, got %v", domains.Items)
+	}
+
+	// triggers of the app are advertised on the domain
+	_, rec = routerRequest(t, srv.Router, "GET", "/v2/triggers/trigger_id", nil)
+	var got models.Trigger
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	var urls []string
+	if b, ok := got.Annotations.Get(models.TriggerHTTPDomainEndpointsAnnotation); !ok || json.Unmarshal(b, &urls) != nil {
+		t.Fatalf("expected domain endpoints, got %v", got.Annotations)
+	}
+	if len(urls) != 1 || urls[0] != "http://api.example.com/orders" {
+		t.Fatalf("expected the trigger url on the domain, got %v", urls)
+	}
+
+	_, rec = routerRequest(t, srv.Router, "DELETE", "/v2/domains/"+domains.Items[0].ID, nil)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected domain to be removed, got %d", rec.Code)
+	}
+	_, rec = routerRequest(t, srv.Router, "GET", "/v2/domains/"+domains.Items[0].ID, nil)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected domain to be gone, got %d", rec.Code)
+	}
+}
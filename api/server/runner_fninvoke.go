@@ -68,7 +68,22 @@ func (s *Server) handleFnInvokeCall2(c *gin.Context) error {
 		return err
 	}
 
-	err = s.ServeFnInvoke(c, app, fn)
+	return s.serveFnInvokeCall(c, app, fn)
+}
+
+// serveFnInvokeCall authenticates and serves a call to the invoke endpoint of
+// fn, against the fn the call is split to if fn splits its traffic
+func (s *Server) serveFnInvokeCall(c *gin.Context, app *models.App, fn *models.Fn) error {
+	ctx := c.Request.Context()
+	fn = s.routeTraffic(c.Request, fn)
+	if err := s.authenticateCall(c, app, fn); err != nil {
+		return err
+	}
+	for k, vs := range jwtClaimHeaders(c) {
+		c.Request.Header[k] = vs
+	}
+
+	err := s.ServeFnInvoke(c, app, fn)
 	if models.IsFuncError(err) || err == nil {
 		// report all user-directed errors and function responses from here, after submit has run.
 		// this is our never ending attempt to distinguish user and platform errors.
@@ -88,13 +103,30 @@ func (s *Server) ServeFnInvoke(c *gin.Context, app *models.App, fn *models.Fn) e
 }
 
 func (s *Server) fnInvoke(resp http.ResponseWriter, req *http.Request, app *models.App, fn *models.Fn, trig *models.Trigger) error {
+	isDetached := req.Header.Get("Fn-Invoke-Type") == models.TypeDetached
+	delay, err := invokeDelay(req, isDetached, s.maxInvokeDelay)
+	if err != nil {
+		return err
+	}
+	if isDetached && s.mq != nil {
+		return s.enqueueDetachedCall(resp, req, app, fn, trig, delay)
+	}
+	if isDetached && fn.HasDestinations() {
+		return models.ErrFnsDestinationsNotSupported
+	}
+	if delay > 0 {
+		return models.ErrInvokeDelayNotSupported
+	}
+	if !isDetached && isStreamed(req, fn) {
+		return s.streamFnInvoke(resp, req, app, fn, trig)
+	}
+
 	// TODO: we should get rid of the buffers, and stream back (saves memory (+splice), faster (splice), allows streaming, don't have to cap resp size)
 	// buffer the response before writing it out to client to prevent partials from trying to stream
 	buf := bufPool.Get().(*bytes.Buffer)
 	buf.Reset()
 	var writer ResponseBuffer
 
-	isDetached := req.Header.Get("Fn-Invoke-Type") == models.TypeDetached
 	if isDetached {
 		writer = agent.NewDetachedResponseWriter(resp.Header(), 202)
 	} else {
@@ -50,10 +50,45 @@ func (s *Server) handleTriggerHTTPFunctionCall2(c *gin.Context) error {
 		return err
 	}
 
-	fn, err := s.lbReadAccess.GetFnByID(ctx, trigger.FnID)
+	// preflight requests are answered by the CORS policy of the trigger, if it
+	// has one, and any other response can be read by the origins it allows
+	if trigger.CORS != nil && isCORSPreflight(c.Request) {
+		return serveCORSPreflight(c, trigger)
+	}
+	for k, vs := range corsHeaders(c.Request, trigger) {
+		c.Writer.Header()[k] = vs
+	}
+
+	// refuse methods the trigger doesn't allow before any fn is looked at
+	fnID, ok, err := s.triggerFnID(ctx, trigger, c.Request.Method)
+	if !ok {
+		c.Header("Allow", strings.Join(trigger.AllowedMethods(), ", "))
+		return models.ErrMethodNotAllowed
+	}
+	if err != nil {
+		return err
+	}
+
+	if trigger.Signature != nil {
+		if err := s.verifyTriggerSignature(c, trigger); err != nil {
+			return err
+		}
+	}
+
+	fn, err := s.lbReadAccess.GetFnByID(ctx, fnID)
 	if err != nil {
 		return err
 	}
+	// the fn the call is split to is the one to authenticate it against, and
+	// serves every message of a websocket
+	fn = s.routeTraffic(c.Request, fn)
+	if err := s.authenticateCall(c, app, fn); err != nil {
+		return err
+	}
+	if isWebSocketUpgrade(c.Request) && webSocketEnabled(trigger) {
+		return s.serveWebSocketTrigger(c, app, fn, trigger)
+	}
+
 	// gin sets this to 404 on NoRoute, so we'll just ensure it's 200 by default.
 	c.Status(200) // this doesn't write the header yet
 
@@ -75,6 +110,8 @@ func (s *Server) handleTriggerHTTPFunctionCall2(c *gin.Context) error {
 type triggerResponseWriter struct {
 	inner     http.ResponseWriter
 	committed bool
+	// headers are set on the response by the gateway, whatever the call responds
+	headers http.Header
 }
 
 func (trw *triggerResponseWriter) Header() http.Header {
@@ -123,6 +160,9 @@ func (trw *triggerResponseWriter) WriteHeader(serviceStatus int) {
 	for k, vs := range gwHeaders {
 		realHeaders[k] = vs
 	}
+	for k, vs := range trw.headers {
+		realHeaders[k] = vs
+	}
 
 	// XXX(reed): simplify / add tests for these behaviors...
 	finalStatus := 200
@@ -154,6 +194,33 @@ func reqURL(req *http.Request) string {
 func (s *Server) ServeHTTPTrigger(c *gin.Context, app *models.App, fn *models.Fn, trigger *models.Trigger) error {
 	// transpose trigger headers into the request
 	req := c.Request
+	cors := corsHeaders(req, trigger)
+	req.Header = httpTriggerHeaders(req, httpTriggerParams(c, trigger))
+	for k, vs := range jwtClaimHeaders(c) {
+		req.Header[k] = vs
+	}
+
+	// trap the headers and rewrite them for http trigger
+	rw := &triggerResponseWriter{inner: c.Writer, headers: cors}
+
+	return s.fnInvoke(rw, req, app, fn, trigger)
+}
+
+// httpTriggerParams are the values the path of a request matched in the
+// source of trigger, if that is a template
+func httpTriggerParams(c *gin.Context, trigger *models.Trigger) map[string]string {
+	p := c.Param(api.TriggerSource)
+	if p == "" {
+		p = "/"
+	}
+	params, _ := models.MatchTriggerSource(trigger.Source, p)
+	return params
+}
+
+// httpTriggerHeaders transposes the headers of a request to an http trigger
+// into the headers of the call it makes, along with the params matched in its
+// path, as Fn-Http-Param-* headers
+func httpTriggerHeaders(req *http.Request, params map[string]string) http.Header {
 	headers := make(http.Header, len(req.Header))
 
 	// remove transport headers before decorating headers
@@ -167,15 +234,12 @@ func (s *Server) ServeHTTPTrigger(c *gin.Context, app *models.App, fn *models.Fn
 		}
 		headers[k] = vs
 	}
-	requestURL := reqURL(req)
 
 	headers.Set("Fn-Http-Method", req.Method)
-	headers.Set("Fn-Http-Request-Url", requestURL)
+	headers.Set("Fn-Http-Request-Url", reqURL(req))
 	headers.Set("Fn-Intent", "httprequest")
-	req.Header = headers
-
-	// trap the headers and rewrite them for http trigger
-	rw := &triggerResponseWriter{inner: c.Writer}
-
-	return s.fnInvoke(rw, req, app, fn, trigger)
+	for name, value := range params {
+		headers.Set("Fn-Http-Param-"+name, value)
+	}
+	return headers
 }
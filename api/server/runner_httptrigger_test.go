@@ -14,6 +14,8 @@ import (
 	"github.com/fnproject/fn/api/agent"
 	"github.com/fnproject/fn/api/datastore"
 	"github.com/fnproject/fn/api/models"
+	pool "github.com/fnproject/fn/api/runnerpool"
+	"github.com/stretchr/testify/mock"
 )
 
 func envTweaker(name, value string) func() {
@@ -514,3 +516,88 @@ func TestTriggerRunnerMinimalConcurrentHotSync(t *testing.T) {
 		}
 	}
 }
+
+func TestTriggerSourceParams(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	posts := &models.Trigger{ID: "posts_id", Name: "posts", AppID: app.ID, FnID: fn.ID, Type: "http", Source: "/users/{id}/posts/*rest"}
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn}, []*models.Trigger{posts})
+
+	headers := make(chan http.Header, 1)
+	srv := testServer(ds, shoutingAgent(headers), ServerTypeFull)
+
+	req, _ := http.NewRequest("POST", "/t/myapp/users/42/posts/2018/hello", strings.NewReader("hi"))
+	// clients can't pass params off as their own
+	req.Header.Set("Fn-Http-Param-Id", "7")
+	_, rec := routerRequest2(t, srv.Router, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected templated source to be matched, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	h := <-headers
+	if h.Get("Fn-Http-Param-Id") != "42" || h.Get("Fn-Http-Param-Rest") != "2018/hello" {
+		t.Fatalf("Expected params of the path in headers, got %v", h)
+	}
+
+	_, rec = routerRequest(t, srv.Router, "GET", "/t/myapp/users/42/likes", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected unmatched path not to be found, got %d", rec.Code)
+	}
+}
+
+func TestTriggerMethods(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	reader := &models.Fn{ID: "reader_id", Name: "reader", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	writer := &models.Fn{ID: "writer_id", Name: "writer", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	items := &models.Trigger{ID: "items_id", Name: "items", AppID: app.ID, FnID: reader.ID, Type: "http", Source: "/items",
+		Methods: models.HTTPMethods{"GET"}, MethodFns: models.MethodFns{"POST": writer.ID}}
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{reader, writer}, []*models.Trigger{items})
+
+	calledFns := make(chan string, 1)
+	a := new(agent.MockAgent)
+	a.On("GetCall", mock.Anything).Return()
+	a.On("Submit", mock.Anything).Run(func(args mock.Arguments) {
+		calledFns <- args.Get(0).(pool.RunnerCall).Model().FnID
+	}).Return(nil)
+	srv := testServer(ds, a, ServerTypeFull)
+
+	for i, test := range []struct {
+		method       string
+		expectedCode int
+		expectedFn   string
+	}{
+		{"GET", http.StatusOK, reader.ID},
+		{"POST", http.StatusOK, writer.ID},
+		{"DELETE", http.StatusMethodNotAllowed, ""},
+	} {
+		_, rec := routerRequest(t, srv.Router, test.method, "/t/myapp/items", strings.NewReader("item"))
+		if rec.Code != test.expectedCode {
+			t.Fatalf("Test %d: expected status %d, got %d: %s", i, test.expectedCode, rec.Code, rec.Body.String())
+		}
+
+		if test.expectedFn == "" {
+			if allow := rec.Header().Get("Allow"); allow != "GET, POST" {
+				t.Fatalf("Test %d: expected the allowed methods in Allow, got %q", i, allow)
+			}
+			a.AssertNumberOfCalls(t, "Submit", 2)
+			continue
+		}
+		if fnID := <-calledFns; fnID != test.expectedFn {
+			t.Fatalf("Test %d: expected %s to be called, got %s", i, test.expectedFn, fnID)
+		}
+	}
+}
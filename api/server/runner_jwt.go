@@ -0,0 +1,123 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/jwt"
+	"github.com/fnproject/fn/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// JWTClaimHeaderPrefix prefixes the headers that carry the claims of the
+// verified bearer token of a call to the fn, such as Fn-Jwt-Claim-Sub
+const JWTClaimHeaderPrefix = "Fn-Jwt-Claim-"
+
+// jwtClaimsKey is where the claim headers of an authenticated call are kept in the gin context
+const jwtClaimsKey = "fn_jwt_claims"
+
+// authenticateCall checks, when bearer tokens are required, that the request
+// to call fn carries one that is valid, and has the audience and claims that
+// app and fn require. The claims of the token are kept in c as headers for
+// the call, see jwtClaimHeaders.
+func (s *Server) authenticateCall(c *gin.Context, app *models.App, fn *models.Fn) error {
+	if s.jwtVerifier == nil {
+		return nil
+	}
+	stripJWTClaimHeaders(c.Request.Header)
+
+	token := bearerToken(c.Request)
+	if token == "" {
+		c.Header("WWW-Authenticate", `Bearer realm="fn"`)
+		return models.ErrJWTMissing
+	}
+	claims, err := s.jwtVerifier.Verify(token)
+	if err != nil {
+		common.Logger(c.Request.Context()).WithError(err).Debug("bearer token refused")
+		c.Header("WWW-Authenticate", `Bearer realm="fn", error="invalid_token"`)
+		return models.ErrJWTInvalid
+	}
+	if !jwtClaimsAccepted(claims, app.Annotations) || !jwtClaimsAccepted(claims, fn.Annotations) {
+		c.Header("WWW-Authenticate", `Bearer realm="fn", error="insufficient_scope"`)
+		return models.ErrJWTRefused
+	}
+
+	headers := make(http.Header, len(claims))
+	for name, v := range claims {
+		value := jwt.ClaimString(v)
+		if !models.ValidHeaderName(name) || strings.ContainsAny(value, "\r\n\x00") {
+			continue
+		}
+		headers.Set(JWTClaimHeaderPrefix+name, value)
+	}
+	c.Set(jwtClaimsKey, headers)
+	return nil
+}
+
+// jwtClaimHeaders are the headers with the claims of the bearer token of the
+// call in c, nil if it was not authenticated
+func jwtClaimHeaders(c *gin.Context) http.Header {
+	headers, _ := c.Get(jwtClaimsKey)
+	h, _ := headers.(http.Header)
+	return h
+}
+
+// stripJWTClaimHeaders removes headers that pose as claims from header, so
+// that fns only ever see the claims of verified tokens
+func stripJWTClaimHeaders(header http.Header) {
+	for k := range header {
+		if strings.HasPrefix(k, JWTClaimHeaderPrefix) {
+			delete(header, k)
+		}
+	}
+}
+
+func bearerToken(req *http.Request) string {
+	auth := req.Header.Get("Authorization")
+	if len(auth) < 7 || !strings.EqualFold(auth[:7], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(auth[7:])
+}
+
+// jwtClaimsAccepted tells if claims have one of the audiences, and all of the
+// claims, required in annotations. Requirements that can't be read accept no
+// token.
+func jwtClaimsAccepted(claims jwt.Claims, annotations models.Annotations) bool {
+	if raw, ok := annotations.Get(models.JWTAudiencesAnnotation); ok {
+		var audiences []string
+		if err := json.Unmarshal(raw, &audiences); err != nil {
+			var audience string
+			if err := json.Unmarshal(raw, &audience); err != nil {
+				return false
+			}
+			audiences = []string{audience}
+		}
+		if !stringsIntersect(claims.Audiences(), audiences) {
+			return false
+		}
+	}
+	if raw, ok := annotations.Get(models.JWTClaimsAnnotation); ok {
+		var required map[string]string
+		if err := json.Unmarshal(raw, &required); err != nil {
+			return false
+		}
+		for name, value := range required {
+			if !claims.Has(name, value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func stringsIntersect(a, b []string) bool {
+	for _, s := range a {
+		if stringInSlice(s, b) {
+			return true
+		}
+	}
+	return false
+}
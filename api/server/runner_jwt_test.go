@@ -0,0 +1,171 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/jwt"
+	"github.com/fnproject/fn/api/models"
+)
+
+// hs256Token makes a token of claims signed with secret
+func hs256Token(secret string, claims map[string]interface{}) string {
+	h, _ := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	c, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestJWTAuthentication(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	appAnnotations, _ := models.EmptyAnnotations().With(models.JWTAudiencesAnnotation, []string{"orders"})
+	fnAnnotations, _ := models.EmptyAnnotations().With(models.JWTClaimsAnnotation, map[string]string{"groups": "admin"})
+	app := &models.App{ID: "app_id", Name: "myapp", Annotations: appAnnotations}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils", Annotations: fnAnnotations}
+	trigger := &models.Trigger{ID: "trigger_id", Name: "mytrigger", AppID: app.ID, FnID: fn.ID, Type: "http", Source: "/mytrigger"}
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn}, []*models.Trigger{trigger})
+
+	key, err := jwt.NewKey("", "HS256", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := make(chan http.Header, 10)
+	srv := testServer(ds, shoutingAgent(headers), ServerTypeFull, WithJWTVerifier(jwt.NewVerifier([]*jwt.Key{key}, "")))
+
+	exp := time.Now().Add(time.Hour).Unix()
+	valid := hs256Token("secret", map[string]interface{}{"sub": "alice", "aud": "orders", "groups": []string{"dev", "admin"}, "exp": exp})
+	wrongAudience := hs256Token("secret", map[string]interface{}{"sub": "alice", "aud": "billing", "groups": []string{"admin"}, "exp": exp})
+	missingClaim := hs256Token("secret", map[string]interface{}{"sub": "alice", "aud": []string{"orders"}, "exp": exp})
+	forged := hs256Token("other", map[string]interface{}{"sub": "alice", "aud": "orders", "groups": []string{"admin"}, "exp": exp})
+
+	for i, tc := range []struct {
+		path            string
+		authorization   string
+		expectedCode    int
+		expectedHeaders map[string]string
+	}{
+		{"/invoke/fn_id", "", http.StatusUnauthorized, nil},
+		{"/invoke/fn_id", "Basic YWxpY2U6c2VjcmV0", http.StatusUnauthorized, nil},
+		{"/invoke/fn_id", "Bearer " + forged, http.StatusUnauthorized, nil},
+		{"/invoke/fn_id", "Bearer " + wrongAudience, http.StatusForbidden, nil},
+		{"/invoke/fn_id", "Bearer " + missingClaim, http.StatusForbidden, nil},
+		{"/invoke/fn_id", "Bearer " + valid, http.StatusOK, map[string]string{
+			"Fn-Jwt-Claim-Sub":    "alice",
+			"Fn-Jwt-Claim-Aud":    "orders",
+			"Fn-Jwt-Claim-Groups": `["dev","admin"]`,
+			"Fn-Jwt-Claim-Role":   "",
+		}},
+		{"/t/myapp/mytrigger", "", http.StatusUnauthorized, nil},
+		{"/t/myapp/mytrigger", "Bearer " + missingClaim, http.StatusForbidden, nil},
+		{"/t/myapp/mytrigger", "bearer " + valid, http.StatusOK, map[string]string{
+			"Fn-Jwt-Claim-Sub":            "alice",
+			"Fn-Http-H-Fn-Jwt-Claim-Sub":  "",
+			"Fn-Http-H-Fn-Jwt-Claim-Role": "",
+			"Fn-Http-H-Authorization":     "bearer " + valid,
+			"Fn-Jwt-Claim-Role":           "",
+		}},
+	} {
+		req := createRequest(t, "POST", tc.path, strings.NewReader("hello"))
+		if tc.authorization != "" {
+			req.Header.Set("Authorization", tc.authorization)
+		}
+		// claims can't be forged by sending them as headers
+		req.Header.Set("Fn-Jwt-Claim-Role", "admin")
+		_, rec := routerRequest2(t, srv.Router, req)
+
+		if rec.Code != tc.expectedCode {
+			t.Fatalf("Test %d: expected status %d, got %d: %s", i, tc.expectedCode, rec.Code, rec.Body.String())
+		}
+		if rec.Code == http.StatusUnauthorized && !strings.HasPrefix(rec.Header().Get("WWW-Authenticate"), "Bearer") {
+			t.Fatalf("Test %d: expected a bearer challenge, got %q", i, rec.Header().Get("WWW-Authenticate"))
+		}
+		select {
+		case h := <-headers:
+			if tc.expectedCode != http.StatusOK {
+				t.Fatalf("Test %d: expected no call to be made", i)
+			}
+			for k, v := range tc.expectedHeaders {
+				if got := h.Get(k); got != v {
+					t.Fatalf("Test %d: expected %s to be %q, got %q", i, k, v, got)
+				}
+			}
+		default:
+			if tc.expectedCode == http.StatusOK {
+				t.Fatalf("Test %d: expected a call to be made", i)
+			}
+		}
+	}
+}
+
+func TestJWTTrafficSplit(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	// calls split to the canary have to meet its claims, not only those of the fn called
+	canaryAnnotations, _ := models.EmptyAnnotations().With(models.JWTClaimsAnnotation, map[string]string{"groups": "admin"})
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	canary := &models.Fn{ID: "canary_id", Name: "canary", AppID: app.ID, Image: "fnproject/fn-test-utils", Annotations: canaryAnnotations}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils",
+		Traffic: &models.FnTraffic{Splits: []models.TrafficSplit{{FnID: canary.ID, Weight: 100}}}}
+	trigger := &models.Trigger{ID: "trigger_id", Name: "mytrigger", AppID: app.ID, FnID: fn.ID, Type: "http", Source: "/mytrigger"}
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{canary, fn}, []*models.Trigger{trigger})
+
+	key, err := jwt.NewKey("", "HS256", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fnIDs := make(chan string, 10)
+	srv := testServer(ds, fnIDAgent(fnIDs), ServerTypeFull, WithJWTVerifier(jwt.NewVerifier([]*jwt.Key{key}, "")))
+
+	exp := time.Now().Add(time.Hour).Unix()
+	admin := hs256Token("secret", map[string]interface{}{"sub": "alice", "groups": "admin", "exp": exp})
+	dev := hs256Token("secret", map[string]interface{}{"sub": "bob", "groups": "dev", "exp": exp})
+
+	for i, tc := range []struct {
+		path         string
+		token        string
+		expectedCode int
+	}{
+		{"/invoke/fn_id", dev, http.StatusForbidden},
+		{"/t/myapp/mytrigger", dev, http.StatusForbidden},
+		{"/invoke/fn_id", admin, http.StatusOK},
+		{"/t/myapp/mytrigger", admin, http.StatusOK},
+	} {
+		req := createRequest(t, "POST", tc.path, strings.NewReader("hello"))
+		req.Header.Set("Authorization", "Bearer "+tc.token)
+		_, rec := routerRequest2(t, srv.Router, req)
+
+		if rec.Code != tc.expectedCode {
+			t.Fatalf("Test %d: expected status %d, got %d: %s", i, tc.expectedCode, rec.Code, rec.Body.String())
+		}
+		select {
+		case fnID := <-fnIDs:
+			if tc.expectedCode != http.StatusOK || fnID != canary.ID {
+				t.Fatalf("Test %d: expected no call to be made, got one to %s", i, fnID)
+			}
+		default:
+			if tc.expectedCode == http.StatusOK {
+				t.Fatalf("Test %d: expected a call to be made", i)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	"github.com/fnproject/fn/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// verifyTriggerSignature checks that a request to trigger is signed as its
+// signature requires, so that forged requests are refused before any fn is
+// called. The body has to be read whole to verify it, up to the maximum size
+// of signed bodies, what is read is put back for the call.
+func (s *Server) verifyTriggerSignature(c *gin.Context, trigger *models.Trigger) error {
+	req := c.Request
+	// the secret is stored sealed, and only ever opened here
+	if s.secretStore == nil {
+		return models.ErrSecretsNotSupported
+	}
+	sig := *trigger.Signature
+	secret, err := s.secretStore.Decrypt(req.Context(), sig.Secret)
+	if err != nil {
+		return err
+	}
+	sig.Secret = secret
+
+	if req.ContentLength > s.maxSignedBodySize {
+		return models.ErrTriggerSignedBodyTooBig
+	}
+	var body []byte
+	if req.Body != nil {
+		body, err = ioutil.ReadAll(io.LimitReader(req.Body, s.maxSignedBodySize+1))
+		req.Body.Close()
+		if err != nil {
+			return models.ErrInvalidPayload
+		}
+		if int64(len(body)) > s.maxSignedBodySize {
+			return models.ErrTriggerSignedBodyTooBig
+		}
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if !sig.Verify(req.Header, body) {
+		return models.ErrTriggerSignatureMismatch
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/api/secrets"
+)
+
+func TestTriggerSignature(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	ss, err := secrets.NewLocal(bytes.Repeat([]byte{1}, secrets.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := ss.Encrypt(context.Background(), "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	signed := &models.Trigger{ID: "signed_id", Name: "signed", AppID: app.ID, FnID: fn.ID, Type: "http", Source: "/signed",
+		Methods:   models.HTTPMethods{"POST"},
+		Signature: &models.TriggerSignature{Secret: sealed, Header: "X-Hub-Signature-256", Algorithm: "sha256", Prefix: "sha256="}}
+	plain := &models.Trigger{ID: "plain_id", Name: "plain", AppID: app.ID, FnID: fn.ID, Type: "http", Source: "/plain"}
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn}, []*models.Trigger{signed, plain})
+
+	headers := make(chan http.Header, 10)
+	srv := testServer(ds, shoutingAgent(headers), ServerTypeFull, WithSecretStore(ss), WithMaxSignedBodySize(16))
+
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(body))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	for i, tc := range []struct {
+		method       string
+		path         string
+		body         string
+		signature    string
+		expectedCode int
+		expectedBody string
+	}{
+		{"POST", "/t/myapp/signed", "hello", sign("hello"), http.StatusOK, "HELLO"},
+		{"POST", "/t/myapp/signed", "", sign(""), http.StatusOK, ""},
+		{"POST", "/t/myapp/signed", "hello", sign("forged"), http.StatusUnauthorized, ""},
+		{"POST", "/t/myapp/signed", "hello", "", http.StatusUnauthorized, ""},
+		{"POST", "/t/myapp/signed", "hello", "sha256=zz", http.StatusUnauthorized, ""},
+		// bodies are only read up to the maximum size to verify them
+		{"POST", "/t/myapp/signed", strings.Repeat("a", 16), sign(strings.Repeat("a", 16)), http.StatusOK, strings.Repeat("A", 16)},
+		{"POST", "/t/myapp/signed", strings.Repeat("a", 17), sign(strings.Repeat("a", 17)), http.StatusRequestEntityTooLarge, ""},
+		// the method is checked before the signature
+		{"GET", "/t/myapp/signed", "hello", "", http.StatusMethodNotAllowed, ""},
+		// triggers without a signature let any request through
+		{"POST", "/t/myapp/plain", "hello", "", http.StatusOK, "HELLO"},
+	} {
+		req := createRequest(t, tc.method, tc.path, strings.NewReader(tc.body))
+		if tc.signature != "" {
+			req.Header.Set("X-Hub-Signature-256", tc.signature)
+		}
+		_, rec := routerRequest2(t, srv.Router, req)
+
+		if rec.Code != tc.expectedCode {
+			t.Fatalf("Test %d: expected status %d, got %d: %s", i, tc.expectedCode, rec.Code, rec.Body.String())
+		}
+		select {
+		case <-headers:
+			if tc.expectedCode != http.StatusOK {
+				t.Fatalf("Test %d: expected no call to be made", i)
+			}
+			if rec.Body.String() != tc.expectedBody {
+				t.Fatalf("Test %d: expected body %q, got %q", i, tc.expectedBody, rec.Body.String())
+			}
+		default:
+			if tc.expectedCode == http.StatusOK {
+				t.Fatalf("Test %d: expected a call to be made", i)
+			}
+		}
+	}
+}
+
+func TestTriggerSignatureSecret(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	ctx := context.Background()
+	ss, err := secrets.NewLocal(bytes.Repeat([]byte{1}, secrets.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn})
+	srv := testServer(ds, shoutingAgent(nil), ServerTypeFull, WithSecretStore(ss))
+
+	request := func(method, path, body string) *models.Trigger {
+		_, rec := routerRequest(t, srv.Router, method, path, bytes.NewBufferString(body))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected %d for %s %s, got %d: %s", http.StatusOK, method, path, rec.Code, rec.Body.String())
+		}
+		if strings.Contains(rec.Body.String(), "hunter2") {
+			t.Fatalf("expected the signature secret to be redacted from %s %s, got %s", method, path, rec.Body.String())
+		}
+		var trigger models.Trigger
+		if err := json.NewDecoder(rec.Body).Decode(&trigger); err != nil {
+			t.Fatal(err)
+		}
+		return &trigger
+	}
+	storedSecret := func(id string) string {
+		stored, err := ds.GetTriggerByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		secret, err := ss.Decrypt(ctx, stored.Signature.Secret)
+		if err != nil {
+			t.Fatalf("expected the signature secret to be stored encrypted, got %q: %v", stored.Signature.Secret, err)
+		}
+		return secret
+	}
+
+	trigger := request("POST", "/v2/triggers", `{"name": "signed", "app_id": "app_id", "fn_id": "fn_id", "type": "http", "source": "/signed",
+		"signature": {"secret": "hunter2", "header": "X-Hub-Signature-256", "algorithm": "sha256"}}`)
+	if trigger.Signature == nil || trigger.Signature.Secret != models.RedactedSecret {
+		t.Fatalf("expected the signature secret to be redacted, got %v", trigger.Signature)
+	}
+	if secret := storedSecret(trigger.ID); secret != "hunter2" {
+		t.Fatalf("expected the stored secret to open to hunter2, got %q", secret)
+	}
+
+	request("GET", "/v2/triggers/"+trigger.ID, "")
+	request("GET", "/v2/triggers?app_id=app_id", "")
+
+	// a trigger read back with its redacted secret keeps the secret it has
+	trigger = request("PUT", "/v2/triggers/"+trigger.ID, `{"signature": {"secret": "[redacted]", "header": "X-Signature", "algorithm": "sha256"}}`)
+	if trigger.Signature.Header != "X-Signature" {
+		t.Fatalf("expected the signature to be updated, got %v", trigger.Signature)
+	}
+	if secret := storedSecret(trigger.ID); secret != "hunter2" {
+		t.Fatalf("expected a redacted secret to keep the stored one, got %q", secret)
+	}
+
+	// secrets can't be stored without a secret store to seal them
+	noStore := testServer(datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn}), shoutingAgent(nil), ServerTypeFull)
+	_, rec := routerRequest(t, noStore.Router, "POST", "/v2/triggers", bytes.NewBufferString(`{"name": "signed", "app_id": "app_id", "fn_id": "fn_id", "type": "http", "source": "/signed",
+		"signature": {"secret": "hunter2", "header": "X-Hub-Signature-256", "algorithm": "sha256"}}`))
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("expected %d without a secret store, got %d: %s", http.StatusNotImplemented, rec.Code, rec.Body.String())
+	}
+}
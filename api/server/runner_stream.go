@@ -0,0 +1,89 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/models"
+)
+
+// streamResponseWriter writes the response of a call straight through to the
+// client, flushing each write so that the client sees output as the function
+// produces it.
+type streamResponseWriter struct {
+	http.ResponseWriter
+
+	lock    sync.Mutex
+	started bool
+	closed  bool
+}
+
+func (w *streamResponseWriter) WriteHeader(code int) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.closed {
+		return
+	}
+	w.started = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *streamResponseWriter) Write(b []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	w.started = true
+	n, err := w.ResponseWriter.Write(b)
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+	return n, err
+}
+
+// close stops writes from the call once its submit returned, some may still
+// be in flight if it timed out, and tells if the response was started.
+func (w *streamResponseWriter) close() bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.closed = true
+	return w.started
+}
+
+// isStreamed tells if the response of a call should be streamed, which the
+// request asks for with the Fn-Invoke-Stream header or the fn with its
+// streamResponse annotation.
+func isStreamed(req *http.Request, fn *models.Fn) bool {
+	if strings.EqualFold(req.Header.Get("Fn-Invoke-Stream"), "true") {
+		return true
+	}
+	stream, err := fn.Annotations.GetBool(models.FnStreamResponseAnnotation)
+	return err == nil && stream
+}
+
+// streamFnInvoke runs a call, writing its response to the client as it is
+// produced rather than once the call completes. Errors that happen once the
+// response started can't be reported to the client, which sees the response
+// end early.
+func (s *Server) streamFnInvoke(resp http.ResponseWriter, req *http.Request, app *models.App, fn *models.Fn, trig *models.Trigger) error {
+	writer := &streamResponseWriter{ResponseWriter: resp}
+
+	call, err := s.agent.GetCall(getCallOptions(req, app, fn, trig, writer)...)
+	if err != nil {
+		return err
+	}
+
+	// add this before submit, always tie a call id to the response at this point
+	writer.Header().Add("Fn-Call-Id", call.Model().ID)
+
+	err = s.agent.Submit(call)
+	if started := writer.close(); err != nil && started {
+		common.Logger(req.Context()).WithError(err).Info("streamed call failed after its response started")
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fnproject/fn/api/agent"
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/models"
+	pool "github.com/fnproject/fn/api/runnerpool"
+	"github.com/stretchr/testify/mock"
+)
+
+// streamingAgent returns an agent whose calls write an event, wait for proceed
+// and then write another before failing with err
+func streamingAgent(proceed chan struct{}, err error) *agent.MockAgent {
+	a := new(agent.MockAgent)
+	a.On("GetCall", mock.Anything).Return()
+	a.On("Submit", mock.Anything).Run(func(args mock.Arguments) {
+		w := args.Get(0).(pool.RunnerCall).ResponseWriter()
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("data: first\n\n"))
+		<-proceed
+		w.Write([]byte("data: second\n\n"))
+	}).Return(err)
+	return a
+}
+
+func TestFnInvokeStreamed(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	streamed := &models.Fn{ID: "streamed_id", Name: "streamed", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	streamed.Annotations, _ = streamed.Annotations.With(models.FnStreamResponseAnnotation, true)
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn, streamed})
+
+	for i, test := range []struct {
+		fnID   string
+		header string
+		err    error
+	}{
+		{fn.ID, "true", nil},
+		{streamed.ID, "", nil},
+		// the response started, so the error can only cut it short
+		{streamed.ID, "", models.ErrCallTimeout},
+	} {
+		proceed := make(chan struct{})
+		srv := testServer(ds, streamingAgent(proceed, test.err), ServerTypeFull)
+		ts := httptest.NewServer(srv.Router)
+
+		req, err := http.NewRequest("POST", ts.URL+"/invoke/"+test.fnID, strings.NewReader("hello"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.header != "" {
+			req.Header.Set("Fn-Invoke-Stream", test.header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i, err)
+		}
+
+		// the first event arrives while the call is still running
+		r := bufio.NewReader(resp.Body)
+		line, err := r.ReadString('\n')
+		if err != nil || line != "data: first\n" {
+			t.Fatalf("Test %d: expected the first event before the call completed, got %q %v", i, line, err)
+		}
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Fn-Call-Id") == "" || len(resp.TransferEncoding) == 0 {
+			t.Fatalf("Test %d: expected a chunked response of the call, got %d %v", i, resp.StatusCode, resp.Header)
+		}
+
+		close(proceed)
+		rest, err := ioutil.ReadAll(r)
+		if err != nil || string(rest) != "\ndata: second\n\n" {
+			t.Fatalf("Test %d: expected the rest of the response, got %q %v", i, rest, err)
+		}
+		resp.Body.Close()
+		ts.Close()
+	}
+}
+
+func TestFnInvokeNotStreamed(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn})
+
+	proceed := make(chan struct{})
+	close(proceed)
+	srv := testServer(ds, streamingAgent(proceed, errors.New("failed")), ServerTypeFull)
+
+	// buffered responses are replaced by the error
+	req := createRequest(t, "POST", "/invoke/fn_id", strings.NewReader("hello"))
+	_, rec := routerRequest2(t, srv.Router, req)
+	if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "data:") {
+		t.Fatalf("expected the error of the call rather than its response, got %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/models"
+)
+
+// routeTraffic picks the fn a call to fn is served by, if fn splits its
+// traffic. Calls with the same value of the sticky header of the split go to
+// the same fn, any other call is sent to one at random by weight. The fns
+// calls are split to don't split them any further, and if one is gone its
+// share of the calls is served by fn itself.
+func (s *Server) routeTraffic(req *http.Request, fn *models.Fn) *models.Fn {
+	t := fn.Traffic
+	if t == nil {
+		return fn
+	}
+
+	var bucket int
+	if key := req.Header.Get(t.StickyHeader); t.StickyHeader != "" && key != "" {
+		h := fnv.New32a()
+		h.Write([]byte(fn.ID))
+		h.Write([]byte(key))
+		bucket = int(h.Sum32() % 100)
+	} else {
+		bucket = rand.Intn(100)
+	}
+
+	fnID := t.Pick(bucket)
+	if fnID == "" {
+		return fn
+	}
+	ctx := req.Context()
+	target, err := s.lbReadAccess.GetFnByID(ctx, fnID)
+	if err != nil || target.AppID != fn.AppID {
+		common.Logger(ctx).WithError(err).WithField("traffic_fn_id", fnID).Warn("Cannot split traffic to fn, serving the call with the fn split from")
+		return fn
+	}
+	return target
+}
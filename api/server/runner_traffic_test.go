@@ -0,0 +1,75 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/models"
+)
+
+func TestTrafficSplit(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	canary := &models.Fn{ID: "canary_id", Name: "canary", AppID: app.ID, Image: "fnproject/fn-test-utils:candidate"}
+	all := &models.Fn{ID: "all_id", Name: "all", AppID: app.ID, Image: "fnproject/fn-test-utils",
+		Traffic: &models.FnTraffic{Splits: []models.TrafficSplit{{FnID: canary.ID, Weight: 100}}}}
+	sticky := &models.Fn{ID: "sticky_id", Name: "sticky", AppID: app.ID, Image: "fnproject/fn-test-utils",
+		Traffic: &models.FnTraffic{Splits: []models.TrafficSplit{{FnID: canary.ID, Weight: 50}}, StickyHeader: "X-User-Id"}}
+	gone := &models.Fn{ID: "gone_id", Name: "gone", AppID: app.ID, Image: "fnproject/fn-test-utils",
+		Traffic: &models.FnTraffic{Splits: []models.TrafficSplit{{FnID: "nonexistent", Weight: 100}}}}
+	trigger := &models.Trigger{ID: "trigger_id", Name: "mytrigger", AppID: app.ID, FnID: all.ID, Type: "http", Source: "/mytrigger"}
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{canary, all, sticky, gone}, []*models.Trigger{trigger})
+
+	fnIDs := make(chan string, 1)
+	srv := testServer(ds, fnIDAgent(fnIDs), ServerTypeFull)
+
+	call := func(path, user string) string {
+		req := createRequest(t, "POST", path, nil)
+		if user != "" {
+			req.Header.Set("X-User-Id", user)
+		}
+		_, rec := routerRequest2(t, srv.Router, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected %d calling %s, got %d: %s", http.StatusOK, path, rec.Code, rec.Body.String())
+		}
+		return <-fnIDs
+	}
+
+	for i, tc := range []struct {
+		path     string
+		expected string
+	}{
+		{"/invoke/all_id", canary.ID},
+		{"/t/myapp/mytrigger", canary.ID},
+		{"/invoke/canary_id", canary.ID},
+		{"/invoke/gone_id", gone.ID},
+	} {
+		if got := call(tc.path, ""); got != tc.expected {
+			t.Errorf("Test %d: expected %s to be served by %s, got %s", i, tc.path, tc.expected, got)
+		}
+	}
+
+	// calls from a user stay with the fn they first went to, and users are split between both
+	served := make(map[string]bool)
+	for u := 0; u < 20; u++ {
+		user := fmt.Sprintf("user-%d", u)
+		first := call("/invoke/sticky_id", user)
+		for j := 0; j < 3; j++ {
+			if got := call("/invoke/sticky_id", user); got != first {
+				t.Fatalf("expected calls from %s to stick to %s, got %s", user, first, got)
+			}
+		}
+		served[first] = true
+	}
+	if !served[sticky.ID] || !served[canary.ID] {
+		t.Errorf("expected users to be split between both fns, got %v", served)
+	}
+}
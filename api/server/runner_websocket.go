@@ -0,0 +1,107 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/fnproject/fn/api/common"
+	"github.com/fnproject/fn/api/id"
+	"github.com/fnproject/fn/api/models"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// WebSocketConnectionIDHeader carries the id of the websocket connection a message arrived on
+const WebSocketConnectionIDHeader = "Fn-Ws-Connection-Id"
+
+// webSocketEnabled tells if clients may upgrade requests to trigger to websockets
+func webSocketEnabled(trigger *models.Trigger) bool {
+	enabled, err := trigger.Annotations.GetBool(models.TriggerWebSocketAnnotation)
+	return err == nil && enabled
+}
+
+// serveWebSocketTrigger upgrades a request to an http trigger to a websocket,
+// and holds the connection until either end closes it. Each message from the
+// client is a call of fn, made in the order the messages arrive, and the body
+// of its response, if any, is sent back to the client as a message of the
+// same type. Calls see the headers of the upgrade request, as they would for
+// any request to the trigger, with the message type as their Content-Type.
+// A call that fails closes the connection.
+func (s *Server) serveWebSocketTrigger(c *gin.Context, app *models.App, fn *models.Fn, trigger *models.Trigger) error {
+	req := c.Request
+	ws, err := upgradeWebSocket(c.Writer, req, s.wsIdleTimeout)
+	if err != nil {
+		return err
+	}
+
+	// the handshake is done with, the rest of the upgrade request goes to every call
+	for k := range req.Header {
+		if strings.HasPrefix(k, "Sec-Websocket-") {
+			delete(req.Header, k)
+		}
+	}
+	method := req.Method
+	header := httpTriggerHeaders(req, httpTriggerParams(c, trigger))
+	for k, vs := range jwtClaimHeaders(c) {
+		header[k] = vs
+	}
+	header.Set("Fn-Intent", "websocketmessage")
+	header.Set(WebSocketConnectionIDHeader, id.New().String())
+
+	// the connection is no longer http, from here errors go to the client as close frames
+	ctx, log := common.LoggerWithFields(req.Context(), logrus.Fields{"ws_connection_id": header.Get(WebSocketConnectionIDHeader)})
+	for {
+		op, msg, err := ws.readMessage()
+		switch err {
+		case nil:
+		case errWSClosed:
+			return nil
+		case errWSTooBig:
+			ws.close(wsCloseTooBig, err.Error())
+			return nil
+		case errWSIdle:
+			ws.close(wsCloseGoingAway, err.Error())
+			return nil
+		default:
+			log.WithError(err).Debug("websocket read failed")
+			ws.close(wsCloseProtocol, "")
+			return nil
+		}
+
+		msgReq, err := http.NewRequest(method, req.URL.String(), bytes.NewReader(msg))
+		if err != nil {
+			ws.close(wsCloseServerError, "")
+			return nil
+		}
+		msgReq = msgReq.WithContext(ctx)
+		msgReq.Host = req.Host
+		for k, vs := range header {
+			msgReq.Header[k] = vs
+		}
+		if op == wsOpText {
+			msgReq.Header.Set("Content-Type", "text/plain; charset=utf-8")
+		} else {
+			msgReq.Header.Set("Content-Type", "application/octet-stream")
+		}
+
+		resp := &syncResponseWriter{
+			headers: make(http.Header),
+			status:  200,
+			Buffer:  new(bytes.Buffer),
+		}
+		if err := s.fnInvoke(&triggerResponseWriter{inner: resp}, msgReq, app, fn, trigger); err != nil {
+			log.WithError(err).Info("websocket message call failed, closing connection")
+			ws.close(wsCloseServerError, err.Error())
+			return nil
+		}
+
+		if resp.Len() > 0 {
+			if err := ws.writeMessage(op, resp.Bytes()); err != nil {
+				log.WithError(err).Debug("websocket write failed")
+				ws.close(wsCloseServerError, "")
+				return nil
+			}
+		}
+	}
+}
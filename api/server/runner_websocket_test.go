@@ -0,0 +1,219 @@
+package server
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/models"
+)
+
+// writeClientFrame writes a masked frame, as clients must
+func writeClientFrame(t *testing.T, conn net.Conn, fin bool, op byte, payload []byte) {
+	b0 := op
+	if fin {
+		b0 |= 0x80
+	}
+	mask := []byte{1, 2, 3, 4}
+	frame := []byte{b0, 0x80 | byte(len(payload))}
+	frame = append(frame, mask...)
+	for i, c := range payload {
+		frame = append(frame, c^mask[i%4])
+	}
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readServerFrame(t *testing.T, r *bufio.Reader) (byte, []byte) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		t.Fatal(err)
+	}
+	if hdr[1]&0x80 != 0 {
+		t.Fatal("expected frames from the server not to be masked")
+	}
+	size := int(hdr[1] & 0x7f)
+	if size == 126 {
+		var ext [2]byte
+		io.ReadFull(r, ext[:])
+		size = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatal(err)
+	}
+	return hdr[0] & 0x0f, payload
+}
+
+func TestHTTPTriggerWebSocket(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	ws := &models.Trigger{ID: "ws_id", Name: "ws", AppID: app.ID, FnID: fn.ID, Type: "http", Source: "/chat"}
+	ws.Annotations, _ = ws.Annotations.With(models.TriggerWebSocketAnnotation, true)
+	plain := &models.Trigger{ID: "plain_id", Name: "plain", AppID: app.ID, FnID: fn.ID, Type: "http", Source: "/plain"}
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn}, []*models.Trigger{ws, plain})
+
+	headers := make(chan http.Header, 10)
+	srv := testServer(ds, shoutingAgent(headers), ServerTypeFull)
+	ts := httptest.NewServer(srv.Router)
+	defer ts.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// the example handshake of RFC 6455
+	conn.Write([]byte("GET /t/myapp/chat HTTP/1.1\r\nHost: example.com\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\nX-User: alice\r\n\r\n"))
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected upgrade, got %d", resp.StatusCode)
+	}
+	if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected accept %q", accept)
+	}
+
+	writeClientFrame(t, conn, true, wsOpText, []byte("hello"))
+	op, msg := readServerFrame(t, r)
+	if op != wsOpText || string(msg) != "HELLO" {
+		t.Fatalf("expected the call response as a text message, got %d %q", op, msg)
+	}
+	h := <-headers
+	if h.Get("Fn-Intent") != "websocketmessage" || h.Get("Fn-Http-H-X-User") != "alice" || h.Get(WebSocketConnectionIDHeader) == "" {
+		t.Fatalf("expected calls to see the upgrade request, got %v", h)
+	}
+	if h.Get("Fn-Http-H-Sec-Websocket-Key") != "" || h.Get("Fn-Http-H-Upgrade") != "" {
+		t.Fatalf("expected handshake headers to be left out, got %v", h)
+	}
+
+	// nothing comes back for empty responses, pings are answered and fragments put together
+	writeClientFrame(t, conn, true, wsOpBinary, []byte("quiet"))
+	writeClientFrame(t, conn, true, wsOpPing, []byte("ping"))
+	op, msg = readServerFrame(t, r)
+	if op != wsOpPong || string(msg) != "ping" {
+		t.Fatalf("expected pong, got %d %q", op, msg)
+	}
+	writeClientFrame(t, conn, false, wsOpBinary, []byte("good"))
+	writeClientFrame(t, conn, true, wsOpContinuation, []byte("bye"))
+	op, msg = readServerFrame(t, r)
+	if op != wsOpBinary || string(msg) != "GOODBYE" {
+		t.Fatalf("expected the call response as a binary message, got %d %q", op, msg)
+	}
+	for i := 0; i < 2; i++ {
+		if id := (<-headers).Get(WebSocketConnectionIDHeader); id != h.Get(WebSocketConnectionIDHeader) {
+			t.Fatalf("expected every message on a connection to have its id, got %q", id)
+		}
+	}
+
+	writeClientFrame(t, conn, true, wsOpClose, []byte{0x03, 0xe8})
+	op, msg = readServerFrame(t, r)
+	if op != wsOpClose || binary.BigEndian.Uint16(msg) != wsCloseNormal {
+		t.Fatalf("expected close to be answered, got %d %v", op, msg)
+	}
+
+	// triggers that don't allow websockets see upgrade requests as any other
+	req, _ := http.NewRequest("GET", ts.URL+"/t/myapp/plain", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	plainResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plainResp.Body.Close()
+	if plainResp.StatusCode != http.StatusOK {
+		t.Fatalf("expected a plain call, got %d", plainResp.StatusCode)
+	}
+
+	// bad handshakes are rejected before the upgrade
+	req, _ = http.NewRequest("GET", ts.URL+"/t/myapp/chat", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "8")
+	badResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	badResp.Body.Close()
+	if badResp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected bad handshake to be rejected, got %d", badResp.StatusCode)
+	}
+}
+
+func TestHTTPTriggerWebSocketIdle(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	app := &models.App{ID: "app_id", Name: "myapp"}
+	fn := &models.Fn{ID: "fn_id", Name: "myfn", AppID: app.ID, Image: "fnproject/fn-test-utils"}
+	ws := &models.Trigger{ID: "ws_id", Name: "ws", AppID: app.ID, FnID: fn.ID, Type: "http", Source: "/chat"}
+	ws.Annotations, _ = ws.Annotations.With(models.TriggerWebSocketAnnotation, true)
+	ds := datastore.NewMockInit([]*models.App{app}, []*models.Fn{fn}, []*models.Trigger{ws})
+
+	srv := testServer(ds, shoutingAgent(make(chan http.Header, 10)), ServerTypeFull, WithWebSocketIdleTimeout(200*time.Millisecond))
+	ts := httptest.NewServer(srv.Router)
+	defer ts.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	conn.Write([]byte("GET /t/myapp/chat HTTP/1.1\r\nHost: example.com\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"))
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected upgrade, got %d", resp.StatusCode)
+	}
+
+	// pings keep the connection open past the idle timeout
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		time.Sleep(100 * time.Millisecond)
+		writeClientFrame(t, conn, true, wsOpPing, []byte("ping"))
+		if op, msg := readServerFrame(t, r); op != wsOpPong || string(msg) != "ping" {
+			t.Fatalf("expected pong, got %d %q", op, msg)
+		}
+	}
+
+	// a quiet one is closed
+	op, msg := readServerFrame(t, r)
+	if op != wsOpClose || binary.BigEndian.Uint16(msg) != wsCloseGoingAway {
+		t.Fatalf("expected idle connection to be closed, got %d %v", op, msg)
+	}
+	if time.Since(start) < 400*time.Millisecond {
+		t.Fatal("expected pings to hold the connection")
+	}
+}
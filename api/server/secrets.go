@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+
+	"github.com/fnproject/fn/api/models"
+)
+
+// sealSecretConfig encrypts the values of the secret config of a request in
+// place, so that only their ciphertexts reach the datastore. Empty values,
+// which remove entries, are left as they are, and redacted ones are dropped
+// so that the entries keep the values they have.
+func (s *Server) sealSecretConfig(ctx context.Context, conf models.Config) error {
+	for k, v := range conf {
+		switch v {
+		case "":
+		case models.RedactedSecret:
+			delete(conf, k)
+		default:
+			if s.secretStore == nil {
+				return models.ErrSecretsNotSupported
+			}
+			sealed, err := s.secretStore.Encrypt(ctx, v)
+			if err != nil {
+				return err
+			}
+			conf[k] = sealed
+		}
+	}
+	return nil
+}
+
+// sealTriggerSignature encrypts the secret of the signature of trigger, a
+// request to create it or, if stored is set, to update it, in place. A
+// redacted secret is replaced by the one stored, which is sealed already.
+func (s *Server) sealTriggerSignature(ctx context.Context, trigger, stored *models.Trigger) error {
+	sig := trigger.Signature
+	switch {
+	case sig == nil || sig.Secret == "":
+	case sig.Secret == models.RedactedSecret:
+		sig.Secret = ""
+		if stored != nil && stored.Signature != nil {
+			sig.Secret = stored.Signature.Secret
+		}
+	case s.secretStore == nil:
+		return models.ErrSecretsNotSupported
+	default:
+		sealed, err := s.secretStore.Encrypt(ctx, sig.Secret)
+		if err != nil {
+			return err
+		}
+		sig.Secret = sealed
+	}
+	return nil
+}
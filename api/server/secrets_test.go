@@ -0,0 +1,132 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/api/secrets"
+)
+
+func TestSecretConfig(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	ctx := context.Background()
+	ss, err := secrets.NewLocal(bytes.Repeat([]byte{1}, secrets.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := datastore.NewMockInit()
+	srv := testServer(ds, shoutingAgent(nil), ServerTypeFull, WithSecretStore(ss))
+
+	request := func(method, path, body string, v interface{}) {
+		_, rec := routerRequest(t, srv.Router, method, path, bytes.NewBufferString(body))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected %d for %s %s, got %d: %s", http.StatusOK, method, path, rec.Code, rec.Body.String())
+		}
+		if strings.Contains(rec.Body.String(), "hunter2") {
+			t.Fatalf("expected secret config to be redacted from %s %s, got %s", method, path, rec.Body.String())
+		}
+		if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	decrypt := func(sealed string) string {
+		plaintext, err := ss.Decrypt(ctx, sealed)
+		if err != nil {
+			t.Fatalf("expected secret config to be stored encrypted, got %q: %v", sealed, err)
+		}
+		return plaintext
+	}
+
+	var app models.App
+	request("POST", "/v2/apps", `{"name": "myapp", "config": {"DB_USER": "fn"}, "secret_config": {"DB_PASSWORD": "hunter2"}}`, &app)
+	if app.SecretConfig["DB_PASSWORD"] != models.RedactedSecret || app.Config["DB_USER"] != "fn" {
+		t.Errorf("expected only the secret config of the app to be redacted, got %v and %v", app.Config, app.SecretConfig)
+	}
+	stored, err := ds.GetAppByID(ctx, app.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed := stored.SecretConfig["DB_PASSWORD"]
+	if decrypt(sealed) != "hunter2" {
+		t.Errorf("expected the stored secret config to decrypt to its value, got %q", decrypt(sealed))
+	}
+
+	// writing back a redacted app leaves its secrets as they are
+	request("PUT", "/v2/apps/"+app.ID, `{"secret_config": {"DB_PASSWORD": "[redacted]", "API_KEY": "hunter2-key"}}`, &app)
+	stored, err = ds.GetAppByID(ctx, app.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.SecretConfig["DB_PASSWORD"] != sealed || decrypt(stored.SecretConfig["API_KEY"]) != "hunter2-key" {
+		t.Errorf("expected the redacted secret to be kept and the new one to be added, got %v", stored.SecretConfig)
+	}
+
+	var apps models.AppList
+	request("GET", "/v2/apps", "", &apps)
+	request("GET", "/v2/apps/"+app.ID, "", &app)
+	if len(app.SecretConfig) != 2 {
+		t.Errorf("expected the app to have 2 secrets, got %v", app.SecretConfig)
+	}
+
+	var fn models.Fn
+	request("POST", "/v2/fns", `{"app_id": "`+app.ID+`", "name": "myfn", "image": "fnproject/fn-test-utils", "secret_config": {"API_KEY": "hunter2-fn"}}`, &fn)
+	if fn.SecretConfig["API_KEY"] != models.RedactedSecret {
+		t.Errorf("expected the secret config of the fn to be redacted, got %v", fn.SecretConfig)
+	}
+	var fns models.FnList
+	request("GET", "/v2/fns?app_id="+app.ID, "", &fns)
+	request("PUT", "/v2/fns/"+fn.ID, `{"secret_config": {"API_KEY": "hunter2-fn2"}}`, &fn)
+	request("GET", "/v2/fns/"+fn.ID, "", &fn)
+
+	// revisions record the sealed secret config, which rollbacks restore
+	var revisions models.FnRevisionList
+	request("GET", "/v2/fns/"+fn.ID+"/revisions", "", &revisions)
+	if len(revisions.Items) != 1 || revisions.Items[0].SecretConfig["API_KEY"] != models.RedactedSecret {
+		t.Fatalf("expected a revision with the secret config redacted, got %#v", revisions.Items)
+	}
+	request("POST", "/v2/fns/"+fn.ID+"/rollback", "", &fn)
+	storedFn, err := ds.GetFnByID(ctx, fn.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypt(storedFn.SecretConfig["API_KEY"]) != "hunter2-fn" {
+		t.Errorf("expected the rollback to restore the secret config, got %v", storedFn.SecretConfig)
+	}
+	request("POST", "/v2/fns/"+fn.ID+"/rollback", "", &fn)
+
+	// LBs read the sealed secrets, to decrypt them as they make calls
+	_, rec := routerRequest(t, srv.Router, "GET", "/v2/runner/fns/"+fn.ID, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the runner to get the fn, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var runnerFn models.Fn
+	if err := json.NewDecoder(rec.Body).Decode(&runnerFn); err != nil {
+		t.Fatal(err)
+	}
+	if decrypt(runnerFn.SecretConfig["API_KEY"]) != "hunter2-fn2" {
+		t.Errorf("expected the runner to get the sealed secret config of the fn, got %v", runnerFn.SecretConfig)
+	}
+	_, rec = routerRequest(t, srv.Router, "GET", "/v2/runner/apps/"+app.ID, nil)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), sealed) {
+		t.Errorf("expected the runner to get the sealed secret config of the app, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// without a store secrets can't be set
+	srv = testServer(ds, shoutingAgent(nil), ServerTypeFull)
+	_, rec = routerRequest(t, srv.Router, "POST", "/v2/apps", bytes.NewBufferString(`{"name": "other", "secret_config": {"DB_PASSWORD": "hunter2"}}`))
+	if rec.Code != http.StatusNotImplemented || !strings.Contains(rec.Body.String(), models.ErrSecretsNotSupported.Error()) {
+		t.Errorf("expected %v without a secret store, got %d: %s", models.ErrSecretsNotSupported, rec.Code, rec.Body.String())
+	}
+}
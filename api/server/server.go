@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"path"
@@ -15,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 	"unicode"
 
 	"contrib.go.opencensus.io/exporter/jaeger"
@@ -30,10 +32,17 @@ import (
 
 	"github.com/fnproject/fn/api/agent"
 	"github.com/fnproject/fn/api/agent/hybrid"
+	"github.com/fnproject/fn/api/async"
+	"github.com/fnproject/fn/api/callstore"
 	"github.com/fnproject/fn/api/common"
 	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/jwt"
+	"github.com/fnproject/fn/api/logs"
 	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/api/mqs"
 	pool "github.com/fnproject/fn/api/runnerpool"
+	"github.com/fnproject/fn/api/scheduler"
+	"github.com/fnproject/fn/api/secrets"
 	"github.com/fnproject/fn/api/version"
 	"github.com/fnproject/fn/fnext"
 )
@@ -64,6 +73,23 @@ const (
 	// possible schemes: { postgres, sqlite3, mysql }
 	EnvDBURL = "FN_DB_URL"
 
+	// EnvCallStoreURL is a url to a store for call history, defaults to the
+	// datastore when it is able to store calls:
+	// possible schemes: { postgres, sqlite3, mysql }
+	EnvCallStoreURL = "FN_CALL_STORE_URL"
+
+	// EnvLogStoreURL is a url to a store for call logs, defaults to the
+	// datastore when it is able to store logs. Only full nodes record logs,
+	// runners don't send them back to LBs, so API nodes only serve logs from
+	// a store set with it:
+	// possible schemes: { postgres, sqlite3, mysql, file }
+	EnvLogStoreURL = "FN_LOG_STORE_URL"
+
+	// EnvMQURL is a url to a queue that detached calls wait on until they
+	// run, defaults to the datastore when it is able to queue calls:
+	// possible schemes: { postgres, sqlite3, mysql, memory }
+	EnvMQURL = "FN_MQ_URL"
+
 	// EnvRunnerURL is a url pointing to an Fn API service.
 	EnvRunnerURL = "FN_RUNNER_API_URL"
 
@@ -89,6 +115,17 @@ const (
 	// EnvAPICORSHeaders is the list of CORS headers allowed.
 	EnvAPICORSHeaders = "FN_API_CORS_HEADERS"
 
+	// EnvJWTKeys is a comma separated list of JWKS or PEM files with the keys
+	// that the bearer tokens calls must carry are verified against, if set.
+	EnvJWTKeys = "FN_JWT_KEYS"
+
+	// EnvJWTIssuer is the issuer the bearer tokens of calls must be from, any if not set.
+	EnvJWTIssuer = "FN_JWT_ISSUER"
+
+	// EnvSecretKeyFile is a file with the key that the secret config of apps
+	// and fns is encrypted with, secret config is not supported if not set.
+	EnvSecretKeyFile = "FN_SECRET_KEY_FILE"
+
 	// EnvZipkinURL is the url of a zipkin node to send traces to.
 	EnvZipkinURL = "FN_ZIPKIN_URL"
 
@@ -105,8 +142,20 @@ const (
 	EnvLBPlacementAlg = "FN_PLACER"
 
 	// EnvMaxRequestSize sets the limit in bytes for any API request body's length.
+	// Bodies of calls the lb buffers for retries spill to disk beyond agent.EnvRequestSpoolThreshold.
 	EnvMaxRequestSize = "FN_MAX_REQUEST_SIZE"
 
+	// EnvMaxSignedBodySize sets the limit in bytes for the bodies of requests
+	// to signed triggers, which are read whole to verify them.
+	EnvMaxSignedBodySize = "FN_MAX_SIGNED_REQUEST_SIZE"
+
+	// EnvWebSocketIdleTimeout sets how long websocket connections to http
+	// triggers are held without a frame from the client before they are closed.
+	EnvWebSocketIdleTimeout = "FN_WS_IDLE_TIMEOUT"
+
+	// EnvMaxInvokeDelay sets the longest delay detached calls may ask for in Fn-Invoke-Delay.
+	EnvMaxInvokeDelay = "FN_MAX_INVOKE_DELAY"
+
 	// EnvMaxHeaderSize sets the limit in bytes for any API request body's length.
 	EnvMaxHeaderSize = "FN_MAX_REQUEST_HEADER_SIZE"
 
@@ -141,6 +190,15 @@ const (
 
 	// DefaultGRPCPort is 9190
 	DefaultGRPCPort = 9190
+
+	// DefaultMaxSignedBodySize is 6MB
+	DefaultMaxSignedBodySize = 6 * 1024 * 1024
+
+	// DefaultWebSocketIdleTimeout is 5 minutes
+	DefaultWebSocketIdleTimeout = 5 * time.Minute
+
+	// DefaultMaxInvokeDelay is 7 days
+	DefaultMaxInvokeDelay = 7 * 24 * time.Hour
 )
 
 // NodeType is the mode to run fn in.
@@ -195,8 +253,19 @@ type Server struct {
 
 	agent     agent.Agent
 	datastore models.Datastore
+	callStore models.CallStore
+	logStore  models.LogStore
+	leaser    models.Leaser
+	mq        models.MQ
 	nodeType  NodeType
 
+	// logStoreFromDB is set if logStore is the datastore, by default
+	logStoreFromDB bool
+
+	// triggerSource is where the scheduler of LB nodes, which have no
+	// datastore, reads schedule triggers from
+	triggerSource scheduler.TriggerSource
+
 	// Service Settings for Admin/Web/gRPC. Note that for gRPC only
 	// TLSConfig and Addr are transferrable from http.Server to GRPC service.
 	// TODO: extend this to cover gRPC options.
@@ -208,6 +277,7 @@ type Server struct {
 	noProfilerEndpoint     bool
 	noWebServer            bool
 	noAdminServer          bool
+	noScheduler            bool
 	appListeners           *appListeners
 	fnListeners            *fnListeners
 	triggerListeners       *triggerListeners
@@ -215,7 +285,27 @@ type Server struct {
 	apiMiddlewares         []fnext.Middleware
 	promExporter           *prometheus.Exporter
 	triggerAnnotator       TriggerAnnotator
+	triggerTypes           map[string]bool
+	triggerTypeAnnotators  map[string]TriggerAnnotator
+	triggerDispatchers     []fnext.TriggerDispatcher
+	asyncRunner            *async.Runner
 	fnAnnotator            FnAnnotator
+	jwtVerifier            *jwt.Verifier
+	secretStore            models.SecretStore
+
+	// hostnames the server answers on, which the domains of apps can't take
+	hostnames map[string]bool
+
+	// maxSignedBodySize caps the bodies of requests to signed triggers, which
+	// are held in memory until their signatures are verified
+	maxSignedBodySize int64
+
+	// wsIdleTimeout closes websocket connections to http triggers that go
+	// quiet for longer, clients that want to hold them should ping
+	wsIdleTimeout time.Duration
+
+	// maxInvokeDelay is the longest a detached call may wait on the queue
+	maxInvokeDelay time.Duration
 
 	// Extensions can append to this list of contexts so that cancellations are properly handled.
 	extraCtxs []context.Context
@@ -251,12 +341,30 @@ func NewFromEnv(ctx context.Context, opts ...Option) *Server {
 	opts = append(opts, WithZipkin(getEnv(EnvZipkinURL, "")))
 	opts = append(opts, WithJaeger(getEnv(EnvJaegerURL, "")))
 	opts = append(opts, WithPrometheus()) // TODO option to turn this off?
+	opts = append(opts, WithCallStoreURL(getEnv(EnvCallStoreURL, "")))
+	opts = append(opts, WithLogStoreURL(getEnv(EnvLogStoreURL, "")))
+	opts = append(opts, WithMQURL(getEnv(EnvMQURL, "")))
 	opts = append(opts, WithDBURL(getEnv(EnvDBURL, defaultDB)))
 	opts = append(opts, WithType(nodeType))
 
 	opts = append(opts, LimitRequestBody(int64(getEnvInt(EnvMaxRequestSize, 0))))
+	opts = append(opts, WithMaxSignedBodySize(int64(getEnvInt(EnvMaxSignedBodySize, DefaultMaxSignedBodySize))))
+	opts = append(opts, WithWebSocketIdleTimeout(getEnvDuration(EnvWebSocketIdleTimeout, DefaultWebSocketIdleTimeout)))
+	opts = append(opts, WithMaxInvokeDelay(getEnvDuration(EnvMaxInvokeDelay, DefaultMaxInvokeDelay)))
+
+	if keyFiles := getEnv(EnvJWTKeys, ""); keyFiles != "" {
+		opts = append(opts, WithJWTKeyFiles(getEnv(EnvJWTIssuer, ""), strings.Split(keyFiles, ",")...))
+	}
+
+	if keyFile := getEnv(EnvSecretKeyFile, ""); keyFile != "" {
+		opts = append(opts, WithSecretKeyFile(keyFile))
+	}
 
 	publicLBURL := getEnv(EnvPublicLoadBalancerURL, "")
+	opts = append(opts, WithHostnames(urlHostname(publicLBURL), urlHostname(getEnv(EnvRunnerURL, ""))))
+	if hostname, err := os.Hostname(); err == nil {
+		opts = append(opts, WithHostnames(hostname))
+	}
 	if publicLBURL != "" {
 		logrus.Infof("using LB Base URL: '%s'", publicLBURL)
 		opts = append(opts, WithTriggerAnnotator(NewStaticURLTriggerAnnotator(publicLBURL)))
@@ -338,6 +446,32 @@ func WithDBURL(dbURL string) Option {
 			if err != nil {
 				return err
 			}
+			// record calls and their logs alongside everything else unless told otherwise
+			if cs, ok := ds.(models.CallStore); ok && s.callStore == nil {
+				err = WithCallStore(cs)(ctx, s)
+				if err != nil {
+					return err
+				}
+			}
+			if ls, ok := ds.(models.LogStore); ok && s.logStore == nil {
+				err = WithLogStore(ls)(ctx, s)
+				if err != nil {
+					return err
+				}
+				s.logStoreFromDB = true
+			}
+			if l, ok := ds.(models.Leaser); ok && s.leaser == nil {
+				err = WithLeaser(l)(ctx, s)
+				if err != nil {
+					return err
+				}
+			}
+			if mq, ok := ds.(models.MQ); ok && s.mq == nil {
+				err = WithMQ(mq)(ctx, s)
+				if err != nil {
+					return err
+				}
+			}
 			return WithDatastore(ds)(ctx, s)
 		}
 		return nil
@@ -381,6 +515,85 @@ func WithDatastore(ds models.Datastore) Option {
 	}
 }
 
+// WithCallStoreURL maps EnvCallStoreURL
+func WithCallStoreURL(csURL string) Option {
+	return func(ctx context.Context, s *Server) error {
+		if csURL != "" {
+			cs, err := callstore.New(ctx, csURL)
+			if err != nil {
+				return err
+			}
+			return WithCallStore(cs)(ctx, s)
+		}
+		return nil
+	}
+}
+
+// WithCallStore allows directly setting a call store, calls run by the agent
+// are recorded in it and may be queried through the API
+func WithCallStore(cs models.CallStore) Option {
+	return func(ctx context.Context, s *Server) error {
+		s.callStore = callstore.Wrap(cs)
+		return nil
+	}
+}
+
+// WithLogStoreURL maps EnvLogStoreURL
+func WithLogStoreURL(lsURL string) Option {
+	return func(ctx context.Context, s *Server) error {
+		if lsURL != "" {
+			ls, err := logs.New(ctx, lsURL)
+			if err != nil {
+				return err
+			}
+			return WithLogStore(ls)(ctx, s)
+		}
+		return nil
+	}
+}
+
+// WithLogStore allows directly setting a log store, the stderr of calls run by
+// the agent is kept in it and may be queried through the API
+func WithLogStore(ls models.LogStore) Option {
+	return func(ctx context.Context, s *Server) error {
+		s.logStore = logs.Wrap(ls)
+		s.logStoreFromDB = false
+		return nil
+	}
+}
+
+// WithLeaser allows directly setting the store used to elect the node which
+// fires schedule triggers, full and LB nodes only run a scheduler when one is set
+func WithLeaser(l models.Leaser) Option {
+	return func(ctx context.Context, s *Server) error {
+		s.leaser = l
+		return nil
+	}
+}
+
+// WithMQURL maps EnvMQURL
+func WithMQURL(mqURL string) Option {
+	return func(ctx context.Context, s *Server) error {
+		if mqURL != "" {
+			mq, err := mqs.New(ctx, mqURL)
+			if err != nil {
+				return err
+			}
+			return WithMQ(mq)(ctx, s)
+		}
+		return nil
+	}
+}
+
+// WithMQ allows directly setting a queue for detached calls, which are then
+// queued when invoked and run by the nodes that can make calls
+func WithMQ(mq models.MQ) Option {
+	return func(ctx context.Context, s *Server) error {
+		s.mq = mqs.Wrap(mq)
+		return nil
+	}
+}
+
 // WithAgent allows directly setting an agent
 func WithAgent(agent agent.Agent) Option {
 	return func(ctx context.Context, s *Server) error {
@@ -401,7 +614,17 @@ func (s *Server) defaultRunnerPool() (pool.RunnerPool, error) {
 func WithFullAgent() Option {
 	return func(ctx context.Context, s *Server) error {
 		s.nodeType = ServerTypeFull
-		s.agent = agent.New()
+		var opts []agent.Option
+		if s.callStore != nil {
+			opts = append(opts, agent.WithCallStore(s.callStore))
+		}
+		if s.logStore != nil {
+			opts = append(opts, agent.WithLogStore(s.logStore))
+		}
+		if s.secretStore != nil {
+			opts = append(opts, agent.WithSecretStore(s.secretStore))
+		}
+		s.agent = agent.New(opts...)
 		return nil
 	}
 }
@@ -452,7 +675,21 @@ func WithAgentFromEnv() Option {
 			if err != nil {
 				return errors.New("LBAgent creation failed")
 			}
-			s.agent, err = agent.NewLBAgent(runnerPool, placer)
+			// LB nodes fire schedule triggers too, electing one through the API nodes
+			if ts, ok := cl.(scheduler.TriggerSource); ok {
+				s.triggerSource = ts
+			}
+			if l, ok := cl.(models.Leaser); ok && s.leaser == nil {
+				s.leaser = l
+			}
+			var lbOpts []agent.LBAgentOption
+			if s.callStore != nil {
+				lbOpts = append(lbOpts, agent.WithLBCallStore(s.callStore))
+			}
+			if s.secretStore != nil {
+				lbOpts = append(lbOpts, agent.WithLBSecretStore(s.secretStore))
+			}
+			s.agent, err = agent.NewLBAgent(runnerPool, placer, lbOpts...)
 			if err != nil {
 				return errors.New("LBAgent creation failed")
 			}
@@ -487,6 +724,66 @@ func WithFnAnnotator(provider FnAnnotator) Option {
 	}
 }
 
+// WithHostnames adds hostnames the server answers on, such as that of its
+// public url, which are then refused as the domains of apps. Empty hostnames
+// are ignored.
+func WithHostnames(hostnames ...string) Option {
+	return func(ctx context.Context, s *Server) error {
+		for _, h := range hostnames {
+			if h != "" {
+				s.hostnames[models.NormalizeHostname(h)] = true
+			}
+		}
+		return nil
+	}
+}
+
+// WithJWTVerifier requires calls to carry a bearer token that v verifies,
+// and that has the audience and claims their app and fn require
+func WithJWTVerifier(v *jwt.Verifier) Option {
+	return func(ctx context.Context, s *Server) error {
+		s.jwtVerifier = v
+		return nil
+	}
+}
+
+// WithJWTKeyFiles maps EnvJWTKeys and EnvJWTIssuer
+func WithJWTKeyFiles(issuer string, paths ...string) Option {
+	return func(ctx context.Context, s *Server) error {
+		keys, err := jwt.LoadKeyFiles(paths...)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			return errors.New("no keys to verify bearer tokens with")
+		}
+		s.jwtVerifier = jwt.NewVerifier(keys, issuer)
+		return nil
+	}
+}
+
+// WithSecretStore encrypts the secret config of apps and fns with ss, and
+// decrypts it for calls if the server has an agent. It must be given before
+// the agent is made.
+func WithSecretStore(ss models.SecretStore) Option {
+	return func(ctx context.Context, s *Server) error {
+		s.secretStore = ss
+		return nil
+	}
+}
+
+// WithSecretKeyFile maps EnvSecretKeyFile
+func WithSecretKeyFile(path string) Option {
+	return func(ctx context.Context, s *Server) error {
+		ss, err := secrets.LoadKeyFile(path)
+		if err != nil {
+			return err
+		}
+		s.secretStore = ss
+		return nil
+	}
+}
+
 // WithAdminServer starts the admin server on the specified port.
 func WithAdminServer(port int) Option {
 	return func(ctx context.Context, s *Server) error {
@@ -527,9 +824,15 @@ func New(ctx context.Context, opts ...Option) *Server {
 			GRPCServer:  &http.Server{},
 		},
 		// MUST initialize these before opts
-		appListeners:     new(appListeners),
-		fnListeners:      new(fnListeners),
-		triggerListeners: new(triggerListeners),
+		appListeners:          new(appListeners),
+		fnListeners:           new(fnListeners),
+		triggerListeners:      new(triggerListeners),
+		triggerTypes:          make(map[string]bool),
+		triggerTypeAnnotators: make(map[string]TriggerAnnotator),
+		hostnames:             make(map[string]bool),
+		maxSignedBodySize:     DefaultMaxSignedBodySize,
+		wsIdleTimeout:         DefaultWebSocketIdleTimeout,
+		maxInvokeDelay:        DefaultMaxInvokeDelay,
 
 		// Almost everything else is configured through opts (see NewFromEnv for ex.) or below
 	}
@@ -553,6 +856,9 @@ func New(ctx context.Context, opts ...Option) *Server {
 	if s.svcConfigs[GRPCServer].Addr == "" {
 		s.svcConfigs[GRPCServer].Addr = fmt.Sprintf(":%d", DefaultGRPCPort)
 	}
+	if host, _, err := net.SplitHostPort(s.svcConfigs[WebServer].Addr); err == nil && host != "" {
+		s.hostnames[models.NormalizeHostname(host)] = true
+	}
 
 	requireConfigSet := func(id string, val interface{}) {
 		if val == nil {
@@ -591,6 +897,30 @@ func New(ctx context.Context, opts ...Option) *Server {
 
 	}
 
+	// triggers are advertised on the custom domains of their apps too
+	if s.triggerAnnotator != nil && s.datastore != nil {
+		s.triggerAnnotator = &domainTriggerAnnotator{ds: s.datastore, next: s.triggerAnnotator}
+	}
+
+	// trigger types added by extensions annotate their own triggers, even
+	// those added before the annotator of the server was set
+	if s.triggerAnnotator != nil {
+		s.triggerAnnotator = &typedTriggerAnnotator{annotators: s.triggerTypeAnnotators, next: s.triggerAnnotator}
+	}
+
+	// nodes that can make calls elect one of their number to fire schedules
+	if s.datastore != nil {
+		s.triggerSource = s.datastore
+	}
+	if (s.nodeType == ServerTypeFull || s.nodeType == ServerTypeLB) && s.triggerSource != nil && s.leaser != nil && !s.noScheduler {
+		s.triggerDispatchers = append(s.triggerDispatchers, scheduler.New(s.triggerSource, s.leaser))
+	}
+
+	// any node that can make calls takes its share of queued calls
+	if (s.nodeType == ServerTypeFull || s.nodeType == ServerTypeLB) && s.mq != nil {
+		s.asyncRunner = async.NewRunner(s.mq, s.lbReadAccess, s.agent, async.DefaultConfig())
+	}
+
 	s.Router.Use(loggerWrap, traceWrap) // TODO should be opts
 	optionalCorsWrap(s.Router)          // TODO should be an opt
 	apiMetricsWrap(s)
@@ -648,6 +978,14 @@ func WithoutHTTPTriggerEndpoints() Option {
 	}
 }
 
+// WithoutScheduler disables firing schedule triggers from a full or LB node, other nodes sharing its datastore may still fire them
+func WithoutScheduler() Option {
+	return func(ctx context.Context, s *Server) error {
+		s.noScheduler = true
+		return nil
+	}
+}
+
 // WithoutFnInvokeEndpoints optionally disables the fn direct invoke endpoints from a LB -supporting server, allowing extensions to replace them with their own versions
 func WithoutFnInvokeEndpoints() Option {
 	return func(ctx context.Context, s *Server) error {
@@ -916,6 +1254,12 @@ func (s *Server) startGears(ctx context.Context, cancel context.CancelFunc) {
 		}()
 	}
 
+	if err := s.startTriggerDispatchers(ctx); err != nil {
+		logrus.WithError(err).Error("failed to start trigger dispatchers")
+		cancel()
+	}
+	s.startAsyncRunner(ctx)
+
 	// listening for signals or listener errors or cancellations on all registered contexts.
 	s.extraCtxs = append(s.extraCtxs, ctx)
 	cases := make([]reflect.SelectCase, len(s.extraCtxs))
@@ -941,6 +1285,9 @@ func (s *Server) startGears(ctx context.Context, cancel context.CancelFunc) {
 		}
 	}
 
+	s.stopTriggerDispatchers(context.Background()) // stop making calls before the agent goes away
+	s.stopAsyncRunner()
+
 	if s.agent != nil {
 		err := s.agent.Close() // after we stop taking requests, wait for all tasks to finish
 		if err != nil {
@@ -959,6 +1306,11 @@ func (s *Server) bindHandlers(ctx context.Context) {
 	// now for extensible middleware
 	engine.Use(s.rootMiddlewareWrapper())
 
+	// requests to the custom domains of apps go to their triggers, whatever the path
+	if (s.nodeType == ServerTypeFull || s.nodeType == ServerTypeLB) && !s.noHTTTPTriggerEndpoint {
+		engine.Use(s.domainRouting)
+	}
+
 	engine.GET("/", handlePing)
 	admin.GET("/version", handleVersion)
 
@@ -990,23 +1342,61 @@ func (s *Server) bindHandlers(ctx context.Context) {
 			v2.GET("/fns/:fn_id", s.handleFnGet)
 			v2.PUT("/fns/:fn_id", s.handleFnUpdate)
 			v2.DELETE("/fns/:fn_id", s.handleFnDelete)
+			v2.GET("/fns/:fn_id/revisions", s.handleFnRevisionList)
+			v2.POST("/fns/:fn_id/rollback", s.handleFnRollback)
 
 			v2.GET("/triggers", s.handleTriggerList)
 			v2.POST("/triggers", s.handleTriggerCreate)
 			v2.GET("/triggers/:trigger_id", s.handleTriggerGet)
 			v2.PUT("/triggers/:trigger_id", s.handleTriggerUpdate)
 			v2.DELETE("/triggers/:trigger_id", s.handleTriggerDelete)
+
+			v2.GET("/domains", s.handleDomainList)
+			v2.POST("/domains", s.handleDomainCreate)
+			v2.GET("/domains/:domain_id", s.handleDomainGet)
+			v2.DELETE("/domains/:domain_id", s.handleDomainDelete)
+
+			v2.GET("/aliases", s.handleAliasList)
+			v2.POST("/aliases", s.handleAliasCreate)
+			v2.GET("/aliases/:alias_id", s.handleAliasGet)
+			v2.PUT("/aliases/:alias_id", s.handleAliasUpdate)
+			v2.DELETE("/aliases/:alias_id", s.handleAliasDelete)
 		}
 
-		// TODO remove these in 30 days or something
-		v2.GET("/fns/:fn_id/calls", s.goneResponse)
-		v2.GET("/fns/:fn_id/calls/:call_id", s.goneResponse)
-		v2.GET("/fns/:fn_id/calls/:call_id/log", s.goneResponse)
+		if s.callStore != nil {
+			v2.GET("/fns/:fn_id/calls", s.handleCallList)
+			v2.GET("/fns/:fn_id/calls/:call_id", s.handleCallGet)
+		} else {
+			v2.GET("/fns/:fn_id/calls", s.goneResponse)
+			v2.GET("/fns/:fn_id/calls/:call_id", s.goneResponse)
+		}
+		switch {
+		case s.logStore == nil:
+			v2.GET("/fns/:fn_id/calls/:call_id/log", s.goneResponse)
+		case s.nodeType == ServerTypeAPI && s.logStoreFromDB:
+			// the calls of API nodes are likely made by LBs, whose runners
+			// don't record logs, unless a log store is set for full nodes
+			v2.GET("/fns/:fn_id/calls/:call_id/log", s.logsNotRecordedResponse)
+		default:
+			v2.GET("/fns/:fn_id/calls/:call_id/log", s.handleCallLogGet)
+		}
 
 		// TODO figure out how to deprecate
 		runner := cleanv2.Group("/runner")
 		runnerAppAPI := runner.Group("/apps/:app_id")
 		runnerAppAPI.GET("/triggerBySource/:trigger_type/*trigger_source", s.handleRunnerGetTriggerBySource)
+		runner.GET("/domainByHostname/:hostname", s.handleRunnerGetDomainByHostname)
+		runnerAppAPI.GET("/aliasByName/:alias_name", s.handleRunnerGetAliasByName)
+		// these carry secret config that is redacted in the API, so they are guarded like it
+		runnerAPI := runner.Group("")
+		runnerAPI.Use(s.apiMiddlewareWrapper())
+		runnerAPI.GET("/apps/:app_id", s.handleRunnerGetApp)
+		runnerAPI.GET("/fns/:fn_id", s.handleRunnerGetFn)
+		// LB nodes elect the one firing schedules through the API nodes
+		if s.leaser != nil {
+			runnerAPI.PUT("/leases/:lease_name", s.handleRunnerAcquireLease)
+			runnerAPI.DELETE("/leases/:lease_name", s.handleRunnerReleaseLease)
+		}
 	}
 
 	switch s.nodeType {
@@ -1020,7 +1410,13 @@ func (s *Server) bindHandlers(ctx context.Context) {
 		if !s.noFnInvokeEndpoint {
 			lbFnInvokeGroup := engine.Group("/invoke")
 			lbFnInvokeGroup.POST("/:fn_id", s.handleFnInvokeCall)
+			lbFnInvokeGroup.POST("/:fn_id/:app_name/:alias_name", s.handleAliasInvokeCall)
 		}
+
+		// calls are cancelled through the agent running them
+		callsGroup := engine.Group("/v2/calls")
+		callsGroup.Use(s.apiMiddlewareWrapper())
+		callsGroup.DELETE("/:call_id", s.handleCallCancel)
 	}
 
 	engine.NoRoute(func(c *gin.Context) {
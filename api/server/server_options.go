@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/fnproject/fn/api/common"
 	"github.com/gin-gonic/gin"
@@ -58,6 +59,32 @@ func LimitRequestBody(max int64) Option {
 	}
 }
 
+// WithMaxSignedBodySize limits the bodies of requests to signed triggers to
+// max bytes, larger ones are refused as they are read whole to verify them.
+func WithMaxSignedBodySize(max int64) Option {
+	return func(ctx context.Context, s *Server) error {
+		s.maxSignedBodySize = max
+		return nil
+	}
+}
+
+// WithWebSocketIdleTimeout closes websocket connections to http triggers
+// that receive no frame from the client for timeout.
+func WithWebSocketIdleTimeout(timeout time.Duration) Option {
+	return func(ctx context.Context, s *Server) error {
+		s.wsIdleTimeout = timeout
+		return nil
+	}
+}
+
+// WithMaxInvokeDelay refuses detached calls that ask to be delayed by more than max.
+func WithMaxInvokeDelay(max time.Duration) Option {
+	return func(ctx context.Context, s *Server) error {
+		s.maxInvokeDelay = max
+		return nil
+	}
+}
+
 func limitRequestBody(max int64) func(c *gin.Context) {
 	return func(c *gin.Context) {
 		cl := int64(c.Request.ContentLength)
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -12,9 +13,12 @@ import (
 
 	"github.com/fnproject/fn/api/agent"
 	_ "github.com/fnproject/fn/api/agent/drivers/docker"
+	_ "github.com/fnproject/fn/api/datastore/sql"
 	_ "github.com/fnproject/fn/api/datastore/sql/sqlite"
 	"github.com/fnproject/fn/api/models"
+	pool "github.com/fnproject/fn/api/runnerpool"
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
 )
 
 func testServer(ds models.Datastore, rnr agent.Agent, nodeType NodeType, opts ...Option) *Server {
@@ -29,6 +33,32 @@ func testServer(ds models.Datastore, rnr agent.Agent, nodeType NodeType, opts ..
 	)...)
 }
 
+// shoutingAgent returns an agent whose calls respond with their body in upper
+// case, and nothing to bodies of "quiet"
+func shoutingAgent(headers chan http.Header) *agent.MockAgent {
+	a := new(agent.MockAgent)
+	a.On("GetCall", mock.Anything).Return()
+	a.On("Submit", mock.Anything).Run(func(args mock.Arguments) {
+		c := args.Get(0).(pool.RunnerCall)
+		headers <- c.Model().Headers
+		body, _ := ioutil.ReadAll(c.RequestBody())
+		if string(body) != "quiet" {
+			c.ResponseWriter().Write(bytes.ToUpper(body))
+		}
+	}).Return(nil)
+	return a
+}
+
+// fnIDAgent sends the id of the fn each call is made to to fnIDs
+func fnIDAgent(fnIDs chan string) *agent.MockAgent {
+	a := new(agent.MockAgent)
+	a.On("GetCall", mock.Anything).Return()
+	a.On("Submit", mock.Anything).Run(func(args mock.Arguments) {
+		fnIDs <- args.Get(0).(pool.RunnerCall).Model().FnID
+	}).Return(nil)
+	return a
+}
+
 func createRequest(t *testing.T, method, path string, body io.Reader) *http.Request {
 
 	bodyLen := int64(0)
@@ -1,17 +1,17 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/fnext"
 	"github.com/gin-gonic/gin"
+	"net/url"
 	"strings"
 )
 
 //TriggerAnnotator Is used to inject trigger context (such as request URLs) into outbound trigger resources
-type TriggerAnnotator interface {
-	// Annotates a trigger on read
-	AnnotateTrigger(ctx *gin.Context, a *models.App, t *models.Trigger) (*models.Trigger, error)
-}
+type TriggerAnnotator = fnext.TriggerAnnotator
 
 type requestBasedTriggerAnnotator struct{}
 
@@ -63,3 +63,47 @@ func (s *staticURLTriggerAnnotator) AnnotateTrigger(ctx *gin.Context, app *model
 	return annotateTriggerWithBaseURL(s.baseURL, app, trigger)
 
 }
+
+// domainTriggerAnnotator adds the URLs of http triggers on the custom domains
+// of their apps to the annotations made by the next annotator. The URLs have
+// the scheme of the trigger endpoint the next annotator made, if any.
+type domainTriggerAnnotator struct {
+	ds   models.Datastore
+	next TriggerAnnotator
+}
+
+func (da *domainTriggerAnnotator) AnnotateTrigger(ctx *gin.Context, app *models.App, t *models.Trigger) (*models.Trigger, error) {
+	t, err := da.next.AnnotateTrigger(ctx, app, t)
+	if err != nil || t.Type != models.TriggerTypeHTTP {
+		return t, err
+	}
+
+	domains, err := da.ds.GetDomains(ctx.Request.Context(), &models.DomainFilter{AppID: app.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(domains.Items) == 0 {
+		return t, nil
+	}
+
+	scheme := "https"
+	var endpoint string
+	if b, ok := t.Annotations.Get(models.TriggerHTTPEndpointAnnotation); ok && json.Unmarshal(b, &endpoint) == nil {
+		if u, err := url.Parse(endpoint); err == nil && u.Scheme != "" {
+			scheme = u.Scheme
+		}
+	}
+
+	urls := make([]string, len(domains.Items))
+	for i, d := range domains.Items {
+		urls[i] = fmt.Sprintf("%s://%s%s", scheme, d.Hostname, t.Source)
+	}
+
+	newT := t.Clone()
+	newAnnotations, err := newT.Annotations.With(models.TriggerHTTPDomainEndpointsAnnotation, urls)
+	if err != nil {
+		return nil, err
+	}
+	newT.Annotations = newAnnotations
+	return newT, nil
+}
@@ -24,6 +24,11 @@ func (s *Server) handleTriggerCreate(c *gin.Context) {
 		return
 	}
 
+	if err := s.sealTriggerSignature(ctx, trigger, nil); err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
 	triggerCreated, err := s.datastore.InsertTrigger(ctx, trigger)
 	if err != nil {
 		handleErrorResponse(c, err)
@@ -33,16 +38,16 @@ func (s *Server) handleTriggerCreate(c *gin.Context) {
 	app, err := s.datastore.GetAppByID(ctx, triggerCreated.AppID)
 	if err != nil {
 		log.Debugln(fmt.Errorf("unexpected error - trigger app not available: %s", err))
-		c.JSON(http.StatusOK, triggerCreated)
+		c.JSON(http.StatusOK, triggerCreated.Redacted())
 		return
 	}
 
 	triggerAnnotated, err := s.triggerAnnotator.AnnotateTrigger(c, app, triggerCreated)
 	if err != nil {
 		log.Debugln("Failed to annotate trigger on cration")
-		c.JSON(http.StatusOK, triggerCreated)
+		c.JSON(http.StatusOK, triggerCreated.Redacted())
 		return
 	}
 
-	c.JSON(http.StatusOK, triggerAnnotated)
+	c.JSON(http.StatusOK, triggerAnnotated.Redacted())
 }
@@ -30,5 +30,5 @@ func (s *Server) handleTriggerGet(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, trigger)
+	c.JSON(http.StatusOK, trigger.Redacted())
 }
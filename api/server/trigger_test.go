@@ -55,6 +55,7 @@ func TestTriggerCreate(t *testing.T) {
 		{commonDS, BaseRoute, `{"app_id":"appid", "fn_id":"fnid", "name": "Test" }`, http.StatusBadRequest, models.ErrTriggerTypeUnknown},
 		{commonDS, BaseRoute, `{ "name": "Test", "app_id": "appid", "fn_id": "fnid", "type":"http"}`, http.StatusBadRequest, models.ErrTriggerMissingSource},
 		{commonDS, BaseRoute, `{ "name": "Test", "app_id": "appid", "fn_id": "fnid", "type":"http", "source":"src"}`, http.StatusBadRequest, models.ErrTriggerMissingSourcePrefix},
+		{commonDS, BaseRoute, `{ "name": "Test", "app_id": "appid", "fn_id": "fnid", "type":"schedule", "source":"/src"}`, http.StatusBadRequest, models.ErrTriggerInvalidSchedule},
 
 		{commonDS, BaseRoute, fmt.Sprintf(`{ "name": "%s", "app_id": "appid", "fn_id": "fnid", "type":"http"}`, tooLongName), http.StatusBadRequest, models.ErrTriggerTooLongName},
 		{commonDS, BaseRoute, `{ "name": "&&%@!#$#@$","app_id": "appid", "fn_id": "fnid", "type":"http" }`, http.StatusBadRequest, models.ErrTriggerInvalidName},
@@ -66,6 +67,7 @@ func TestTriggerCreate(t *testing.T) {
 
 		// // success
 		{commonDS, BaseRoute, `{ "name": "trigger", "app_id": "appid", "fn_id": "fnid", "type": "http", "source": "/src"}`, http.StatusOK, nil},
+		{commonDS, BaseRoute, `{ "name": "nightly", "app_id": "appid", "fn_id": "fnid", "type": "schedule", "source": "0 2 * * *"}`, http.StatusOK, nil},
 
 		//repeated name
 		{commonDS, BaseRoute, `{ "name": "trigger", "app_id": "appid", "fn_id": "fnid", "type": "http", "source": "/src"}`, http.StatusConflict, nil},
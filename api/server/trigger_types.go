@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/fnext"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// typedTriggerAnnotator annotates triggers of the types added to the server
+// with the annotators of those types, handing all others to the next annotator
+type typedTriggerAnnotator struct {
+	annotators map[string]TriggerAnnotator
+	next       TriggerAnnotator
+}
+
+func (ta *typedTriggerAnnotator) AnnotateTrigger(ctx *gin.Context, app *models.App, t *models.Trigger) (*models.Trigger, error) {
+	if a, ok := ta.annotators[t.Type]; ok {
+		return a.AnnotateTrigger(ctx, app, t)
+	}
+	return ta.next.AnnotateTrigger(ctx, app, t)
+}
+
+var (
+	addedTriggerTypesLock sync.Mutex
+	// addedTriggerTypes are the trigger types servers of this process have
+	// registered, which the servers share
+	addedTriggerTypes = make(map[string]bool)
+)
+
+// AddTriggerType implements fnext.ExtServer. The annotators of trigger types
+// are consulted ahead of the annotator of the server, however the two were
+// configured. Trigger types are registered for the whole process, so a type
+// another server added already is not registered again, and its sources are
+// validated as they were for the first server.
+func (s *Server) AddTriggerType(tt fnext.TriggerType) error {
+	if s.triggerTypes[tt.Name] {
+		return models.ErrTriggerTypeExists
+	}
+
+	addedTriggerTypesLock.Lock()
+	defer addedTriggerTypesLock.Unlock()
+	if !addedTriggerTypes[tt.Name] {
+		err := models.RegisterTriggerType(tt.Name, tt.ValidateSource)
+		if err != nil {
+			return err
+		}
+		addedTriggerTypes[tt.Name] = true
+	}
+
+	s.triggerTypes[tt.Name] = true
+	if tt.Annotator != nil {
+		s.triggerTypeAnnotators[tt.Name] = tt.Annotator
+	}
+	if tt.Dispatcher != nil {
+		s.triggerDispatchers = append(s.triggerDispatchers, tt.Dispatcher)
+	}
+	return nil
+}
+
+// InvokeTrigger implements fnext.Invoker
+func (s *Server) InvokeTrigger(w http.ResponseWriter, req *http.Request, t *models.Trigger) error {
+	ctx := req.Context()
+	app, err := s.lbReadAccess.GetAppByID(ctx, t.AppID)
+	if err != nil {
+		return err
+	}
+	fnID, _, err := s.triggerFnID(ctx, t, req.Method)
+	if err != nil {
+		return err
+	}
+	fn, err := s.lbReadAccess.GetFnByID(ctx, fnID)
+	if err != nil {
+		return err
+	}
+	return s.fnInvoke(w, req, app, s.routeTraffic(req, fn), t)
+}
+
+// startTriggerDispatchers starts dispatching triggers on nodes which can make calls
+func (s *Server) startTriggerDispatchers(ctx context.Context) error {
+	if s.nodeType != ServerTypeFull && s.nodeType != ServerTypeLB {
+		return nil
+	}
+	for _, d := range s.triggerDispatchers {
+		if err := d.Start(ctx, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) stopTriggerDispatchers(ctx context.Context) {
+	if s.nodeType != ServerTypeFull && s.nodeType != ServerTypeLB {
+		return
+	}
+	for _, d := range s.triggerDispatchers {
+		if err := d.Stop(ctx); err != nil {
+			logrus.WithError(err).Error("failed to stop trigger dispatcher")
+		}
+	}
+}
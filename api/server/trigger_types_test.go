@@ -0,0 +1,183 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fnproject/fn/api/datastore"
+	"github.com/fnproject/fn/api/models"
+	"github.com/fnproject/fn/fnext"
+	"github.com/gin-gonic/gin"
+)
+
+const testQueueAnnotation = "example.com/queue"
+
+type testQueueAnnotator struct{}
+
+func (testQueueAnnotator) AnnotateTrigger(ctx *gin.Context, app *models.App, t *models.Trigger) (*models.Trigger, error) {
+	newT := t.Clone()
+	annotations, err := newT.Annotations.With(testQueueAnnotation, strings.TrimPrefix(t.Source, "queue:"))
+	if err != nil {
+		return nil, err
+	}
+	newT.Annotations = annotations
+	return newT, nil
+}
+
+type testDispatcher struct {
+	invoker fnext.Invoker
+	stopped bool
+}
+
+func (d *testDispatcher) Start(ctx context.Context, invoker fnext.Invoker) error {
+	d.invoker = invoker
+	return nil
+}
+
+func (d *testDispatcher) Stop(ctx context.Context) error {
+	d.stopped = true
+	return nil
+}
+
+func TestAddTriggerType(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	a := &models.App{ID: "appid", Name: "myapp"}
+	fn := &models.Fn{ID: "fnid", AppID: a.ID}
+	fn.SetDefaults()
+	ds := datastore.NewMockInit([]*models.App{a}, []*models.Fn{fn})
+
+	srv := testServer(ds, nil, ServerTypeAPI)
+
+	errNotAQueue := models.NewAPIError(http.StatusBadRequest, errors.New("source must be a queue"))
+	dispatcher := &testDispatcher{}
+	queueType := fnext.TriggerType{
+		Name: "test-server-queue",
+		ValidateSource: func(source string) error {
+			if !strings.HasPrefix(source, "queue:") {
+				return errNotAQueue
+			}
+			return nil
+		},
+		Annotator:  testQueueAnnotator{},
+		Dispatcher: dispatcher,
+	}
+
+	if err := srv.AddTriggerType(queueType); err != nil {
+		t.Fatalf("unexpected error adding trigger type: %v", err)
+	}
+	if err := srv.AddTriggerType(queueType); err != models.ErrTriggerTypeExists {
+		t.Fatalf("expected adding a trigger type twice to fail, got %v", err)
+	}
+	if err := srv.AddTriggerType(fnext.TriggerType{Name: models.TriggerTypeHTTP}); err != models.ErrTriggerTypeExists {
+		t.Fatalf("expected adding a builtin trigger type to fail, got %v", err)
+	}
+
+	// the types of a process are shared, other servers may add them too
+	other := testServer(ds, nil, ServerTypeAPI)
+	if err := other.AddTriggerType(queueType); err != nil {
+		t.Fatalf("expected another server to add the trigger type, got %v", err)
+	}
+	if other.triggerTypeAnnotators[queueType.Name] == nil || len(other.triggerDispatchers) != 1 {
+		t.Fatal("expected the annotator and dispatcher of the trigger type to be added to the other server")
+	}
+
+	body := `{ "name": "orders", "app_id": "appid", "fn_id": "fnid", "type": "test-server-queue", "source": "/orders"}`
+	_, rec := routerRequest(t, srv.Router, "POST", BaseRoute, bytes.NewBufferString(body))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected invalid source to be rejected, got %d", rec.Code)
+	}
+	if resp := getErrorResponse(t, rec); resp.Message != errNotAQueue.Error() {
+		t.Fatalf("expected the error of the source validator, got %q", resp.Message)
+	}
+
+	body = `{ "name": "orders", "app_id": "appid", "fn_id": "fnid", "type": "test-server-queue", "source": "queue:orders"}`
+	_, rec = routerRequest(t, srv.Router, "POST", BaseRoute, bytes.NewBufferString(body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected trigger of added type to be created, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var trigger models.Trigger
+	if err := json.NewDecoder(rec.Body).Decode(&trigger); err != nil {
+		t.Fatal(err)
+	}
+	if queue, err := trigger.Annotations.GetString(testQueueAnnotation); err != nil || queue != "orders" {
+		t.Fatalf("expected trigger to be annotated by its type's annotator, got %v", trigger.Annotations)
+	}
+
+	// other types are still annotated as before
+	body = `{ "name": "web", "app_id": "appid", "fn_id": "fnid", "type": "http", "source": "/web"}`
+	_, rec = routerRequest(t, srv.Router, "POST", BaseRoute, bytes.NewBufferString(body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected http trigger to be created, got %d: %s", rec.Code, rec.Body.String())
+	}
+	trigger = models.Trigger{}
+	if err := json.NewDecoder(rec.Body).Decode(&trigger); err != nil {
+		t.Fatal(err)
+	}
+	if endpoint, err := trigger.Annotations.GetString(models.TriggerHTTPEndpointAnnotation); err != nil || endpoint == "" {
+		t.Fatalf("expected http trigger to have an endpoint annotation, got %v", trigger.Annotations)
+	}
+
+	// api nodes can't make calls, so don't dispatch
+	if err := srv.startTriggerDispatchers(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if dispatcher.invoker != nil {
+		t.Fatal("expected dispatcher not to be started on an api node")
+	}
+
+	srv.nodeType = ServerTypeFull
+	if err := srv.startTriggerDispatchers(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if dispatcher.invoker != srv {
+		t.Fatal("expected dispatcher to be started with the server as its invoker")
+	}
+	srv.stopTriggerDispatchers(context.Background())
+	if !dispatcher.stopped {
+		t.Fatal("expected dispatcher to be stopped")
+	}
+}
+
+func TestAddTriggerTypeBeforeAnnotator(t *testing.T) {
+	buf := setLogBuffer()
+	defer func() {
+		if t.Failed() {
+			t.Log(buf.String())
+		}
+	}()
+
+	a := &models.App{ID: "appid", Name: "myapp"}
+	fn := &models.Fn{ID: "fnid", AppID: a.ID}
+	fn.SetDefaults()
+	ds := datastore.NewMockInit([]*models.App{a}, []*models.Fn{fn})
+
+	// the type is added by an option that runs before the server's annotator is set
+	addType := func(ctx context.Context, s *Server) error {
+		return s.AddTriggerType(fnext.TriggerType{Name: "test-early-queue", Annotator: testQueueAnnotator{}})
+	}
+	srv := testServer(ds, nil, ServerTypeAPI, addType)
+
+	body := `{ "name": "orders", "app_id": "appid", "fn_id": "fnid", "type": "test-early-queue", "source": "queue:orders"}`
+	_, rec := routerRequest(t, srv.Router, "POST", BaseRoute, bytes.NewBufferString(body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected trigger of added type to be created, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var trigger models.Trigger
+	if err := json.NewDecoder(rec.Body).Decode(&trigger); err != nil {
+		t.Fatal(err)
+	}
+	if queue, err := trigger.Annotations.GetString(testQueueAnnotation); err != nil || queue != "orders" {
+		t.Fatalf("expected trigger to be annotated by its type's annotator, got %v", trigger.Annotations)
+	}
+}
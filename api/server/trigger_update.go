@@ -32,11 +32,25 @@ func (s *Server) handleTriggerUpdate(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
+	// a redacted secret keeps the one stored
+	var stored *models.Trigger
+	if trigger.Signature != nil && trigger.Signature.Secret == models.RedactedSecret {
+		stored, err = s.datastore.GetTriggerByID(ctx, trigger.ID)
+		if err != nil {
+			handleErrorResponse(c, err)
+			return
+		}
+	}
+	if err := s.sealTriggerSignature(ctx, trigger, stored); err != nil {
+		handleErrorResponse(c, err)
+		return
+	}
+
 	triggerUpdated, err := s.datastore.UpdateTrigger(ctx, trigger)
 	if err != nil {
 		handleErrorResponse(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, triggerUpdated)
+	c.JSON(http.StatusOK, triggerUpdated.Redacted())
 }
@@ -0,0 +1,256 @@
+package server
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fnproject/fn/api/models"
+)
+
+// just enough of RFC 6455 to hold websocket connections for http triggers:
+// no extensions or subprotocols, and whole messages only.
+
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xa
+
+	wsCloseNormal      = 1000
+	wsCloseGoingAway   = 1001
+	wsCloseProtocol    = 1002
+	wsCloseTooBig      = 1009
+	wsCloseServerError = 1011
+
+	// wsMaxMessageSize is the largest message read from a client
+	wsMaxMessageSize = 1 << 20
+
+	// wsWriteTimeout bounds the time to send a frame to a client
+	wsWriteTimeout = 10 * time.Second
+
+	wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+var (
+	errWSClosed   = errors.New("websocket closed")
+	errWSProtocol = errors.New("websocket protocol error")
+	errWSTooBig   = errors.New("websocket message too big")
+	errWSIdle     = errors.New("websocket idle for too long")
+)
+
+// isWebSocketUpgrade tells if req asks to be upgraded to a websocket
+func isWebSocketUpgrade(req *http.Request) bool {
+	return headerHasToken(req.Header, "Connection", "upgrade") && headerHasToken(req.Header, "Upgrade", "websocket")
+}
+
+func headerHasToken(h http.Header, key, token string) bool {
+	for _, v := range h[http.CanonicalHeaderKey(key)] {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type wsConn struct {
+	conn        net.Conn
+	br          *bufio.Reader
+	wlock       sync.Mutex
+	idleTimeout time.Duration
+}
+
+// upgradeWebSocket completes the opening handshake of an upgrade request and
+// takes the connection over from the http server. Reading a message fails
+// with errWSIdle once the client sends no frame for idleTimeout.
+func upgradeWebSocket(w http.ResponseWriter, req *http.Request, idleTimeout time.Duration) (*wsConn, error) {
+	key := req.Header.Get("Sec-WebSocket-Key")
+	if req.Method != http.MethodGet || key == "" || req.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, models.ErrInvalidWebSocketUpgrade
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("response writer does not support hijacking")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	// deadlines of the http server don't fit a connection held open, set our own
+	conn.SetDeadline(time.Time{})
+
+	sum := sha1.Sum([]byte(key + wsAcceptGUID))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: ")
+	rw.WriteString(base64.StdEncoding.EncodeToString(sum[:]))
+	rw.WriteString("\r\n\r\n")
+	conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, br: rw.Reader, idleTimeout: idleTimeout}, nil
+}
+
+// readMessage reads the next text or binary message, answering any control
+// frames on the way. Once the client closes the connection errWSClosed is
+// returned.
+func (ws *wsConn) readMessage() (int, []byte, error) {
+	var op int
+	var msg []byte
+	for {
+		// any frame, pings included, keeps the connection from going idle
+		if ws.idleTimeout > 0 {
+			ws.conn.SetReadDeadline(time.Now().Add(ws.idleTimeout))
+		}
+		fin, fop, payload, err := ws.readFrame()
+		if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+			return 0, nil, errWSIdle
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch fop {
+		case wsOpPing:
+			if err := ws.writeFrame(wsOpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			ws.close(wsCloseNormal, "")
+			return 0, nil, errWSClosed
+		case wsOpText, wsOpBinary:
+			if op != 0 {
+				return 0, nil, errWSProtocol
+			}
+			op = fop
+		case wsOpContinuation:
+			if op == 0 {
+				return 0, nil, errWSProtocol
+			}
+		default:
+			return 0, nil, errWSProtocol
+		}
+
+		if len(msg)+len(payload) > wsMaxMessageSize {
+			return 0, nil, errWSTooBig
+		}
+		msg = append(msg, payload...)
+		if fin {
+			return op, msg, nil
+		}
+	}
+}
+
+func (ws *wsConn) readFrame() (fin bool, op int, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err = io.ReadFull(ws.br, hdr[:]); err != nil {
+		return
+	}
+	fin = hdr[0]&0x80 != 0
+	op = int(hdr[0] & 0x0f)
+	masked := hdr[1]&0x80 != 0
+	size := uint64(hdr[1] & 0x7f)
+
+	// clients must mask frames, and may not use extension bits
+	if !masked || hdr[0]&0x70 != 0 {
+		err = errWSProtocol
+		return
+	}
+	if op >= wsOpClose && (!fin || size > 125) {
+		err = errWSProtocol
+		return
+	}
+
+	switch size {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(ws.br, ext[:]); err != nil {
+			return
+		}
+		size = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(ws.br, ext[:]); err != nil {
+			return
+		}
+		size = binary.BigEndian.Uint64(ext[:])
+	}
+	if size > wsMaxMessageSize {
+		err = errWSTooBig
+		return
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(ws.br, mask[:]); err != nil {
+		return
+	}
+	payload = make([]byte, size)
+	if _, err = io.ReadFull(ws.br, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return
+}
+
+// writeMessage sends a whole message to the client in a single frame
+func (ws *wsConn) writeMessage(op int, msg []byte) error {
+	return ws.writeFrame(op, msg)
+}
+
+func (ws *wsConn) writeFrame(op int, payload []byte) error {
+	ws.wlock.Lock()
+	defer ws.wlock.Unlock()
+
+	// servers don't mask frames
+	hdr := make([]byte, 2, 10)
+	hdr[0] = 0x80 | byte(op)
+	switch n := len(payload); {
+	case n <= 125:
+		hdr[1] = byte(n)
+	case n <= 0xffff:
+		hdr[1] = 126
+		hdr = hdr[:4]
+		binary.BigEndian.PutUint16(hdr[2:], uint16(n))
+	default:
+		hdr[1] = 127
+		hdr = hdr[:10]
+		binary.BigEndian.PutUint64(hdr[2:], uint64(n))
+	}
+	ws.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if _, err := ws.conn.Write(hdr); err != nil {
+		return err
+	}
+	_, err := ws.conn.Write(payload)
+	return err
+}
+
+// close sends a close frame with code and reason, then closes the connection
+func (ws *wsConn) close(code int, reason string) error {
+	// control frames carry at most 125 bytes
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	payload := make([]byte, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	copy(payload[2:], reason)
+	ws.writeFrame(wsOpClose, payload)
+	return ws.conn.Close()
+}
@@ -182,6 +182,21 @@ func (e *extds) UpdateFn(ctx context.Context, fn *models.Fn) (*models.Fn, error)
 
 }
 
+// RollbackFn is an update of the fn, of which listeners only see the outcome
+// as what it is updated to is not known until the revision is read
+func (e *extds) RollbackFn(ctx context.Context, fnID, revisionID string) (*models.Fn, error) {
+	f, err := e.Datastore.RollbackFn(ctx, fnID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = e.fl.AfterFnUpdate(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
 func (e *extds) RemoveFn(ctx context.Context, fnID string) error {
 	err := e.fl.BeforeFnDelete(ctx, fnID)
 
@@ -35,6 +35,10 @@ type ExtServer interface {
 	// AddEndpoint adds an endpoint to /v2/x
 	AddEndpointFunc(method, path string, handler func(w http.ResponseWriter, r *http.Request))
 
+	// AddTriggerType adds a new kind of trigger, it fails if the type is already in use on
+	// the server. Servers of one process may each add the same type.
+	AddTriggerType(triggerType TriggerType) error
+
 	// Datastore returns the Datastore Fn is using
 	Datastore() models.Datastore
 }
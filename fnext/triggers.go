@@ -0,0 +1,55 @@
+package fnext
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fnproject/fn/api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// TriggerType describes a kind of trigger, which extensions may add to fn
+// alongside the builtin http and schedule triggers. Only Name is required.
+type TriggerType struct {
+	// Name is the type of triggers of this kind, it must not already be in use.
+	Name string
+
+	// ValidateSource checks the source of a trigger of this kind when it is
+	// created or updated. It should return a models.APIError for invalid
+	// sources. If nil, any non empty source is accepted.
+	ValidateSource models.TriggerSourceValidator
+
+	// Annotator is used to add information, e.g. where to send events, to
+	// triggers of this kind as they are returned by the API.
+	Annotator TriggerAnnotator
+
+	// Dispatcher receives events for triggers of this kind and calls their fns.
+	Dispatcher TriggerDispatcher
+}
+
+// TriggerAnnotator is used to inject trigger context (such as request URLs)
+// into outbound trigger resources
+type TriggerAnnotator interface {
+	// Annotates a trigger on read
+	AnnotateTrigger(ctx *gin.Context, a *models.App, t *models.Trigger) (*models.Trigger, error)
+}
+
+// TriggerDispatcher turns events from some source into calls for triggers.
+// Dispatchers run on nodes that can make calls, i.e. full and lb nodes.
+type TriggerDispatcher interface {
+	// Start is called once as the server starts, calls are made using invoker
+	// until Stop is called. ctx is done when the server starts to shut down.
+	Start(ctx context.Context, invoker Invoker) error
+
+	// Stop is called as the server shuts down, before it stops running calls.
+	// It may be called without Start having been called, or having succeeded.
+	Stop(ctx context.Context) error
+}
+
+// Invoker makes calls to the fns of triggers
+type Invoker interface {
+	// InvokeTrigger calls the fn of t with req, writing the response to w. If
+	// req has the header `Fn-Invoke-Type: detached` the call is detached, and
+	// w only receives an acknowledgement.
+	InvokeTrigger(w http.ResponseWriter, req *http.Request, t *models.Trigger) error
+}